@@ -13,6 +13,8 @@ import (
 	"github.com/gorilla/mux"
 
 	dbSqlc "github.com/DraconDev/go-templ-htmx-ex/database/sqlc"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/authms"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/mailer"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/admin"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/auth/login"
@@ -20,11 +22,15 @@ import (
 	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/dashboard"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/payment"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/settings"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/shop"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/middleware"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/repositories"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/routes"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/config"
 	database "github.com/DraconDev/go-templ-htmx-ex/internal/utils/database"
+	"github.com/dracondev/go-templ-htmx-ex/libs/cachex"
 	_ "github.com/lib/pq"
 )
 
@@ -36,10 +42,15 @@ var sessionHandler *session.SessionHandler
 var paymentHandler *payment.PaymentHandler
 var dashboardHandler *dashboard.DashboardHandler
 var settingsHandler *settings.SettingsHandler
+var webhookHandler *payment.WebhookHandler
+var shopHandler *shop.ShopHandler
 
 func main() {
 	// Load configuration
 	cfg := config.LoadConfig()
+	if cfg.SessionSecret == "change-me-in-production" {
+		log.Println("⚠️  SESSION_SECRET is the default value - set a random secret in production (it signs CSRF tokens and OAuth state; rotating it fails logins in progress)")
+	}
 
 	// Initialize database if configured
 	if err := database.InitDatabaseIfConfigured(); err != nil {
@@ -75,9 +86,16 @@ func main() {
 		log.Println("✅ Database services ready")
 	}
 
+	// Initialize Payment MS Client
+	paymentClient := paymentms.New(cfg.PaymentServiceURL, cfg.PaymentServiceAPIKey).WithProject(cfg.PaymentProjectID)
+	log.Println("✅ Payment MS Client initialized")
+
+	// Roles are shared by the admin API and permission checks, which cache them
+	roleService := services.NewRoleService(queries)
+
 	// Create handlers with services
 	if queries != nil {
-		adminHandler = admin.NewAdminHandler(cfg, queries)
+		adminHandler = admin.NewAdminHandler(cfg, queries, roleService, paymentClient)
 	} else {
 		log.Println("⚠️  Admin handler not initialized - no database connection")
 	}
@@ -85,29 +103,111 @@ func main() {
 	// Initialize repositories
 	userRepo := repositories.NewUserRepository(queries)
 	prefsRepo := repositories.NewPreferencesRepository(queries)
+	sessionRepo := repositories.NewSessionRepository(queries)
+	paymentEventRepo := repositories.NewPaymentEventRepository(queries)
+	subscriptionRepo := repositories.NewSubscriptionRepository(queries)
+	cartRepo := repositories.NewCartRepository(queries)
+	purchaseRepo := repositories.NewPurchaseRepository(queries)
 	log.Println("✅ Repositories initialized")
 
+	// Admin permissions come from assigned roles when a database is available
+	if queries != nil {
+		middleware.PermissionChecker = middleware.DatabasePermissionChecker(roleService)
+
+		// Keep the active sessions list's last-seen times current
+		middleware.OnSessionValidated = func(r *http.Request, sessionID string) {
+			if err := sessionRepo.TouchSession(r.Context(), sessionID); err != nil {
+				log.Printf("⚠️  Could not update session last-seen time: %v", err)
+			}
+		}
+	}
+
+	// Share one auth service client between session validation and the login
+	// flow, with a circuit breaker and a grace window so an auth outage doesn't
+	// log everyone out
+	authClient := authms.New(cfg.AuthServiceURL).
+		WithBreaker(cfg.AuthBreakerThreshold, time.Duration(cfg.AuthBreakerCooldown)*time.Second)
+	middleware.SessionGracePeriod = time.Duration(cfg.SessionGracePeriod) * time.Second
+	middleware.SessionCacheMaxEntries = cfg.SessionCacheMaxEntries
+	middleware.SetAuthClient(authClient)
+
+	// Share the session cache between replicas when configured
+	if cfg.SessionCacheBackend == "redis" {
+		redisOpts, err := cachex.ParseRedisURL(cfg.RedisURL)
+		if err != nil {
+			log.Fatalf("Invalid REDIS_URL: %v", err)
+		}
+		redisClient := cachex.NewRedisClient(redisOpts)
+		if err := redisClient.Ping(); err != nil {
+			log.Printf("⚠️  Redis ping failed, session cache will miss until it is reachable: %v", err)
+		}
+		middleware.UseSessionCache(middleware.NewRedisSessionCache(redisClient))
+		roleChanges := cachex.NewRedis[time.Time](redisClient, "roles-changed:", time.Minute)
+		roleChanges.OnError = func(err error) {
+			log.Printf("⚠️  Role change backend error: %v", err)
+		}
+		roleService.ShareRoleChanges(roleChanges)
+		log.Println("✅ Session cache and role changes using Redis")
+	} else {
+		middleware.InitializeSessionCache()
+	}
+
 	// Initialize login and session handlers
-	loginHandler = login.NewLoginHandler(cfg)
-	sessionHandler = session.NewSessionHandler(cfg, userRepo)
+	authService := services.NewAuthService(cfg, authClient)
+	loginService := services.NewLoginService(authService, userRepo, sessionRepo, roleService)
+	loginHandler = login.NewLoginHandler(cfg, authService, loginService)
+	sessionHandler = session.NewSessionHandler(cfg, authService, loginService, userRepo, sessionRepo)
 	log.Println("✅ Login and session handlers initialized")
 
-	// Initialize Payment MS Client
-	paymentClient := paymentms.New(cfg.PaymentServiceURL, cfg.PaymentServiceAPIKey)
-	log.Println("✅ Payment MS Client initialized")
+	// Plan checks read the local subscriptions table, kept in sync with the payment service
+	productPlans := cfg.Catalog.ProductPlans()
+	if cfg.StripeProductID != "" {
+		if _, ok := productPlans[cfg.StripeProductID]; !ok {
+			// Subscriptions bought before the catalog existed
+			productPlans[cfg.StripeProductID] = models.PlanPro
+		}
+	}
+	entitlementService := services.NewEntitlementService(subscriptionRepo, userRepo, paymentClient, cfg.Catalog, productPlans)
+	if queries != nil {
+		middleware.EntitlementLookup = middleware.LocalEntitlementLookup(userRepo, entitlementService)
+	}
 
 	// Initialize payment handler
-	paymentHandler = payment.NewPaymentHandler(cfg, paymentClient)
+	promotionService := services.NewPromotionService(paymentClient, cfg.Catalog)
+	paymentHandler = payment.NewPaymentHandler(cfg, paymentClient, userRepo, entitlementService, promotionService)
 	log.Println("✅ Payment handler initialized")
 
+	// Initialize shop and cart
+	cartService := services.NewCartService(cartRepo, purchaseRepo, paymentClient, cfg.Catalog)
+	shopHandler = shop.NewShopHandler(cfg, cartService, userRepo)
+	log.Println("✅ Shop handler initialized")
+
+	// Initialize payment webhook receiver
+	webhookHandler = payment.NewWebhookHandler(cfg, paymentEventRepo, entitlementService, cartService)
+	if cfg.PaymentWebhookSecret == "" {
+		log.Println("⚠️  PAYMENT_WEBHOOK_SECRET not set - payment webhooks will be refused")
+	}
+
 	// Initialize Dashboard Handler
-	dashboardHandler = dashboard.NewDashboardHandler(cfg, paymentClient, sessionHandler)
+	subscriptionService := services.NewSubscriptionService(entitlementService, paymentClient, cfg.Catalog)
+	dashboardHandler = dashboard.NewDashboardHandler(cfg, userRepo, entitlementService, purchaseRepo, subscriptionService)
 	log.Println("✅ Dashboard handler initialized")
 
 	// Initialize Settings Handler
-	settingsHandler = settings.NewSettingsHandler(cfg, sessionHandler, userRepo, prefsRepo, paymentClient)
+	settingsHandler = settings.NewSettingsHandler(cfg, userRepo, prefsRepo, paymentClient)
 	log.Println("✅ Settings handler initialized")
 
+	// Email users before their free trial ends; reminders are claimed in the
+	// database, so every replica can run the job
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if queries != nil && cfg.TrialReminderInterval > 0 {
+		emailer := mailer.New(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.EmailFrom)
+		trialReminders := services.NewTrialReminderService(subscriptionRepo, emailer, cfg.Catalog, cfg.RedirectURL, cfg.TrialReminderDays)
+		go trialReminders.Run(jobCtx, time.Duration(cfg.TrialReminderInterval)*time.Minute)
+		log.Println("✅ Trial reminder job started")
+	}
+
 	// Create router using centralized route structure
 	router := SetupRoutes()
 
@@ -135,6 +235,7 @@ func main() {
 	<-c
 
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	// Graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -157,6 +258,8 @@ func SetupRoutes() *mux.Router {
 		PaymentHandler:   paymentHandler,
 		DashboardHandler: dashboardHandler,
 		SettingsHandler:  settingsHandler,
+		WebhookHandler:   webhookHandler,
+		ShopHandler:      shopHandler,
 	}
 
 	// Use centralized route setup
@@ -164,6 +267,8 @@ func SetupRoutes() *mux.Router {
 
 	// Add middleware after routes are set up
 	router.Use(middleware.AuthMiddleware)
+	router.Use(middleware.CSRFMiddleware)
+	router.Use(middleware.TrialBannerMiddleware)
 
 	return router
 }
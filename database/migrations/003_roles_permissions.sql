@@ -0,0 +1,74 @@
+-- Role-based access control
+-- Roles group permissions; users can hold any number of roles
+
+CREATE TABLE IF NOT EXISTS roles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(50) UNIQUE NOT NULL, -- e.g. admin, support, billing-manager
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) UNIQUE NOT NULL, -- resource:action, e.g. users:read
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    permission_id UUID NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_roles_role_id ON user_roles(role_id);
+
+-- Seed roles
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'Full administrative access'),
+    ('support', 'Customer support: read users and activity logs'),
+    ('billing-manager', 'Manage billing and view revenue analytics')
+ON CONFLICT (name) DO NOTHING;
+
+-- Seed permissions
+INSERT INTO permissions (name, description) VALUES
+    ('admin:access', 'Open the admin dashboard'),
+    ('users:read', 'List and view users'),
+    ('users:write', 'Change user roles'),
+    ('analytics:read', 'View platform analytics'),
+    ('settings:read', 'View system settings'),
+    ('logs:read', 'View activity logs'),
+    ('billing:read', 'View billing data'),
+    ('billing:write', 'Manage billing data')
+ON CONFLICT (name) DO NOTHING;
+
+-- Admin gets every permission
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p
+    ON p.name IN ('admin:access', 'users:read', 'logs:read')
+WHERE r.name = 'support'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p
+    ON p.name IN ('admin:access', 'analytics:read', 'billing:read', 'billing:write')
+WHERE r.name = 'billing-manager'
+ON CONFLICT DO NOTHING;
+
+-- Existing admins keep their access through the admin role
+INSERT INTO user_roles (user_id, role_id)
+SELECT u.id, r.id FROM users u CROSS JOIN roles r
+WHERE u.is_admin = TRUE AND r.name = 'admin'
+ON CONFLICT DO NOTHING;
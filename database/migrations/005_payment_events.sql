@@ -0,0 +1,16 @@
+-- Webhook events received from the payment service, one row per event
+-- event_id is the payment service's ID; the unique constraint makes redelivery a no-op
+CREATE TABLE IF NOT EXISTS payment_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id VARCHAR(255) UNIQUE NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    user_id VARCHAR(255),
+    subscription_id VARCHAR(255),
+    status VARCHAR(50),
+    payload JSONB NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_events_user_id ON payment_events(user_id);
+CREATE INDEX IF NOT EXISTS idx_payment_events_subscription_id ON payment_events(subscription_id);
@@ -0,0 +1,15 @@
+-- Local projection of the user's subscriptions at the payment service
+-- Kept in sync by payment webhooks and on-demand syncs; plan checks read only this table
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    subscription_id VARCHAR(255) UNIQUE NOT NULL,
+    product_id VARCHAR(255) NOT NULL,
+    price_id VARCHAR(255),
+    status VARCHAR(50) NOT NULL,
+    current_period_end TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user_id ON subscriptions(user_id);
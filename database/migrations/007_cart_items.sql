@@ -0,0 +1,11 @@
+-- Shopping cart lines, one row per item in a login session's cart
+-- session_hash is the SHA-256 of the auth service session ID (see 013); the
+-- cart goes away with the session
+CREATE TABLE IF NOT EXISTS cart_items (
+    session_hash VARCHAR(255) NOT NULL,
+    price_id VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (session_hash, price_id)
+);
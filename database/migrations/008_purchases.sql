@@ -0,0 +1,18 @@
+-- One-off purchases, one row per cart line of a checkout
+-- Rows are created pending at checkout and completed by the checkout.completed webhook
+CREATE TABLE IF NOT EXISTS purchases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    checkout_session_id VARCHAR(255) NOT NULL,
+    price_id VARCHAR(255) NOT NULL,
+    item_name VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL,
+    unit_amount BIGINT NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (checkout_session_id, price_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchases_user_id ON purchases(user_id);
@@ -0,0 +1,3 @@
+-- Subscriptions cancelled in-app keep access until the period ends
+-- The flag is set while such a cancellation is pending and cleared on resume
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,6 @@
+-- Free trials: when a trialing subscription converts, and when its reminder email went out
+-- The reminder is claimed by setting trial_reminder_sent_at, so replicas never send it twice
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS trial_end TIMESTAMP WITH TIME ZONE;
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS trial_reminder_sent_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_trial_end ON subscriptions(trial_end) WHERE status = 'trialing';
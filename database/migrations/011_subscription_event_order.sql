@@ -0,0 +1,3 @@
+-- Webhooks can arrive out of order: remember when the state last written was
+-- current, so an older event never overwrites a newer one
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS updated_from_event_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,4 @@
+-- Concurrent deliveries of one event: the handler claims the row by setting
+-- processing_at, so only one delivery handles it. A claim older than the
+-- lease is treated as abandoned by a crashed handler and can be taken over.
+ALTER TABLE payment_events ADD COLUMN IF NOT EXISTS processing_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,13 @@
+-- Carts are keyed by a SHA-256 of the session ID, so the table holds nothing
+-- that could be replayed as a session. Tables from earlier versions of 007 used the
+-- raw session_id: their rows are hashed in place and the column renamed.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'cart_items' AND column_name = 'session_id'
+    ) THEN
+        UPDATE cart_items SET session_id = encode(sha256(convert_to(session_id, 'UTF8')), 'hex');
+        ALTER TABLE cart_items RENAME COLUMN session_id TO session_hash;
+    END IF;
+END $$;
@@ -0,0 +1,24 @@
+-- name: AddCartItem :one
+INSERT INTO cart_items (session_hash, price_id, quantity)
+VALUES ($1, $2, $3)
+ON CONFLICT (session_hash, price_id) DO UPDATE
+SET quantity = LEAST(cart_items.quantity + EXCLUDED.quantity, sqlc.arg(max_quantity)::integer),
+    updated_at = NOW()
+RETURNING *;
+
+-- name: SetCartItemQuantity :exec
+UPDATE cart_items SET quantity = $3, updated_at = NOW()
+WHERE session_hash = $1 AND price_id = $2;
+
+-- name: RemoveCartItem :exec
+DELETE FROM cart_items
+WHERE session_hash = $1 AND price_id = $2;
+
+-- name: GetCartItems :many
+SELECT * FROM cart_items
+WHERE session_hash = $1
+ORDER BY created_at;
+
+-- name: ClearCart :exec
+DELETE FROM cart_items
+WHERE session_hash = $1;
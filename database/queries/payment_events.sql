@@ -0,0 +1,30 @@
+-- name: CreatePaymentEvent :one
+INSERT INTO payment_events (event_id, event_type, user_id, subscription_id, status, payload)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (event_id) DO NOTHING
+RETURNING *;
+
+-- name: GetPaymentEvent :one
+SELECT * FROM payment_events
+WHERE event_id = $1;
+
+-- name: ClaimPaymentEvent :execrows
+-- Claims an unhandled event for one delivery; $2 is the lease after which an abandoned claim expires
+UPDATE payment_events SET processing_at = NOW()
+WHERE event_id = $1
+  AND processed_at IS NULL
+  AND (processing_at IS NULL OR processing_at < NOW() - sqlc.arg(lease)::interval);
+
+-- name: ReleasePaymentEvent :exec
+UPDATE payment_events SET processing_at = NULL
+WHERE event_id = $1 AND processed_at IS NULL;
+
+-- name: MarkPaymentEventProcessed :exec
+UPDATE payment_events SET processed_at = NOW(), processing_at = NULL
+WHERE event_id = $1;
+
+-- name: GetPaymentEventsByUser :many
+SELECT * FROM payment_events
+WHERE user_id = $1
+ORDER BY received_at DESC
+LIMIT $2;
@@ -0,0 +1,18 @@
+-- name: CreatePurchases :exec
+-- Records every line of a checkout in one statement, so either all are stored or none
+INSERT INTO purchases (user_id, checkout_session_id, price_id, item_name, quantity, unit_amount, currency)
+SELECT sqlc.arg(user_id), sqlc.arg(checkout_session_id), line.price_id, line.item_name, line.quantity, line.unit_amount, line.currency
+FROM unnest(sqlc.arg(price_ids)::text[], sqlc.arg(item_names)::text[], sqlc.arg(quantities)::integer[], sqlc.arg(unit_amounts)::bigint[], sqlc.arg(currencies)::text[])
+    AS line(price_id, item_name, quantity, unit_amount, currency)
+ON CONFLICT (checkout_session_id, price_id) DO NOTHING;
+
+-- name: CompletePurchases :many
+UPDATE purchases SET status = 'completed', completed_at = NOW()
+WHERE checkout_session_id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: GetCompletedPurchasesByUser :many
+SELECT * FROM purchases
+WHERE user_id = $1 AND status = 'completed'
+ORDER BY completed_at DESC
+LIMIT $2;
@@ -0,0 +1,28 @@
+-- name: GetAllRoles :many
+SELECT * FROM roles ORDER BY name;
+
+-- name: GetRoleByName :one
+SELECT * FROM roles WHERE name = $1;
+
+-- name: GetUserRoles :many
+SELECT r.* FROM roles r
+JOIN user_roles ur ON ur.role_id = r.id
+WHERE ur.user_id = $1
+ORDER BY r.name;
+
+-- name: GetUserPermissionsByEmail :many
+SELECT DISTINCT p.name FROM permissions p
+JOIN role_permissions rp ON rp.permission_id = p.id
+JOIN user_roles ur ON ur.role_id = rp.role_id
+JOIN users u ON u.id = ur.user_id
+WHERE u.email = $1
+ORDER BY p.name;
+
+-- name: AssignRoleToUser :exec
+INSERT INTO user_roles (user_id, role_id)
+VALUES ($1, (SELECT id FROM roles WHERE name = $2))
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveRoleFromUser :exec
+DELETE FROM user_roles
+WHERE user_id = $1 AND role_id = (SELECT id FROM roles WHERE name = $2);
@@ -0,0 +1,50 @@
+-- name: UpsertSubscription :one
+INSERT INTO subscriptions (user_id, subscription_id, product_id, price_id, status, current_period_end, cancel_at_period_end, trial_end, updated_from_event_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (subscription_id) DO UPDATE
+SET product_id = EXCLUDED.product_id,
+    price_id = COALESCE(EXCLUDED.price_id, subscriptions.price_id),
+    status = EXCLUDED.status,
+    current_period_end = EXCLUDED.current_period_end,
+    cancel_at_period_end = EXCLUDED.cancel_at_period_end,
+    trial_end = EXCLUDED.trial_end,
+    -- An extended trial gets a new reminder
+    trial_reminder_sent_at = CASE
+        WHEN subscriptions.trial_end IS DISTINCT FROM EXCLUDED.trial_end THEN NULL
+        ELSE subscriptions.trial_reminder_sent_at
+    END,
+    updated_from_event_at = COALESCE(EXCLUDED.updated_from_event_at, subscriptions.updated_from_event_at),
+    updated_at = NOW()
+-- Skip events older than the last one stored; no row is returned then. Event times
+-- have second precision, so events from the same second apply in arrival order.
+-- States fetched from the payment service carry no event time and always apply.
+WHERE EXCLUDED.updated_from_event_at IS NULL
+   OR subscriptions.updated_from_event_at IS NULL
+   OR subscriptions.updated_from_event_at <= EXCLUDED.updated_from_event_at
+RETURNING *;
+
+-- name: GetSubscriptionsByUser :many
+SELECT * FROM subscriptions
+WHERE user_id = $1
+ORDER BY updated_at DESC;
+
+-- name: GetTrialsEndingBefore :many
+-- Trials ending by $1 whose reminder is still due, skipping users who opted out of billing email
+SELECT s.subscription_id, s.user_id, s.product_id, s.trial_end, u.email, u.name
+FROM subscriptions s
+JOIN users u ON u.id = s.user_id
+LEFT JOIN user_preferences p ON p.user_id = s.user_id
+WHERE s.status = 'trialing'
+  AND s.trial_end > NOW()
+  AND s.trial_end <= $1
+  AND s.trial_reminder_sent_at IS NULL
+  AND COALESCE(p.email_billing, TRUE)
+ORDER BY s.trial_end;
+
+-- name: ClaimTrialReminder :execrows
+UPDATE subscriptions SET trial_reminder_sent_at = NOW()
+WHERE subscription_id = $1 AND trial_reminder_sent_at IS NULL;
+
+-- name: ReleaseTrialReminder :exec
+UPDATE subscriptions SET trial_reminder_sent_at = NULL
+WHERE subscription_id = $1;
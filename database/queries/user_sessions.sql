@@ -0,0 +1,27 @@
+-- name: CreateUserSession :one
+INSERT INTO user_sessions (user_id, session_id, user_agent, ip_address)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (session_id) DO UPDATE
+SET last_seen_at = NOW()
+RETURNING *;
+
+-- name: GetActiveUserSessions :many
+SELECT * FROM user_sessions
+WHERE user_id = $1 AND revoked_at IS NULL
+ORDER BY last_seen_at DESC;
+
+-- name: GetUserSession :one
+SELECT * FROM user_sessions
+WHERE id = $1 AND user_id = $2;
+
+-- name: TouchUserSession :exec
+UPDATE user_sessions SET last_seen_at = NOW()
+WHERE session_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserSession :exec
+UPDATE user_sessions SET revoked_at = NOW()
+WHERE session_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeAllUserSessions :exec
+UPDATE user_sessions SET revoked_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL;
@@ -24,6 +24,24 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.addCartItemStmt, err = db.PrepareContext(ctx, addCartItem); err != nil {
+		return nil, fmt.Errorf("error preparing query AddCartItem: %w", err)
+	}
+	if q.assignRoleToUserStmt, err = db.PrepareContext(ctx, assignRoleToUser); err != nil {
+		return nil, fmt.Errorf("error preparing query AssignRoleToUser: %w", err)
+	}
+	if q.claimPaymentEventStmt, err = db.PrepareContext(ctx, claimPaymentEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimPaymentEvent: %w", err)
+	}
+	if q.claimTrialReminderStmt, err = db.PrepareContext(ctx, claimTrialReminder); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimTrialReminder: %w", err)
+	}
+	if q.clearCartStmt, err = db.PrepareContext(ctx, clearCart); err != nil {
+		return nil, fmt.Errorf("error preparing query ClearCart: %w", err)
+	}
+	if q.completePurchasesStmt, err = db.PrepareContext(ctx, completePurchases); err != nil {
+		return nil, fmt.Errorf("error preparing query CompletePurchases: %w", err)
+	}
 	if q.countUsersStmt, err = db.PrepareContext(ctx, countUsers); err != nil {
 		return nil, fmt.Errorf("error preparing query CountUsers: %w", err)
 	}
@@ -33,21 +51,57 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.countUsersCreatedTodayStmt, err = db.PrepareContext(ctx, countUsersCreatedToday); err != nil {
 		return nil, fmt.Errorf("error preparing query CountUsersCreatedToday: %w", err)
 	}
+	if q.createPaymentEventStmt, err = db.PrepareContext(ctx, createPaymentEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query CreatePaymentEvent: %w", err)
+	}
+	if q.createPurchasesStmt, err = db.PrepareContext(ctx, createPurchases); err != nil {
+		return nil, fmt.Errorf("error preparing query CreatePurchases: %w", err)
+	}
 	if q.createUserStmt, err = db.PrepareContext(ctx, createUser); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUser: %w", err)
 	}
 	if q.createUserPreferencesStmt, err = db.PrepareContext(ctx, createUserPreferences); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateUserPreferences: %w", err)
 	}
+	if q.createUserSessionStmt, err = db.PrepareContext(ctx, createUserSession); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUserSession: %w", err)
+	}
+	if q.getActiveUserSessionsStmt, err = db.PrepareContext(ctx, getActiveUserSessions); err != nil {
+		return nil, fmt.Errorf("error preparing query GetActiveUserSessions: %w", err)
+	}
 	if q.getAdminUsersStmt, err = db.PrepareContext(ctx, getAdminUsers); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAdminUsers: %w", err)
 	}
+	if q.getAllRolesStmt, err = db.PrepareContext(ctx, getAllRoles); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAllRoles: %w", err)
+	}
 	if q.getAllUsersStmt, err = db.PrepareContext(ctx, getAllUsers); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAllUsers: %w", err)
 	}
+	if q.getCartItemsStmt, err = db.PrepareContext(ctx, getCartItems); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCartItems: %w", err)
+	}
+	if q.getCompletedPurchasesByUserStmt, err = db.PrepareContext(ctx, getCompletedPurchasesByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCompletedPurchasesByUser: %w", err)
+	}
+	if q.getPaymentEventStmt, err = db.PrepareContext(ctx, getPaymentEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPaymentEvent: %w", err)
+	}
+	if q.getPaymentEventsByUserStmt, err = db.PrepareContext(ctx, getPaymentEventsByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPaymentEventsByUser: %w", err)
+	}
 	if q.getRecentUsersStmt, err = db.PrepareContext(ctx, getRecentUsers); err != nil {
 		return nil, fmt.Errorf("error preparing query GetRecentUsers: %w", err)
 	}
+	if q.getRoleByNameStmt, err = db.PrepareContext(ctx, getRoleByName); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRoleByName: %w", err)
+	}
+	if q.getSubscriptionsByUserStmt, err = db.PrepareContext(ctx, getSubscriptionsByUser); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSubscriptionsByUser: %w", err)
+	}
+	if q.getTrialsEndingBeforeStmt, err = db.PrepareContext(ctx, getTrialsEndingBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTrialsEndingBefore: %w", err)
+	}
 	if q.getUserByAuthIDStmt, err = db.PrepareContext(ctx, getUserByAuthID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserByAuthID: %w", err)
 	}
@@ -57,9 +111,45 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getUserByIDStmt, err = db.PrepareContext(ctx, getUserByID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserByID: %w", err)
 	}
+	if q.getUserPermissionsByEmailStmt, err = db.PrepareContext(ctx, getUserPermissionsByEmail); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserPermissionsByEmail: %w", err)
+	}
 	if q.getUserPreferencesStmt, err = db.PrepareContext(ctx, getUserPreferences); err != nil {
 		return nil, fmt.Errorf("error preparing query GetUserPreferences: %w", err)
 	}
+	if q.getUserRolesStmt, err = db.PrepareContext(ctx, getUserRoles); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserRoles: %w", err)
+	}
+	if q.getUserSessionStmt, err = db.PrepareContext(ctx, getUserSession); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserSession: %w", err)
+	}
+	if q.markPaymentEventProcessedStmt, err = db.PrepareContext(ctx, markPaymentEventProcessed); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkPaymentEventProcessed: %w", err)
+	}
+	if q.releasePaymentEventStmt, err = db.PrepareContext(ctx, releasePaymentEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query ReleasePaymentEvent: %w", err)
+	}
+	if q.releaseTrialReminderStmt, err = db.PrepareContext(ctx, releaseTrialReminder); err != nil {
+		return nil, fmt.Errorf("error preparing query ReleaseTrialReminder: %w", err)
+	}
+	if q.removeCartItemStmt, err = db.PrepareContext(ctx, removeCartItem); err != nil {
+		return nil, fmt.Errorf("error preparing query RemoveCartItem: %w", err)
+	}
+	if q.removeRoleFromUserStmt, err = db.PrepareContext(ctx, removeRoleFromUser); err != nil {
+		return nil, fmt.Errorf("error preparing query RemoveRoleFromUser: %w", err)
+	}
+	if q.revokeAllUserSessionsStmt, err = db.PrepareContext(ctx, revokeAllUserSessions); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeAllUserSessions: %w", err)
+	}
+	if q.revokeUserSessionStmt, err = db.PrepareContext(ctx, revokeUserSession); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeUserSession: %w", err)
+	}
+	if q.setCartItemQuantityStmt, err = db.PrepareContext(ctx, setCartItemQuantity); err != nil {
+		return nil, fmt.Errorf("error preparing query SetCartItemQuantity: %w", err)
+	}
+	if q.touchUserSessionStmt, err = db.PrepareContext(ctx, touchUserSession); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchUserSession: %w", err)
+	}
 	if q.updateUserStmt, err = db.PrepareContext(ctx, updateUser); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateUser: %w", err)
 	}
@@ -69,6 +159,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateUserPreferencesStmt, err = db.PrepareContext(ctx, updateUserPreferences); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateUserPreferences: %w", err)
 	}
+	if q.upsertSubscriptionStmt, err = db.PrepareContext(ctx, upsertSubscription); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertSubscription: %w", err)
+	}
 	if q.upsertUserStmt, err = db.PrepareContext(ctx, upsertUser); err != nil {
 		return nil, fmt.Errorf("error preparing query UpsertUser: %w", err)
 	}
@@ -77,6 +170,36 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 
 func (q *Queries) Close() error {
 	var err error
+	if q.addCartItemStmt != nil {
+		if cerr := q.addCartItemStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addCartItemStmt: %w", cerr)
+		}
+	}
+	if q.assignRoleToUserStmt != nil {
+		if cerr := q.assignRoleToUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing assignRoleToUserStmt: %w", cerr)
+		}
+	}
+	if q.claimPaymentEventStmt != nil {
+		if cerr := q.claimPaymentEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimPaymentEventStmt: %w", cerr)
+		}
+	}
+	if q.claimTrialReminderStmt != nil {
+		if cerr := q.claimTrialReminderStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimTrialReminderStmt: %w", cerr)
+		}
+	}
+	if q.clearCartStmt != nil {
+		if cerr := q.clearCartStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing clearCartStmt: %w", cerr)
+		}
+	}
+	if q.completePurchasesStmt != nil {
+		if cerr := q.completePurchasesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing completePurchasesStmt: %w", cerr)
+		}
+	}
 	if q.countUsersStmt != nil {
 		if cerr := q.countUsersStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing countUsersStmt: %w", cerr)
@@ -92,6 +215,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing countUsersCreatedTodayStmt: %w", cerr)
 		}
 	}
+	if q.createPaymentEventStmt != nil {
+		if cerr := q.createPaymentEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createPaymentEventStmt: %w", cerr)
+		}
+	}
+	if q.createPurchasesStmt != nil {
+		if cerr := q.createPurchasesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createPurchasesStmt: %w", cerr)
+		}
+	}
 	if q.createUserStmt != nil {
 		if cerr := q.createUserStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
@@ -102,21 +235,71 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createUserPreferencesStmt: %w", cerr)
 		}
 	}
+	if q.createUserSessionStmt != nil {
+		if cerr := q.createUserSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUserSessionStmt: %w", cerr)
+		}
+	}
+	if q.getActiveUserSessionsStmt != nil {
+		if cerr := q.getActiveUserSessionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getActiveUserSessionsStmt: %w", cerr)
+		}
+	}
 	if q.getAdminUsersStmt != nil {
 		if cerr := q.getAdminUsersStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAdminUsersStmt: %w", cerr)
 		}
 	}
+	if q.getAllRolesStmt != nil {
+		if cerr := q.getAllRolesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAllRolesStmt: %w", cerr)
+		}
+	}
 	if q.getAllUsersStmt != nil {
 		if cerr := q.getAllUsersStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAllUsersStmt: %w", cerr)
 		}
 	}
+	if q.getCartItemsStmt != nil {
+		if cerr := q.getCartItemsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCartItemsStmt: %w", cerr)
+		}
+	}
+	if q.getCompletedPurchasesByUserStmt != nil {
+		if cerr := q.getCompletedPurchasesByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCompletedPurchasesByUserStmt: %w", cerr)
+		}
+	}
+	if q.getPaymentEventStmt != nil {
+		if cerr := q.getPaymentEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPaymentEventStmt: %w", cerr)
+		}
+	}
+	if q.getPaymentEventsByUserStmt != nil {
+		if cerr := q.getPaymentEventsByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPaymentEventsByUserStmt: %w", cerr)
+		}
+	}
 	if q.getRecentUsersStmt != nil {
 		if cerr := q.getRecentUsersStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getRecentUsersStmt: %w", cerr)
 		}
 	}
+	if q.getRoleByNameStmt != nil {
+		if cerr := q.getRoleByNameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRoleByNameStmt: %w", cerr)
+		}
+	}
+	if q.getSubscriptionsByUserStmt != nil {
+		if cerr := q.getSubscriptionsByUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSubscriptionsByUserStmt: %w", cerr)
+		}
+	}
+	if q.getTrialsEndingBeforeStmt != nil {
+		if cerr := q.getTrialsEndingBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTrialsEndingBeforeStmt: %w", cerr)
+		}
+	}
 	if q.getUserByAuthIDStmt != nil {
 		if cerr := q.getUserByAuthIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getUserByAuthIDStmt: %w", cerr)
@@ -132,11 +315,71 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getUserByIDStmt: %w", cerr)
 		}
 	}
+	if q.getUserPermissionsByEmailStmt != nil {
+		if cerr := q.getUserPermissionsByEmailStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserPermissionsByEmailStmt: %w", cerr)
+		}
+	}
 	if q.getUserPreferencesStmt != nil {
 		if cerr := q.getUserPreferencesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getUserPreferencesStmt: %w", cerr)
 		}
 	}
+	if q.getUserRolesStmt != nil {
+		if cerr := q.getUserRolesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserRolesStmt: %w", cerr)
+		}
+	}
+	if q.getUserSessionStmt != nil {
+		if cerr := q.getUserSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserSessionStmt: %w", cerr)
+		}
+	}
+	if q.markPaymentEventProcessedStmt != nil {
+		if cerr := q.markPaymentEventProcessedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markPaymentEventProcessedStmt: %w", cerr)
+		}
+	}
+	if q.releasePaymentEventStmt != nil {
+		if cerr := q.releasePaymentEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing releasePaymentEventStmt: %w", cerr)
+		}
+	}
+	if q.releaseTrialReminderStmt != nil {
+		if cerr := q.releaseTrialReminderStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing releaseTrialReminderStmt: %w", cerr)
+		}
+	}
+	if q.removeCartItemStmt != nil {
+		if cerr := q.removeCartItemStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing removeCartItemStmt: %w", cerr)
+		}
+	}
+	if q.removeRoleFromUserStmt != nil {
+		if cerr := q.removeRoleFromUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing removeRoleFromUserStmt: %w", cerr)
+		}
+	}
+	if q.revokeAllUserSessionsStmt != nil {
+		if cerr := q.revokeAllUserSessionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeAllUserSessionsStmt: %w", cerr)
+		}
+	}
+	if q.revokeUserSessionStmt != nil {
+		if cerr := q.revokeUserSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeUserSessionStmt: %w", cerr)
+		}
+	}
+	if q.setCartItemQuantityStmt != nil {
+		if cerr := q.setCartItemQuantityStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setCartItemQuantityStmt: %w", cerr)
+		}
+	}
+	if q.touchUserSessionStmt != nil {
+		if cerr := q.touchUserSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchUserSessionStmt: %w", cerr)
+		}
+	}
 	if q.updateUserStmt != nil {
 		if cerr := q.updateUserStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateUserStmt: %w", cerr)
@@ -152,6 +395,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateUserPreferencesStmt: %w", cerr)
 		}
 	}
+	if q.upsertSubscriptionStmt != nil {
+		if cerr := q.upsertSubscriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertSubscriptionStmt: %w", cerr)
+		}
+	}
 	if q.upsertUserStmt != nil {
 		if cerr := q.upsertUserStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing upsertUserStmt: %w", cerr)
@@ -194,45 +442,107 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                            DBTX
-	tx                            *sql.Tx
-	countUsersStmt                *sql.Stmt
-	countUsersCreatedThisWeekStmt *sql.Stmt
-	countUsersCreatedTodayStmt    *sql.Stmt
-	createUserStmt                *sql.Stmt
-	createUserPreferencesStmt     *sql.Stmt
-	getAdminUsersStmt             *sql.Stmt
-	getAllUsersStmt               *sql.Stmt
-	getRecentUsersStmt            *sql.Stmt
-	getUserByAuthIDStmt           *sql.Stmt
-	getUserByEmailStmt            *sql.Stmt
-	getUserByIDStmt               *sql.Stmt
-	getUserPreferencesStmt        *sql.Stmt
-	updateUserStmt                *sql.Stmt
-	updateUserAdminStatusStmt     *sql.Stmt
-	updateUserPreferencesStmt     *sql.Stmt
-	upsertUserStmt                *sql.Stmt
+	db                              DBTX
+	tx                              *sql.Tx
+	addCartItemStmt                 *sql.Stmt
+	assignRoleToUserStmt            *sql.Stmt
+	claimPaymentEventStmt           *sql.Stmt
+	claimTrialReminderStmt          *sql.Stmt
+	clearCartStmt                   *sql.Stmt
+	completePurchasesStmt           *sql.Stmt
+	countUsersStmt                  *sql.Stmt
+	countUsersCreatedThisWeekStmt   *sql.Stmt
+	countUsersCreatedTodayStmt      *sql.Stmt
+	createPaymentEventStmt          *sql.Stmt
+	createPurchasesStmt             *sql.Stmt
+	createUserStmt                  *sql.Stmt
+	createUserPreferencesStmt       *sql.Stmt
+	createUserSessionStmt           *sql.Stmt
+	getActiveUserSessionsStmt       *sql.Stmt
+	getAdminUsersStmt               *sql.Stmt
+	getAllRolesStmt                 *sql.Stmt
+	getAllUsersStmt                 *sql.Stmt
+	getCartItemsStmt                *sql.Stmt
+	getCompletedPurchasesByUserStmt *sql.Stmt
+	getPaymentEventStmt             *sql.Stmt
+	getPaymentEventsByUserStmt      *sql.Stmt
+	getRecentUsersStmt              *sql.Stmt
+	getRoleByNameStmt               *sql.Stmt
+	getSubscriptionsByUserStmt      *sql.Stmt
+	getTrialsEndingBeforeStmt       *sql.Stmt
+	getUserByAuthIDStmt             *sql.Stmt
+	getUserByEmailStmt              *sql.Stmt
+	getUserByIDStmt                 *sql.Stmt
+	getUserPermissionsByEmailStmt   *sql.Stmt
+	getUserPreferencesStmt          *sql.Stmt
+	getUserRolesStmt                *sql.Stmt
+	getUserSessionStmt              *sql.Stmt
+	markPaymentEventProcessedStmt   *sql.Stmt
+	releasePaymentEventStmt         *sql.Stmt
+	releaseTrialReminderStmt        *sql.Stmt
+	removeCartItemStmt              *sql.Stmt
+	removeRoleFromUserStmt          *sql.Stmt
+	revokeAllUserSessionsStmt       *sql.Stmt
+	revokeUserSessionStmt           *sql.Stmt
+	setCartItemQuantityStmt         *sql.Stmt
+	touchUserSessionStmt            *sql.Stmt
+	updateUserStmt                  *sql.Stmt
+	updateUserAdminStatusStmt       *sql.Stmt
+	updateUserPreferencesStmt       *sql.Stmt
+	upsertSubscriptionStmt          *sql.Stmt
+	upsertUserStmt                  *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                            tx,
-		tx:                            tx,
-		countUsersStmt:                q.countUsersStmt,
-		countUsersCreatedThisWeekStmt: q.countUsersCreatedThisWeekStmt,
-		countUsersCreatedTodayStmt:    q.countUsersCreatedTodayStmt,
-		createUserStmt:                q.createUserStmt,
-		createUserPreferencesStmt:     q.createUserPreferencesStmt,
-		getAdminUsersStmt:             q.getAdminUsersStmt,
-		getAllUsersStmt:               q.getAllUsersStmt,
-		getRecentUsersStmt:            q.getRecentUsersStmt,
-		getUserByAuthIDStmt:           q.getUserByAuthIDStmt,
-		getUserByEmailStmt:            q.getUserByEmailStmt,
-		getUserByIDStmt:               q.getUserByIDStmt,
-		getUserPreferencesStmt:        q.getUserPreferencesStmt,
-		updateUserStmt:                q.updateUserStmt,
-		updateUserAdminStatusStmt:     q.updateUserAdminStatusStmt,
-		updateUserPreferencesStmt:     q.updateUserPreferencesStmt,
-		upsertUserStmt:                q.upsertUserStmt,
+		db:                              tx,
+		tx:                              tx,
+		addCartItemStmt:                 q.addCartItemStmt,
+		assignRoleToUserStmt:            q.assignRoleToUserStmt,
+		claimPaymentEventStmt:           q.claimPaymentEventStmt,
+		claimTrialReminderStmt:          q.claimTrialReminderStmt,
+		clearCartStmt:                   q.clearCartStmt,
+		completePurchasesStmt:           q.completePurchasesStmt,
+		countUsersStmt:                  q.countUsersStmt,
+		countUsersCreatedThisWeekStmt:   q.countUsersCreatedThisWeekStmt,
+		countUsersCreatedTodayStmt:      q.countUsersCreatedTodayStmt,
+		createPaymentEventStmt:          q.createPaymentEventStmt,
+		createPurchasesStmt:             q.createPurchasesStmt,
+		createUserStmt:                  q.createUserStmt,
+		createUserPreferencesStmt:       q.createUserPreferencesStmt,
+		createUserSessionStmt:           q.createUserSessionStmt,
+		getActiveUserSessionsStmt:       q.getActiveUserSessionsStmt,
+		getAdminUsersStmt:               q.getAdminUsersStmt,
+		getAllRolesStmt:                 q.getAllRolesStmt,
+		getAllUsersStmt:                 q.getAllUsersStmt,
+		getCartItemsStmt:                q.getCartItemsStmt,
+		getCompletedPurchasesByUserStmt: q.getCompletedPurchasesByUserStmt,
+		getPaymentEventStmt:             q.getPaymentEventStmt,
+		getPaymentEventsByUserStmt:      q.getPaymentEventsByUserStmt,
+		getRecentUsersStmt:              q.getRecentUsersStmt,
+		getRoleByNameStmt:               q.getRoleByNameStmt,
+		getSubscriptionsByUserStmt:      q.getSubscriptionsByUserStmt,
+		getTrialsEndingBeforeStmt:       q.getTrialsEndingBeforeStmt,
+		getUserByAuthIDStmt:             q.getUserByAuthIDStmt,
+		getUserByEmailStmt:              q.getUserByEmailStmt,
+		getUserByIDStmt:                 q.getUserByIDStmt,
+		getUserPermissionsByEmailStmt:   q.getUserPermissionsByEmailStmt,
+		getUserPreferencesStmt:          q.getUserPreferencesStmt,
+		getUserRolesStmt:                q.getUserRolesStmt,
+		getUserSessionStmt:              q.getUserSessionStmt,
+		markPaymentEventProcessedStmt:   q.markPaymentEventProcessedStmt,
+		releasePaymentEventStmt:         q.releasePaymentEventStmt,
+		releaseTrialReminderStmt:        q.releaseTrialReminderStmt,
+		removeCartItemStmt:              q.removeCartItemStmt,
+		removeRoleFromUserStmt:          q.removeRoleFromUserStmt,
+		revokeAllUserSessionsStmt:       q.revokeAllUserSessionsStmt,
+		revokeUserSessionStmt:           q.revokeUserSessionStmt,
+		setCartItemQuantityStmt:         q.setCartItemQuantityStmt,
+		touchUserSessionStmt:            q.touchUserSessionStmt,
+		updateUserStmt:                  q.updateUserStmt,
+		updateUserAdminStatusStmt:       q.updateUserAdminStatusStmt,
+		updateUserPreferencesStmt:       q.updateUserPreferencesStmt,
+		upsertSubscriptionStmt:          q.upsertSubscriptionStmt,
+		upsertUserStmt:                  q.upsertUserStmt,
 	}
 }
@@ -6,10 +6,81 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 
 	"github.com/google/uuid"
 )
 
+type CartItem struct {
+	SessionHash string       `json:"session_hash"`
+	PriceID     string       `json:"price_id"`
+	Quantity    int32        `json:"quantity"`
+	CreatedAt   sql.NullTime `json:"created_at"`
+	UpdatedAt   sql.NullTime `json:"updated_at"`
+}
+
+type PaymentEvent struct {
+	ID             uuid.UUID       `json:"id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	UserID         sql.NullString  `json:"user_id"`
+	SubscriptionID sql.NullString  `json:"subscription_id"`
+	Status         sql.NullString  `json:"status"`
+	Payload        json.RawMessage `json:"payload"`
+	ReceivedAt     sql.NullTime    `json:"received_at"`
+	ProcessedAt    sql.NullTime    `json:"processed_at"`
+	ProcessingAt   sql.NullTime    `json:"processing_at"`
+}
+
+type Permission struct {
+	ID          uuid.UUID      `json:"id"`
+	Name        string         `json:"name"`
+	Description sql.NullString `json:"description"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+}
+
+type Purchase struct {
+	ID                uuid.UUID    `json:"id"`
+	UserID            uuid.UUID    `json:"user_id"`
+	CheckoutSessionID string       `json:"checkout_session_id"`
+	PriceID           string       `json:"price_id"`
+	ItemName          string       `json:"item_name"`
+	Quantity          int32        `json:"quantity"`
+	UnitAmount        int64        `json:"unit_amount"`
+	Currency          string       `json:"currency"`
+	Status            string       `json:"status"`
+	CreatedAt         sql.NullTime `json:"created_at"`
+	CompletedAt       sql.NullTime `json:"completed_at"`
+}
+
+type Role struct {
+	ID          uuid.UUID      `json:"id"`
+	Name        string         `json:"name"`
+	Description sql.NullString `json:"description"`
+	CreatedAt   sql.NullTime   `json:"created_at"`
+}
+
+type RolePermission struct {
+	RoleID       uuid.UUID `json:"role_id"`
+	PermissionID uuid.UUID `json:"permission_id"`
+}
+
+type Subscription struct {
+	ID                  uuid.UUID      `json:"id"`
+	UserID              uuid.UUID      `json:"user_id"`
+	SubscriptionID      string         `json:"subscription_id"`
+	ProductID           string         `json:"product_id"`
+	PriceID             sql.NullString `json:"price_id"`
+	Status              string         `json:"status"`
+	CurrentPeriodEnd    sql.NullTime   `json:"current_period_end"`
+	CreatedAt           sql.NullTime   `json:"created_at"`
+	UpdatedAt           sql.NullTime   `json:"updated_at"`
+	CancelAtPeriodEnd   bool           `json:"cancel_at_period_end"`
+	TrialEnd            sql.NullTime   `json:"trial_end"`
+	TrialReminderSentAt sql.NullTime   `json:"trial_reminder_sent_at"`
+	UpdatedFromEventAt  sql.NullTime   `json:"updated_from_event_at"`
+}
+
 type User struct {
 	ID        uuid.UUID      `json:"id"`
 	AuthID    string         `json:"auth_id"`
@@ -33,3 +104,20 @@ type UserPreference struct {
 	Timezone           sql.NullString `json:"timezone"`
 	EmailBilling       sql.NullBool   `json:"email_billing"`
 }
+
+type UserRole struct {
+	UserID    uuid.UUID    `json:"user_id"`
+	RoleID    uuid.UUID    `json:"role_id"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type UserSession struct {
+	ID         uuid.UUID      `json:"id"`
+	UserID     uuid.UUID      `json:"user_id"`
+	SessionID  string         `json:"session_id"`
+	UserAgent  sql.NullString `json:"user_agent"`
+	IpAddress  sql.NullString `json:"ip_address"`
+	CreatedAt  sql.NullTime   `json:"created_at"`
+	LastSeenAt sql.NullTime   `json:"last_seen_at"`
+	RevokedAt  sql.NullTime   `json:"revoked_at"`
+}
@@ -0,0 +1,177 @@
+package authms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Client is the client for the Auth Microservice.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
+	breaker    *Breaker
+}
+
+// New creates a new Auth MS client.
+func New(baseURL string) *Client {
+	return &Client{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+		maxRetries: 2,
+		backoff:    100 * time.Millisecond,
+		breaker:    NewBreaker(5, 30*time.Second),
+	}
+}
+
+// WithRetries sets how often idempotent calls are retried and the initial backoff.
+func (c *Client) WithRetries(maxRetries int, backoff time.Duration) *Client {
+	c.maxRetries = maxRetries
+	c.backoff = backoff
+	return c
+}
+
+// WithBreaker sets the failure threshold and cooldown of the circuit breaker (0 disables it).
+func (c *Client) WithBreaker(threshold int, cooldown time.Duration) *Client {
+	c.breaker = NewBreaker(threshold, cooldown)
+	return c
+}
+
+// BreakerStatus reports the circuit breaker state for health checks.
+func (c *Client) BreakerStatus() BreakerStatus {
+	return c.breaker.Status()
+}
+
+// CreateSession exchanges an authorization code for a session.
+// Codes are single-use, so this call is never retried.
+func (c *Client) CreateSession(ctx context.Context, req CreateSessionRequest) (*SessionResponse, error) {
+	var result SessionResponse
+	if err := c.doRequest(ctx, "/auth/session/create", req, &result, false); err != nil {
+		return nil, err
+	}
+	if result.SessionID == "" {
+		return nil, fmt.Errorf("auth service returned no session_id")
+	}
+	return &result, nil
+}
+
+// RefreshSession validates a session and returns its user context.
+func (c *Client) RefreshSession(ctx context.Context, req RefreshSessionRequest) (*SessionResponse, error) {
+	var result SessionResponse
+	if err := c.doRequest(ctx, "/auth/session/refresh", req, &result, true); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RevokeSession revokes a single session.
+func (c *Client) RevokeSession(ctx context.Context, req RevokeSessionRequest) error {
+	return c.doRequest(ctx, "/auth/session/revoke", req, &RevokeResponse{}, true)
+}
+
+// RevokeAllSessions revokes every session belonging to the owner of the given session.
+func (c *Client) RevokeAllSessions(ctx context.Context, req RevokeSessionRequest) error {
+	return c.doRequest(ctx, "/auth/session/revoke-all", req, &RevokeResponse{}, true)
+}
+
+// doRequest POSTs payload to path and decodes the response into result.
+// Idempotent calls are retried with exponential backoff when the service is unavailable.
+func (c *Client) doRequest(ctx context.Context, path string, payload, result interface{}, idempotent bool) error {
+	jsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	attempts := 1
+	if idempotent {
+		attempts += c.maxRetries
+	}
+
+	backoff := c.backoff
+	for attempt := 1; ; attempt++ {
+		err = c.sendThroughBreaker(ctx, path, jsonBytes, result)
+		if err == nil || attempt >= attempts || !errors.Is(err, ErrUnavailable) || errors.Is(err, ErrCircuitOpen) {
+			return err
+		}
+
+		fmt.Printf("🔍 AUTH-MS: %s failed (attempt %d/%d), retrying in %s\n", path, attempt, attempts, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// sendThroughBreaker sends the request unless the breaker is open, and records the outcome.
+// Rejections (4xx) count as success: the service is up and answering.
+func (c *Client) sendThroughBreaker(ctx context.Context, path string, body []byte, result interface{}) error {
+	if !c.breaker.Allow() {
+		return ErrCircuitOpen
+	}
+
+	err := c.send(ctx, path, body, result)
+	switch {
+	case err != nil && ctx.Err() != nil:
+		c.breaker.release() // the caller gave up; says nothing about the service
+	case err == nil || !errors.Is(err, ErrUnavailable):
+		c.breaker.RecordSuccess()
+	default:
+		c.breaker.RecordFailure()
+	}
+	return err
+}
+
+// send performs a single request. Bodies are never logged: they carry session IDs and auth codes.
+func (c *Client) send(ctx context.Context, path string, body []byte, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &Error{StatusCode: resp.StatusCode, Message: errorMessage(respBody)}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// errorMessage extracts the "error" field of a JSON error body
+func errorMessage(body []byte) string {
+	var payload struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	if payload.Error != "" {
+		return payload.Error
+	}
+	return payload.Message
+}
@@ -0,0 +1,215 @@
+package authms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCreateSession(t *testing.T) {
+	// Mock server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/auth/session/create" {
+			t.Errorf("Expected path /auth/session/create, got %s", r.URL.Path)
+		}
+
+		var req CreateSessionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request body: %v", err)
+		}
+		if req.AuthCode != "code123" {
+			t.Errorf("Expected auth code code123, got %s", req.AuthCode)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SessionResponse{
+			SessionID:   "sess_123",
+			UserContext: UserContext{UserID: "u1", Email: "test@example.com"},
+		})
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+
+	resp, err := client.CreateSession(context.Background(), CreateSessionRequest{AuthCode: "code123"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.SessionID != "sess_123" {
+		t.Errorf("Expected session ID sess_123, got %s", resp.SessionID)
+	}
+	if resp.UserContext.Email != "test@example.com" {
+		t.Errorf("Expected user context email, got %s", resp.UserContext.Email)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusBadGateway, ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(`{"error":"nope"}`))
+		}))
+
+		client := New(server.URL).WithRetries(0, 0)
+		_, err := client.RefreshSession(context.Background(), RefreshSessionRequest{SessionID: "s"})
+		server.Close()
+
+		if !errors.Is(err, tt.want) {
+			t.Errorf("status %d: expected %v, got %v", tt.status, tt.want, err)
+		}
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.Message != "nope" {
+			t.Errorf("status %d: expected *Error with message, got %v", tt.status, err)
+		}
+	}
+}
+
+func TestRetriesIdempotentCalls(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(SessionResponse{Success: true})
+	}))
+	defer server.Close()
+
+	client := New(server.URL).WithRetries(2, time.Millisecond)
+
+	if _, err := client.RefreshSession(context.Background(), RefreshSessionRequest{SessionID: "s"}); err != nil {
+		t.Fatalf("Expected refresh to succeed after retries, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+
+	// Code exchange is not idempotent and must not be retried
+	atomic.StoreInt32(&calls, 0)
+	if _, err := client.CreateSession(context.Background(), CreateSessionRequest{AuthCode: "c"}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single create attempt, got %d", calls)
+	}
+}
+
+func TestRequestHonoursContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := New(server.URL).WithRetries(5, time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := client.RevokeSession(ctx, RevokeSessionRequest{SessionID: "s"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context deadline error, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected retries to stop when the context ends")
+	}
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	var calls int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(SessionResponse{Success: true})
+	}))
+	defer server.Close()
+
+	client := New(server.URL).WithRetries(0, 0).WithBreaker(2, 50*time.Millisecond)
+	refresh := func() error {
+		_, err := client.RefreshSession(context.Background(), RefreshSessionRequest{SessionID: "s"})
+		return err
+	}
+
+	refresh()
+	refresh()
+	if state := client.BreakerStatus().State; state != BreakerOpen {
+		t.Fatalf("Expected breaker to open after 2 failures, got %s", state)
+	}
+
+	// While open, calls fail fast without reaching the service
+	if err := refresh(); !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrCircuitOpen wrapping ErrUnavailable, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected no call while open, got %d calls", calls)
+	}
+
+	// After the cooldown a probe goes through and closes the breaker
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	if err := refresh(); err != nil {
+		t.Fatalf("Expected probe to succeed, got %v", err)
+	}
+	if state := client.BreakerStatus().State; state != BreakerClosed {
+		t.Errorf("Expected breaker to close after a successful probe, got %s", state)
+	}
+}
+
+func TestCancelledProbeLeavesBreakerHalfOpen(t *testing.T) {
+	var healthy atomic.Bool
+	hang := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		// Hang until the caller gives up
+		select {
+		case <-r.Context().Done():
+		case <-hang:
+		}
+	}))
+	defer server.Close()
+	defer close(hang)
+
+	client := New(server.URL).WithRetries(0, 0).WithBreaker(1, 20*time.Millisecond)
+	client.RefreshSession(context.Background(), RefreshSessionRequest{SessionID: "s"})
+	if state := client.BreakerStatus().State; state != BreakerOpen {
+		t.Fatalf("Expected breaker to open, got %s", state)
+	}
+
+	healthy.Store(true)
+	time.Sleep(30 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := client.RefreshSession(ctx, RefreshSessionRequest{SessionID: "s"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the probe to be cancelled, got %v", err)
+	}
+
+	// A cancelled probe proves nothing: the breaker stays half-open and frees the probe slot
+	if state := client.BreakerStatus().State; state != BreakerHalfOpen {
+		t.Errorf("Expected breaker to stay half-open after a cancelled probe, got %s", state)
+	}
+	if !client.breaker.Allow() {
+		t.Error("Expected the probe slot to be released")
+	}
+}
@@ -0,0 +1,52 @@
+package authms
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrUnauthorized is returned when the auth service rejects the code or session (401).
+	ErrUnauthorized = errors.New("auth service: unauthorized")
+	// ErrNotFound is returned when the session does not exist (404).
+	ErrNotFound = errors.New("auth service: not found")
+	// ErrUnavailable is returned for 5xx responses and transport failures.
+	ErrUnavailable = errors.New("auth service: unavailable")
+	// ErrCircuitOpen is returned without contacting the auth service while the breaker is open.
+	// It wraps ErrUnavailable.
+	ErrCircuitOpen = fmt.Errorf("%w: circuit open", ErrUnavailable)
+)
+
+// Error is a non-2xx response from the auth service. It unwraps to the
+// matching sentinel so callers can use errors.Is.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("auth service error (status %d)", e.StatusCode)
+	}
+	return fmt.Sprintf("auth service error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// Unwrap maps the status code to a sentinel error.
+func (e *Error) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode >= 500:
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// IsRejected reports whether the auth service answered that the session or code is not valid,
+// as opposed to being unreachable.
+func IsRejected(err error) bool {
+	return errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrNotFound)
+}
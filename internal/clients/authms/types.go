@@ -0,0 +1,41 @@
+package authms
+
+// CreateSessionRequest exchanges an OAuth authorization code for a session.
+type CreateSessionRequest struct {
+	AuthCode string `json:"auth_code"`
+}
+
+// RefreshSessionRequest validates and refreshes an existing session.
+type RefreshSessionRequest struct {
+	SessionID string `json:"session_id"`
+}
+
+// RevokeSessionRequest revokes a session, or every session of its owner.
+type RevokeSessionRequest struct {
+	SessionID string `json:"session_id"`
+}
+
+// UserContext is the user attached to a session by the auth service.
+type UserContext struct {
+	UserID  string `json:"user_id"`
+	Name    string `json:"name"`
+	Email   string `json:"email,omitempty"`
+	Picture string `json:"picture,omitempty"`
+}
+
+// SessionResponse is returned by session create and refresh.
+type SessionResponse struct {
+	Success     bool        `json:"success"`
+	SessionID   string      `json:"session_id"`
+	UserContext UserContext `json:"user_context"`
+}
+
+// HasUser reports whether the response carries user details.
+func (r *SessionResponse) HasUser() bool {
+	return r.UserContext.UserID != "" || r.UserContext.Email != ""
+}
+
+// RevokeResponse is returned by the revoke endpoints.
+type RevokeResponse struct {
+	Success bool `json:"success"`
+}
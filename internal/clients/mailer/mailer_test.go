@@ -0,0 +1,87 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWithoutHostLogs(t *testing.T) {
+	m := New("", 587, "", "", "no-reply@example.com")
+	if _, ok := m.(LogMailer); !ok {
+		t.Fatalf("Expected a LogMailer without SMTP host, got %T", m)
+	}
+	if err := m.Send(context.Background(), Message{To: "user@example.com", Subject: "Hi", Body: "Hello"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	from := &mail.Address{Name: "Startup Platform", Address: "no-reply@example.com"}
+	to := &mail.Address{Address: "user@example.com"}
+	date := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	raw := string(buildMessage(from, to, Message{Subject: "Your trial ends in 3 days – don't miss out", Body: "Hello\nBye"}, date))
+
+	head, body, ok := strings.Cut(raw, "\r\n\r\n")
+	if !ok {
+		t.Fatalf("Expected a blank line between headers and body:\n%s", raw)
+	}
+	for _, want := range []string{
+		`From: "Startup Platform" <no-reply@example.com>`,
+		"To: <user@example.com>",
+		"Subject: =?utf-8?q?",
+		"Date: Sun, 01 Mar 2026 09:00:00 +0000",
+		"Content-Type: text/plain; charset=utf-8",
+	} {
+		if !strings.Contains(head, want) {
+			t.Errorf("Expected header %q in:\n%s", want, head)
+		}
+	}
+	if body != "Hello\r\nBye" {
+		t.Errorf("Expected CRLF line endings in the body, got %q", body)
+	}
+}
+
+func TestSMTPMailerRejectsInvalidRecipient(t *testing.T) {
+	m := New("smtp.example.com", 587, "", "", "no-reply@example.com")
+	if err := m.Send(context.Background(), Message{To: "not an address"}); err == nil {
+		t.Error("Expected an error for an invalid recipient")
+	}
+}
+
+func TestSMTPMailerGivesUpOnSilentServer(t *testing.T) {
+	// Accepts connections but never sends the SMTP greeting
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	m := New(host, portNumber, "", "", "no-reply@example.com")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := m.Send(ctx, Message{To: "user@example.com", Subject: "Hi", Body: "Hello"}); err == nil {
+		t.Fatal("Expected an error from a server that never answers")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected Send to give up at the context deadline, took %s", elapsed)
+	}
+}
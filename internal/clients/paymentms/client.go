@@ -4,17 +4,34 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"net/url"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+// ProjectHeader names the project a call is made for when several projects share
+// one payment service.
+const ProjectHeader = "X-Project-ID"
+
+// maxRetryWait is the longest Retry-After a call waits out; longer ones fail
+// the call with ErrRateLimited instead of holding the request open. Calls are
+// made while serving requests, so it stays well below the server's write timeout.
+const maxRetryWait = 3 * time.Second
+
 // Client is the client for the Payment Microservice.
 type Client struct {
 	baseURL    string
 	apiKey     string
+	projectID  string
 	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
 }
 
 // New creates a new Payment MS client.
@@ -25,140 +42,304 @@ func New(baseURL, apiKey string) *Client {
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		maxRetries: 2,
+		backoff:    200 * time.Millisecond,
 	}
 }
 
+// WithRetries sets how often safe calls are retried and the base backoff.
+func (c *Client) WithRetries(maxRetries int, backoff time.Duration) *Client {
+	c.maxRetries = maxRetries
+	c.backoff = backoff
+	return c
+}
+
+// WithProject sets the project identifier sent on every call.
+func (c *Client) WithProject(projectID string) *Client {
+	c.projectID = projectID
+	return c
+}
+
+// ProjectID returns the project identifier sent on every call.
+func (c *Client) ProjectID() string {
+	return c.projectID
+}
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey makes the checkout and subscription change calls made with ctx
+// use key instead of a generated one, so a client retrying the same submission gets
+// the same session or change.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
 // CreateCartCheckout creates a checkout session for multiple items.
 func (c *Client) CreateCartCheckout(ctx context.Context, req CartCheckoutRequest) (*CheckoutResponse, error) {
-	return c.doRequest(ctx, "POST", "/api/v1/checkout/cart", req)
+	return c.createCheckout(ctx, "/api/v1/checkout/cart", req)
 }
 
 // CreateItemCheckout creates a checkout session for a single item.
 func (c *Client) CreateItemCheckout(ctx context.Context, req ItemCheckoutRequest) (*CheckoutResponse, error) {
-	return c.doRequest(ctx, "POST", "/api/v1/checkout/item", req)
+	return c.createCheckout(ctx, "/api/v1/checkout/item", req)
 }
 
 // CreateSubscriptionCheckout creates a checkout session for a subscription.
 func (c *Client) CreateSubscriptionCheckout(ctx context.Context, req SubscriptionCheckoutRequest) (*CheckoutResponse, error) {
-	return c.doRequest(ctx, "POST", "/api/v1/checkout/subscription", req)
+	return c.createCheckout(ctx, "/api/v1/checkout/subscription", req)
 }
 
 // GetSubscriptionStatus retrieves the subscription status for a user and product.
+// A user without a subscription yields an error matching ErrNotFound.
 func (c *Client) GetSubscriptionStatus(ctx context.Context, userID, productID string) (*SubscriptionStatusResponse, error) {
-	path := fmt.Sprintf("/api/v1/subscriptions/%s/%s", userID, productID)
+	path := fmt.Sprintf("/api/v1/subscriptions/%s/%s", url.PathEscape(userID), url.PathEscape(productID))
 
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var result SubscriptionStatusResponse
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &result, ""); err != nil {
+		return nil, err
 	}
+	return &result, nil
+}
 
-	c.addHeaders(req)
+// ListSubscriptions retrieves all of a user's subscriptions across every project
+// sharing the payment service, not only the client's own project.
+func (c *Client) ListSubscriptions(ctx context.Context, userID string) ([]UserSubscription, error) {
+	path := fmt.Sprintf("/api/v1/users/%s/subscriptions", url.PathEscape(userID))
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to perform request: %w", err)
+	var result UserSubscriptionsResponse
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &result, ""); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return result.Subscriptions, nil
+}
+
+// ListInvoices retrieves a user's most recent invoices, newest first.
+// A user without a billing customer yields an error matching ErrNotFound.
+func (c *Client) ListInvoices(ctx context.Context, userID string, limit int) ([]Invoice, error) {
+	path := fmt.Sprintf("/api/v1/users/%s/invoices?limit=%d", url.PathEscape(userID), limit)
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("payment ms error (status %d): %s", resp.StatusCode, string(body))
+	var result InvoicesResponse
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &result, ""); err != nil {
+		return nil, err
 	}
+	return result.Invoices, nil
+}
 
-	var result SubscriptionStatusResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// ListPaymentMethods retrieves the cards saved for a user.
+// A user without a billing customer yields an error matching ErrNotFound.
+func (c *Client) ListPaymentMethods(ctx context.Context, userID string) ([]PaymentMethod, error) {
+	path := fmt.Sprintf("/api/v1/users/%s/payment-methods", url.PathEscape(userID))
+
+	var result PaymentMethodsResponse
+	if err := c.doRequest(ctx, http.MethodGet, path, nil, &result, ""); err != nil {
+		return nil, err
 	}
+	return result.PaymentMethods, nil
+}
+
+// CancelSubscription cancels a subscription at the end of its current period.
+// The subscription keeps granting access until then and can be resumed.
+func (c *Client) CancelSubscription(ctx context.Context, subscriptionID string) (*SubscriptionStatusResponse, error) {
+	return c.changeSubscription(ctx, subscriptionID, "cancel", CancelSubscriptionRequest{AtPeriodEnd: true})
+}
 
+// ResumeSubscription undoes a pending cancellation made with CancelSubscription.
+func (c *Client) ResumeSubscription(ctx context.Context, subscriptionID string) (*SubscriptionStatusResponse, error) {
+	return c.changeSubscription(ctx, subscriptionID, "resume", nil)
+}
+
+// PreviewSubscriptionChange returns the prorated cost of moving a subscription to priceID now.
+func (c *Client) PreviewSubscriptionChange(ctx context.Context, subscriptionID, priceID string) (*ProrationPreview, error) {
+	path := fmt.Sprintf("/api/v1/subscriptions/%s/preview-change", url.PathEscape(subscriptionID))
+
+	var result ProrationPreview
+	if err := c.doRequest(ctx, http.MethodPost, path, ChangeSubscriptionRequest{PriceID: priceID}, &result, ""); err != nil {
+		return nil, err
+	}
 	return &result, nil
 }
 
-// CreateCustomerPortal creates a session for the customer portal.
-// Note: The spec didn't explicitly detail the request/response for portal, assuming standard return_url pattern.
-func (c *Client) CreateCustomerPortal(ctx context.Context, userID string, returnURL string) (string, error) {
-	// Assuming the endpoint expects a JSON body with return_url or similar,
-	// and returns a URL. Based on common Stripe patterns.
-	// If the MS endpoint is just POST /api/v1/portal with some body:
+// ChangeSubscriptionPrice moves a subscription to another price, prorating the
+// current period as of prorationDate (from PreviewSubscriptionChange; zero for now).
+func (c *Client) ChangeSubscriptionPrice(ctx context.Context, subscriptionID, priceID string, prorationDate time.Time) (*SubscriptionStatusResponse, error) {
+	return c.changeSubscription(ctx, subscriptionID, "change", ChangeSubscriptionRequest{
+		PriceID:       priceID,
+		ProrationDate: prorationDate,
+	})
+}
 
-	// NOTE: The user provided spec for /api/v1/portal was empty in details.
-	// I will assume it takes a user_id and return_url in the body.
-	// Adjusting based on typical needs.
+// ValidatePromotionCode checks whether a promotion code applies to a price and
+// returns its discount. An unknown code yields an error matching ErrNotFound.
+func (c *Client) ValidatePromotionCode(ctx context.Context, req PromotionCodeRequest) (*PromotionCodeValidation, error) {
+	var result PromotionCodeValidation
+	if err := c.doRequest(ctx, http.MethodPost, "/api/v1/promotion-codes/validate", req, &result, ""); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
 
+// ListPromotionCodes retrieves the project's promotion codes with their redemption counts.
+func (c *Client) ListPromotionCodes(ctx context.Context) ([]PromotionCode, error) {
+	var result PromotionCodesResponse
+	if err := c.doRequest(ctx, http.MethodGet, "/api/v1/promotion-codes", nil, &result, ""); err != nil {
+		return nil, err
+	}
+	return result.PromotionCodes, nil
+}
+
+// CreateCustomerPortal creates a session for the customer portal and returns its URL.
+// Portal sessions are short-lived and cheap, so this call is not retried.
+func (c *Client) CreateCustomerPortal(ctx context.Context, userID string, returnURL string) (string, error) {
 	reqBody := map[string]string{
 		"user_id":    userID,
 		"return_url": returnURL,
 	}
 
-	reqBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+	var result PortalResponse
+	if err := c.doRequest(ctx, http.MethodPost, "/api/v1/portal", reqBody, &result, ""); err != nil {
+		return "", err
 	}
+	return result.URL, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/v1/portal", bytes.NewBuffer(reqBytes))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+// createCheckout POSTs a checkout request with an idempotency key, which makes it safe to retry.
+func (c *Client) createCheckout(ctx context.Context, path string, payload interface{}) (*CheckoutResponse, error) {
+	var result CheckoutResponse
+	if err := c.doRequest(ctx, http.MethodPost, path, payload, &result, idempotencyKey(ctx)); err != nil {
+		return nil, err
 	}
+	return &result, nil
+}
 
-	c.addHeaders(req)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to perform request: %w", err)
-	}
-	defer resp.Body.Close()
+// changeSubscription POSTs a subscription action with an idempotency key and returns the updated subscription.
+func (c *Client) changeSubscription(ctx context.Context, subscriptionID, action string, payload interface{}) (*SubscriptionStatusResponse, error) {
+	path := fmt.Sprintf("/api/v1/subscriptions/%s/%s", url.PathEscape(subscriptionID), action)
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("payment ms error (status %d): %s", resp.StatusCode, string(body))
+	var result SubscriptionStatusResponse
+	if err := c.doRequest(ctx, http.MethodPost, path, payload, &result, idempotencyKey(ctx)); err != nil {
+		return nil, err
 	}
+	return &result, nil
+}
 
-	var result PortalResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		// Fallback if response is just the URL string or different structure
-		// But for now assuming JSON
-		return "", fmt.Errorf("failed to decode response: %w", err)
+// idempotencyKey returns the key set with WithIdempotencyKey, or a new one
+func idempotencyKey(ctx context.Context) string {
+	if key, _ := ctx.Value(idempotencyKeyCtx{}).(string); key != "" {
+		return key
 	}
-
-	return result.URL, nil
+	return uuid.NewString()
 }
 
-func (c *Client) doRequest(ctx context.Context, method, path string, payload interface{}) (*CheckoutResponse, error) {
-	var body io.Reader
+// doRequest sends the request and decodes the response into result.
+// GETs and requests carrying an idempotency key are retried with jittered
+// exponential backoff when the service is unavailable or rate limiting us.
+func (c *Client) doRequest(ctx context.Context, method, path string, payload, result interface{}, idempotencyKey string) error {
+	var body []byte
 	if payload != nil {
 		jsonBytes, err := json.Marshal(payload)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		body = bytes.NewBuffer(jsonBytes)
+		body = jsonBytes
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	attempts := 1
+	if method == http.MethodGet || idempotencyKey != "" {
+		attempts += c.maxRetries
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := c.send(ctx, method, path, body, result, idempotencyKey)
+		if err == nil || attempt >= attempts || !retryable(err) {
+			return err
+		}
+
+		wait := c.retryDelay(attempt, err)
+		if !canWait(ctx, wait) {
+			fmt.Printf("💳 PAYMENT-MS: %s %s failed, not retrying after %s\n", method, path, wait)
+			return err
+		}
+		fmt.Printf("💳 PAYMENT-MS: %s %s failed (attempt %d/%d), retrying in %s\n", method, path, attempt, attempts, wait)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func retryable(err error) bool {
+	return errors.Is(err, ErrUnavailable) || errors.Is(err, ErrRateLimited)
+}
+
+// retryDelay uses full jitter over an exponential ceiling, but never waits
+// less than the Retry-After the service asked for.
+func (c *Client) retryDelay(attempt int, err error) time.Duration {
+	ceiling := c.backoff << (attempt - 1)
+	wait := time.Duration(0)
+	if ceiling > 0 {
+		wait = time.Duration(rand.Int63n(int64(ceiling) + 1))
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > wait {
+		wait = apiErr.RetryAfter
+	}
+	return wait
+}
+
+// canWait reports whether a retry after wait stays within maxRetryWait and
+// the context's deadline
+func canWait(ctx context.Context, wait time.Duration) bool {
+	if wait > maxRetryWait {
+		return false
+	}
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > wait
+}
+
+// send performs a single request
+func (c *Client) send(ctx context.Context, method, path string, body []byte, result interface{}, idempotencyKey string) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	c.addHeaders(req)
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to perform request: %w", err)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("payment ms error (status %d): %s", resp.StatusCode, string(respBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return newError(resp, respBody)
 	}
 
-	var result CheckoutResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &result, nil
+	return nil
 }
 
 func (c *Client) addHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-API-Key", c.apiKey)
+	if c.projectID != "" {
+		req.Header.Set(ProjectHeader, c.projectID)
+	}
 }
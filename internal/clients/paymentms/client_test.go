@@ -3,8 +3,11 @@ package paymentms
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -52,6 +55,288 @@ func TestGetSubscriptionStatus(t *testing.T) {
 	}
 }
 
+func TestListSubscriptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Expected GET request, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1/users/user123/subscriptions" {
+			t.Errorf("Expected path /api/v1/users/user123/subscriptions, got %s", r.URL.Path)
+		}
+
+		resp := UserSubscriptionsResponse{Subscriptions: []UserSubscription{
+			{ProjectID: "startup_platform", SubscriptionID: "sub_1", ProductID: "prod_a", Status: "active"},
+			{ProjectID: "analytics_dashboard", SubscriptionID: "sub_2", ProductID: "prod_b", Status: "trialing"},
+		}}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key")
+
+	subs, err := client.ListSubscriptions(context.Background(), "user123")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(subs) != 2 || subs[1].ProjectID != "analytics_dashboard" {
+		t.Errorf("Expected subscriptions from both projects, got %+v", subs)
+	}
+}
+
+func TestListInvoices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/users/user123/invoices" {
+			t.Errorf("Expected path /api/v1/users/user123/invoices, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("limit") != "12" {
+			t.Errorf("Expected limit 12, got %q", r.URL.Query().Get("limit"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"invoices":[{"id":"in_1","number":"INV-0001","created":"2026-03-01T10:00:00Z","amount_paid":2900,"currency":"usd","status":"paid","invoice_pdf":"https://pay.example.com/in_1.pdf"}]}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key")
+
+	invoices, err := client.ListInvoices(context.Background(), "user123", 12)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(invoices) != 1 || invoices[0].PDFURL != "https://pay.example.com/in_1.pdf" || invoices[0].AmountPaid != 2900 {
+		t.Errorf("Unexpected invoices: %+v", invoices)
+	}
+}
+
+func TestListPaymentMethods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/users/user123/payment-methods" {
+			t.Errorf("Expected path /api/v1/users/user123/payment-methods, got %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"payment_methods":[{"id":"pm_1","brand":"visa","last4":"4242","exp_month":4,"exp_year":2030,"default":true}]}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key")
+
+	methods, err := client.ListPaymentMethods(context.Background(), "user123")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(methods) != 1 || methods[0].Last4 != "4242" || !methods[0].Default {
+		t.Errorf("Unexpected payment methods: %+v", methods)
+	}
+}
+
+func TestProjectHeaderSentOnEveryCall(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Header.Get(ProjectHeader) != "startup_platform" {
+			t.Errorf("%s %s: expected project header, got %q", r.Method, r.URL.Path, r.Header.Get(ProjectHeader))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key").WithProject("startup_platform")
+	ctx := context.Background()
+
+	client.GetSubscriptionStatus(ctx, "user123", "prod456")
+	client.ListSubscriptions(ctx, "user123")
+	client.ListInvoices(ctx, "user123", 10)
+	client.ListPaymentMethods(ctx, "user123")
+	client.CreateSubscriptionCheckout(ctx, SubscriptionCheckoutRequest{UserID: "user123"})
+	client.CreateCartCheckout(ctx, CartCheckoutRequest{UserID: "user123"})
+	client.CreateCustomerPortal(ctx, "user123", "http://localhost/settings")
+	client.CancelSubscription(ctx, "sub_1")
+	client.PreviewSubscriptionChange(ctx, "sub_1", "price_year")
+	client.ValidatePromotionCode(ctx, PromotionCodeRequest{Code: "LAUNCH20", PriceID: "price_month"})
+	client.ListPromotionCodes(ctx)
+
+	if got := atomic.LoadInt32(&calls); got != 11 {
+		t.Errorf("Expected 11 calls, got %d", got)
+	}
+}
+
+func TestCancelAndResumeSubscription(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if r.Header.Get("Idempotency-Key") == "" {
+			t.Error("Expected an idempotency key")
+		}
+
+		cancelling := strings.HasSuffix(r.URL.Path, "/cancel")
+		if cancelling {
+			var body CancelSubscriptionRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || !body.AtPeriodEnd {
+				t.Errorf("Expected at_period_end in cancel body, got %+v (%v)", body, err)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SubscriptionStatusResponse{
+			SubscriptionID:    "sub_1",
+			Status:            "active",
+			CancelAtPeriodEnd: cancelling,
+		})
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key")
+
+	cancelled, err := client.CancelSubscription(context.Background(), "sub_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !cancelled.CancelAtPeriodEnd {
+		t.Error("Expected the cancellation to be pending")
+	}
+
+	resumed, err := client.ResumeSubscription(context.Background(), "sub_1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resumed.CancelAtPeriodEnd {
+		t.Error("Expected the cancellation to be undone")
+	}
+
+	want := []string{"/api/v1/subscriptions/sub_1/cancel", "/api/v1/subscriptions/sub_1/resume"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected paths %v, got %v", want, paths)
+	}
+}
+
+func TestSubscriptionChangeUsesPreviewProrationDate(t *testing.T) {
+	prorationDate := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body ChangeSubscriptionRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode body: %v", err)
+		}
+		if body.PriceID != "price_year" {
+			t.Errorf("Expected price_year, got %s", body.PriceID)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/subscriptions/sub_1/preview-change":
+			json.NewEncoder(w).Encode(ProrationPreview{AmountDue: 26100, Currency: "usd", ProrationDate: prorationDate})
+		case "/api/v1/subscriptions/sub_1/change":
+			if !body.ProrationDate.Equal(prorationDate) {
+				t.Errorf("Expected proration date %v, got %v", prorationDate, body.ProrationDate)
+			}
+			json.NewEncoder(w).Encode(SubscriptionStatusResponse{SubscriptionID: "sub_1", PriceID: "price_year", Status: "active"})
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key")
+
+	preview, err := client.PreviewSubscriptionChange(context.Background(), "sub_1", "price_year")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if preview.AmountDue != 26100 {
+		t.Errorf("Expected amount due 26100, got %d", preview.AmountDue)
+	}
+
+	status, err := client.ChangeSubscriptionPrice(context.Background(), "sub_1", "price_year", preview.ProrationDate)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status.PriceID != "price_year" {
+		t.Errorf("Expected price_year, got %s", status.PriceID)
+	}
+}
+
+func TestValidatePromotionCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/promotion-codes/validate" {
+			t.Errorf("Expected POST /api/v1/promotion-codes/validate, got %s %s", r.Method, r.URL.Path)
+		}
+		var body PromotionCodeRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if body.Code != "LAUNCH20" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"promotion code not found"}`))
+			return
+		}
+		w.Write([]byte(`{"valid":true,"id":"promo_1","code":"LAUNCH20","percent_off":20,"duration":"repeating","duration_in_months":3}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key")
+
+	result, err := client.ValidatePromotionCode(context.Background(), PromotionCodeRequest{Code: "LAUNCH20", PriceID: "price_month", ProductID: "prod_pro"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.Valid || result.ID != "promo_1" || result.PercentOff != 20 || result.DurationInMonths != 3 {
+		t.Errorf("Unexpected validation: %+v", result)
+	}
+
+	_, err = client.ValidatePromotionCode(context.Background(), PromotionCodeRequest{Code: "NOPE", PriceID: "price_month"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown code, got %v", err)
+	}
+}
+
+func TestListPromotionCodes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/promotion-codes" {
+			t.Errorf("Expected path /api/v1/promotion-codes, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"promotion_codes":[{"id":"promo_1","code":"LAUNCH20","active":true,"percent_off":20,"times_redeemed":7,"max_redemptions":100}]}`))
+	}))
+	defer server.Close()
+
+	codes, err := New(server.URL, "test-key").ListPromotionCodes(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(codes) != 1 || codes[0].TimesRedeemed != 7 || codes[0].MaxRedemptions != 100 {
+		t.Errorf("Unexpected promotion codes: %+v", codes)
+	}
+}
+
+func TestPromotionCodeApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		promo PromotionCode
+		want  int64
+	}{
+		{"percent", PromotionCode{PercentOff: 20}, 2320},
+		{"percent_rounds", PromotionCode{PercentOff: 33.3}, 1934},
+		{"amount", PromotionCode{AmountOff: 500, Currency: "USD"}, 2400},
+		{"amount_other_currency", PromotionCode{AmountOff: 500, Currency: "eur"}, 2900},
+		{"amount_exceeds_price", PromotionCode{AmountOff: 5000, Currency: "usd"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.promo.Apply(2900, "usd"); got != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestCreateSubscriptionCheckout(t *testing.T) {
 	// Mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -141,3 +426,176 @@ func TestCreateCustomerPortal(t *testing.T) {
 		t.Errorf("Expected portal URL, got %s", url)
 	}
 }
+
+func TestTypedErrors(t *testing.T) {
+	tests := []struct {
+		status   int
+		sentinel error
+		httpCode int
+	}{
+		{http.StatusNotFound, ErrNotFound, http.StatusNotFound},
+		{http.StatusUnauthorized, ErrUnauthorized, http.StatusBadGateway},
+		{http.StatusForbidden, ErrUnauthorized, http.StatusBadGateway},
+		{http.StatusUnprocessableEntity, ErrValidation, http.StatusBadRequest},
+		{http.StatusTooManyRequests, ErrRateLimited, http.StatusTooManyRequests},
+		{http.StatusBadGateway, ErrUnavailable, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(`{"error":"nope"}`))
+		}))
+
+		client := New(server.URL, "test-key").WithRetries(0, 0)
+		_, err := client.CreateCustomerPortal(context.Background(), "user123", "http://localhost/return")
+		server.Close()
+
+		if !errors.Is(err, tt.sentinel) {
+			t.Errorf("Status %d: expected %v, got %v", tt.status, tt.sentinel, err)
+		}
+		if got := HTTPStatus(err); got != tt.httpCode {
+			t.Errorf("Status %d: expected HTTP status %d, got %d", tt.status, tt.httpCode, got)
+		}
+	}
+}
+
+func TestValidationErrorFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid request","fields":{"price_id":"unknown price"}}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key")
+	_, err := client.CreateSubscriptionCheckout(context.Background(), SubscriptionCheckoutRequest{PriceID: "price_bad"})
+
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected ErrValidation, got %v", err)
+	}
+	if fields := FieldErrors(err); fields["price_id"] != "unknown price" {
+		t.Errorf("Expected price_id field error, got %v", fields)
+	}
+}
+
+func TestGetSubscriptionStatusRetriesWhenUnavailable(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(SubscriptionStatusResponse{Status: "active"})
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key").WithRetries(2, time.Millisecond)
+	status, err := client.GetSubscriptionStatus(context.Background(), "user123", "prod456")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status.Status != "active" {
+		t.Errorf("Expected status active, got %s", status.Status)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", calls)
+	}
+}
+
+func TestCheckoutRetriesReuseIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(CheckoutResponse{CheckoutSessionID: "cs_test_123"})
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key").WithRetries(2, time.Millisecond)
+	if _, err := client.CreateSubscriptionCheckout(context.Background(), SubscriptionCheckoutRequest{UserID: "user123"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 calls, got %d", len(keys))
+	}
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("Expected the same idempotency key on both attempts, got %q and %q", keys[0], keys[1])
+	}
+
+	keys = nil
+	ctx := WithIdempotencyKey(context.Background(), "checkout-abc")
+	if _, err := client.CreateSubscriptionCheckout(ctx, SubscriptionCheckoutRequest{UserID: "user123"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if keys[len(keys)-1] != "checkout-abc" {
+		t.Errorf("Expected the supplied idempotency key, got %q", keys[len(keys)-1])
+	}
+}
+
+func TestPortalIsNotRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key").WithRetries(2, time.Millisecond)
+	if _, err := client.CreateCustomerPortal(context.Background(), "user123", "http://localhost/return"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
+
+func TestRateLimitHonoursRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key").WithRetries(0, 0)
+	_, err := client.GetSubscriptionStatus(context.Background(), "user123", "prod456")
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 7*time.Second {
+		t.Errorf("Expected Retry-After of 7s, got %v", err)
+	}
+	if got := client.retryDelay(1, err); got < 7*time.Second {
+		t.Errorf("Expected retry delay of at least 7s, got %s", got)
+	}
+}
+
+func TestRateLimitFailsFastWhenRetryAfterIsTooLong(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key").WithRetries(2, time.Millisecond)
+
+	// Waiting would outlast the caller's deadline
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetSubscriptionStatus(ctx, "user123", "prod456"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+	if calls != 1 || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected one call without waiting, got %d calls in %s", calls, time.Since(start))
+	}
+
+	// Waits beyond maxRetryWait are refused without a deadline too
+	if canWait(context.Background(), maxRetryWait+time.Second) || !canWait(context.Background(), maxRetryWait) {
+		t.Error("Expected only waits up to maxRetryWait to be allowed")
+	}
+}
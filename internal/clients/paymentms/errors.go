@@ -0,0 +1,127 @@
+package paymentms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when the subscription, price or customer does not exist (404).
+	ErrNotFound = errors.New("payment ms: not found")
+	// ErrUnauthorized is returned when the payment service rejects our API key (401/403).
+	ErrUnauthorized = errors.New("payment ms: unauthorized")
+	// ErrValidation is returned when the request was rejected as invalid (400/422).
+	ErrValidation = errors.New("payment ms: validation failed")
+	// ErrRateLimited is returned when the payment service throttles us (429).
+	ErrRateLimited = errors.New("payment ms: rate limited")
+	// ErrUnavailable is returned for 5xx responses and transport failures.
+	ErrUnavailable = errors.New("payment ms: unavailable")
+)
+
+// Error is a non-2xx response from the payment service. It unwraps to the
+// matching sentinel so callers can use errors.Is.
+type Error struct {
+	StatusCode int
+	Message    string
+	Fields     map[string]string // per-field validation messages, when provided
+	RetryAfter time.Duration     // from the Retry-After header on 429/503
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("payment ms error (status %d)", e.StatusCode)
+	}
+	return fmt.Sprintf("payment ms error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// Unwrap maps the status code to a sentinel error.
+func (e *Error) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= 500:
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// newError builds an Error from a response and its (already read) body
+func newError(resp *http.Response, body []byte) *Error {
+	var payload struct {
+		Error   string            `json:"error"`
+		Message string            `json:"message"`
+		Fields  map[string]string `json:"fields"`
+		Details map[string]string `json:"details"`
+	}
+	_ = json.Unmarshal(body, &payload)
+
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		Message:    payload.Error,
+		Fields:     payload.Fields,
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = payload.Message
+	}
+	if apiErr.Fields == nil {
+		apiErr.Fields = payload.Details
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
+
+// HTTPStatus maps a client error to the status our own API should answer with.
+func HTTPStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrUnauthorized):
+		// Our credentials were rejected: a server-side problem, not the user's
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// UserMessage returns a message that is safe to show to end users.
+func UserMessage(err error) string {
+	switch {
+	case errors.Is(err, ErrValidation):
+		return "Some checkout details were not accepted. Please check them and try again."
+	case errors.Is(err, ErrNotFound):
+		return "The requested plan or subscription could not be found."
+	case errors.Is(err, ErrRateLimited):
+		return "Too many requests. Please wait a moment and try again."
+	case errors.Is(err, ErrUnavailable), errors.Is(err, ErrUnauthorized):
+		return "Payments are temporarily unavailable. Please try again in a few minutes."
+	default:
+		return "Something went wrong with the payment service. Please try again."
+	}
+}
+
+// FieldErrors returns per-field validation messages, if the error carries any.
+func FieldErrors(err error) map[string]string {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Fields
+	}
+	return nil
+}
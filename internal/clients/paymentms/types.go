@@ -1,6 +1,10 @@
 package paymentms
 
-import "time"
+import (
+	"math"
+	"strings"
+	"time"
+)
 
 // CartCheckoutRequest represents a request to create a checkout session for multiple items.
 type CartCheckoutRequest struct {
@@ -28,13 +32,16 @@ type ItemCheckoutRequest struct {
 }
 
 // SubscriptionCheckoutRequest represents a request to create a checkout session for a subscription.
+// A non-zero TrialPeriodDays starts the subscription with a free trial of that many days.
 type SubscriptionCheckoutRequest struct {
-	CancelURL  string `json:"cancel_url"`
-	Email      string `json:"email"`
-	PriceID    string `json:"price_id"`
-	ProductID  string `json:"product_id"`
-	SuccessURL string `json:"success_url"`
-	UserID     string `json:"user_id"`
+	CancelURL       string `json:"cancel_url"`
+	Email           string `json:"email"`
+	PriceID         string `json:"price_id"`
+	ProductID       string `json:"product_id"`
+	PromotionCode   string `json:"promotion_code,omitempty"`
+	SuccessURL      string `json:"success_url"`
+	TrialPeriodDays int    `json:"trial_period_days,omitempty"`
+	UserID          string `json:"user_id"`
 }
 
 // CheckoutResponse represents the response from a checkout creation request.
@@ -43,12 +50,135 @@ type CheckoutResponse struct {
 	CheckoutURL       string `json:"checkout_url"`
 }
 
-// SubscriptionStatusResponse represents the response for a subscription status check.
+// SubscriptionStatusResponse represents the response for a subscription status check
+// and for the calls that change a subscription.
 type SubscriptionStatusResponse struct {
-	CurrentPeriodEnd time.Time `json:"current_period_end"`
-	ProductID        string    `json:"product_id"`
-	Status           string    `json:"status"`
-	SubscriptionID   string    `json:"subscription_id"`
+	CancelAtPeriodEnd bool      `json:"cancel_at_period_end"`
+	CurrentPeriodEnd  time.Time `json:"current_period_end"`
+	PriceID           string    `json:"price_id"`
+	ProductID         string    `json:"product_id"`
+	Status            string    `json:"status"`
+	SubscriptionID    string    `json:"subscription_id"`
+	TrialEnd          time.Time `json:"trial_end"`
+}
+
+// CancelSubscriptionRequest represents a request to cancel a subscription.
+type CancelSubscriptionRequest struct {
+	AtPeriodEnd bool `json:"at_period_end"`
+}
+
+// ChangeSubscriptionRequest represents a request to move a subscription to another price.
+type ChangeSubscriptionRequest struct {
+	PriceID       string    `json:"price_id"`
+	ProrationDate time.Time `json:"proration_date,omitzero"`
+}
+
+// ProrationPreview represents what changing a subscription's price would cost.
+// ProrationDate should be sent back with the change so the charge matches the preview.
+type ProrationPreview struct {
+	AmountDue         int64     `json:"amount_due"`
+	Currency          string    `json:"currency"`
+	NextPaymentAmount int64     `json:"next_payment_amount"`
+	NextPaymentDate   time.Time `json:"next_payment_date"`
+	ProrationDate     time.Time `json:"proration_date"`
+}
+
+// PromotionCodeRequest asks whether a promotion code applies to a price.
+type PromotionCodeRequest struct {
+	Code      string `json:"code"`
+	PriceID   string `json:"price_id"`
+	ProductID string `json:"product_id"`
+	UserID    string `json:"user_id,omitempty"`
+}
+
+// PromotionCode represents a customer-facing code and the coupon it applies.
+// Exactly one of AmountOff and PercentOff is set.
+type PromotionCode struct {
+	Active           bool      `json:"active"`
+	AmountOff        int64     `json:"amount_off"`
+	Code             string    `json:"code"`
+	Currency         string    `json:"currency"`
+	Duration         string    `json:"duration"` // "once", "repeating" or "forever"
+	DurationInMonths int       `json:"duration_in_months"`
+	ExpiresAt        time.Time `json:"expires_at,omitzero"`
+	ID               string    `json:"id"`
+	MaxRedemptions   int       `json:"max_redemptions"` // 0 for unlimited
+	PercentOff       float64   `json:"percent_off"`
+	TimesRedeemed    int       `json:"times_redeemed"`
+}
+
+// Apply returns amount (in minor units of currency) after the discount, never below zero.
+// A fixed discount in another currency does not apply.
+func (p PromotionCode) Apply(amount int64, currency string) int64 {
+	switch {
+	case p.PercentOff > 0:
+		amount -= int64(math.Round(float64(amount) * p.PercentOff / 100))
+	case p.AmountOff > 0 && strings.EqualFold(p.Currency, currency):
+		amount -= p.AmountOff
+	}
+	return max(amount, 0)
+}
+
+// PromotionCodeValidation represents whether a promotion code applies to a price.
+type PromotionCodeValidation struct {
+	PromotionCode
+	Reason string `json:"reason"` // Why the code does not apply, when not valid
+	Valid  bool   `json:"valid"`
+}
+
+// PromotionCodesResponse represents the response listing the project's promotion codes.
+type PromotionCodesResponse struct {
+	PromotionCodes []PromotionCode `json:"promotion_codes"`
+}
+
+// UserSubscription represents one of a user's subscriptions in any project.
+type UserSubscription struct {
+	CancelAtPeriodEnd bool      `json:"cancel_at_period_end"`
+	CurrentPeriodEnd  time.Time `json:"current_period_end"`
+	PriceID           string    `json:"price_id"`
+	ProductID         string    `json:"product_id"`
+	ProjectID         string    `json:"project_id"`
+	Status            string    `json:"status"`
+	SubscriptionID    string    `json:"subscription_id"`
+	TrialEnd          time.Time `json:"trial_end"`
+}
+
+// UserSubscriptionsResponse represents the response listing a user's subscriptions.
+type UserSubscriptionsResponse struct {
+	Subscriptions []UserSubscription `json:"subscriptions"`
+}
+
+// Invoice represents a billed invoice.
+type Invoice struct {
+	AmountDue  int64     `json:"amount_due"`
+	AmountPaid int64     `json:"amount_paid"`
+	Created    time.Time `json:"created"`
+	Currency   string    `json:"currency"`
+	HostedURL  string    `json:"hosted_invoice_url"`
+	ID         string    `json:"id"`
+	Number     string    `json:"number"`
+	PDFURL     string    `json:"invoice_pdf"`
+	Status     string    `json:"status"`
+}
+
+// InvoicesResponse represents the response listing a user's invoices.
+type InvoicesResponse struct {
+	Invoices []Invoice `json:"invoices"`
+}
+
+// PaymentMethod represents a card saved for a customer.
+type PaymentMethod struct {
+	Brand    string `json:"brand"`
+	Default  bool   `json:"default"`
+	ExpMonth int    `json:"exp_month"`
+	ExpYear  int    `json:"exp_year"`
+	ID       string `json:"id"`
+	Last4    string `json:"last4"`
+}
+
+// PaymentMethodsResponse represents the response listing a user's payment methods.
+type PaymentMethodsResponse struct {
+	PaymentMethods []PaymentMethod `json:"payment_methods"`
 }
 
 // PortalRequest represents a request to create a customer portal session.
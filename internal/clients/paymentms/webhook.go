@@ -0,0 +1,151 @@
+package paymentms
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the webhook signature: "t=<unix seconds>,v1=<hex HMAC-SHA256>".
+// The HMAC is computed over "<t>.<raw body>" with the shared webhook secret.
+const SignatureHeader = "X-Payment-Signature"
+
+// DefaultSignatureTolerance is how old a signed webhook may be before it is refused as a replay.
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// Webhook event types sent by the payment service
+const (
+	EventSubscriptionCreated   = "subscription.created"
+	EventSubscriptionUpdated   = "subscription.updated"
+	EventSubscriptionCancelled = "subscription.cancelled"
+	EventInvoicePaid           = "invoice.paid"
+	EventInvoicePaymentFailed  = "invoice.payment_failed"
+	EventCheckoutCompleted     = "checkout.completed"
+)
+
+var (
+	// ErrInvalidSignature is returned when a webhook signature is missing, malformed or wrong.
+	ErrInvalidSignature = errors.New("payment webhook: invalid signature")
+	// ErrSignatureExpired is returned when a correctly signed webhook is outside the tolerance.
+	ErrSignatureExpired = errors.New("payment webhook: signature timestamp outside tolerance")
+)
+
+// WebhookEvent is a notification sent by the payment service.
+type WebhookEvent struct {
+	ID      string           `json:"id"`
+	Type    string           `json:"type"`
+	Created int64            `json:"created"`
+	Data    WebhookEventData `json:"data"`
+}
+
+// WebhookEventData is the object the event is about.
+// Subscription events fill the subscription fields, invoice events the invoice ones
+// and checkout events the checkout session.
+type WebhookEventData struct {
+	// ProjectID is the project the event belongs to when the service is shared
+	ProjectID        string    `json:"project_id"`
+	UserID           string    `json:"user_id"`
+	Email            string    `json:"email"`
+	CustomerID       string    `json:"customer_id"`
+	SubscriptionID   string    `json:"subscription_id"`
+	ProductID        string    `json:"product_id"`
+	PriceID          string    `json:"price_id"`
+	Status           string    `json:"status"`
+	CurrentPeriodEnd time.Time `json:"current_period_end"`
+	// CancelAtPeriodEnd is set while a cancellation is pending
+	CancelAtPeriodEnd bool `json:"cancel_at_period_end"`
+	// TrialEnd is when a trialing subscription converts to paid
+	TrialEnd   time.Time `json:"trial_end"`
+	InvoiceID  string    `json:"invoice_id"`
+	AmountPaid int64     `json:"amount_paid"`
+	Currency   string    `json:"currency"`
+	// CheckoutSessionID is the checkout a checkout.completed event is about
+	CheckoutSessionID string `json:"checkout_session_id"`
+}
+
+// SignPayload returns a signature header value for payload at the given time.
+func SignPayload(secret string, payload []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, computeSignature(secret, timestamp, payload))
+}
+
+// VerifySignature checks a webhook signature header against the raw request body.
+// A tolerance of 0 disables the timestamp check.
+func VerifySignature(secret string, payload []byte, header string, tolerance time.Duration) error {
+	if secret == "" {
+		return fmt.Errorf("%w: no webhook secret configured", ErrInvalidSignature)
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	expected := []byte(computeSignature(secret, timestamp, payload))
+	valid := false
+	for _, sig := range signatures {
+		// Several v1 values are allowed so the secret can be rotated
+		if hmac.Equal(expected, []byte(sig)) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 {
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		age := time.Since(time.Unix(seconds, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrSignatureExpired
+		}
+	}
+	return nil
+}
+
+// ParseWebhookEvent verifies the signature and decodes the event.
+func ParseWebhookEvent(secret string, payload []byte, header string, tolerance time.Duration) (*WebhookEvent, error) {
+	if err := VerifySignature(secret, payload, header, tolerance); err != nil {
+		return nil, err
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook event: %w", err)
+	}
+	if event.ID == "" || event.Type == "" {
+		return nil, errors.New("webhook event is missing id or type")
+	}
+	return &event, nil
+}
+
+func computeSignature(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
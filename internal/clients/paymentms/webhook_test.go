@@ -0,0 +1,78 @@
+package paymentms
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","type":"invoice.paid"}`)
+	header := SignPayload("whsec", payload, time.Now())
+
+	if err := VerifySignature("whsec", payload, header, DefaultSignatureTolerance); err != nil {
+		t.Errorf("Expected valid signature, got %v", err)
+	}
+
+	if err := VerifySignature("other", payload, header, DefaultSignatureTolerance); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for wrong secret, got %v", err)
+	}
+
+	tampered := []byte(`{"id":"evt_1","type":"subscription.cancelled"}`)
+	if err := VerifySignature("whsec", tampered, header, DefaultSignatureTolerance); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for tampered body, got %v", err)
+	}
+
+	for _, bad := range []string{"", "garbage", "t=123", "v1=abc"} {
+		if err := VerifySignature("whsec", payload, bad, DefaultSignatureTolerance); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("Header %q: expected ErrInvalidSignature, got %v", bad, err)
+		}
+	}
+
+	if err := VerifySignature("", payload, header, DefaultSignatureTolerance); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature without a secret, got %v", err)
+	}
+}
+
+func TestVerifySignatureTolerance(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","type":"invoice.paid"}`)
+	old := SignPayload("whsec", payload, time.Now().Add(-10*time.Minute))
+
+	if err := VerifySignature("whsec", payload, old, DefaultSignatureTolerance); !errors.Is(err, ErrSignatureExpired) {
+		t.Errorf("Expected ErrSignatureExpired, got %v", err)
+	}
+	if err := VerifySignature("whsec", payload, old, 0); err != nil {
+		t.Errorf("Expected no timestamp check with zero tolerance, got %v", err)
+	}
+}
+
+func TestVerifySignatureDuringRotation(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","type":"invoice.paid"}`)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	header := "t=" + timestamp +
+		",v1=" + computeSignature("old-secret", timestamp, payload) +
+		",v1=" + computeSignature("new-secret", timestamp, payload)
+
+	if err := VerifySignature("new-secret", payload, header, DefaultSignatureTolerance); err != nil {
+		t.Errorf("Expected the second v1 signature to be accepted, got %v", err)
+	}
+}
+
+func TestParseWebhookEvent(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","type":"subscription.created","created":1700000000,"data":{"user_id":"user@example.com","subscription_id":"sub_1","status":"active"}}`)
+	header := SignPayload("whsec", payload, time.Now())
+
+	event, err := ParseWebhookEvent("whsec", payload, header, DefaultSignatureTolerance)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if event.Type != EventSubscriptionCreated || event.Data.SubscriptionID != "sub_1" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+
+	missing := []byte(`{"type":"subscription.created"}`)
+	if _, err := ParseWebhookEvent("whsec", missing, SignPayload("whsec", missing, time.Now()), 0); err == nil {
+		t.Error("Expected an error for an event without id")
+	}
+}
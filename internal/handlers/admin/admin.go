@@ -2,6 +2,7 @@ package admin
 
 import (
 	dbSqlc "github.com/DraconDev/go-templ-htmx-ex/database/sqlc"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/config"
 )
@@ -10,12 +11,17 @@ import (
 type AdminHandler struct {
 	Config      *config.Config
 	UserService *services.UserService
+	RoleService *services.RoleService
+	// PaymentClient lists promotion codes and their redemptions
+	PaymentClient *paymentms.Client
 }
 
 // NewAdminHandler creates a new admin handler
-func NewAdminHandler(config *config.Config, queries *dbSqlc.Queries) *AdminHandler {
+func NewAdminHandler(config *config.Config, queries *dbSqlc.Queries, roleService *services.RoleService, paymentClient *paymentms.Client) *AdminHandler {
 	return &AdminHandler{
-		Config:      config,
-		UserService: services.NewUserService(queries),
+		Config:        config,
+		UserService:   services.NewUserService(queries),
+		RoleService:   roleService,
+		PaymentClient: paymentClient,
 	}
 }
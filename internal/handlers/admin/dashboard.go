@@ -14,7 +14,6 @@ import (
 // =============================================================================
 // These handlers manage the admin dashboard and its functionality:
 // - Dashboard rendering and data loading
-// - Admin access validation
 // - Dashboard HTML generation
 // =============================================================================
 
@@ -22,37 +21,8 @@ import (
 func (h *AdminHandler) AdminDashboardHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Printf("📋 ADMIN: Admin dashboard requested\n")
 
-	// Get user info from middleware context
+	// Admin access is enforced by AuthMiddleware via the route's admin policy
 	userInfo := middleware.GetUserFromContext(r)
-	if !userInfo.LoggedIn {
-		fmt.Printf("📋 ADMIN: User not logged in\n")
-		http.Redirect(w, r, "/", http.StatusFound)
-		return
-	}
-
-	fmt.Printf("📋 ADMIN: User logged in: %s (%s)\n", userInfo.Name, userInfo.Email)
-
-	// Check if this user is admin using UserService
-	if h.UserService != nil {
-		userRecord, err := h.UserService.GetUserByEmail(r.Context(), userInfo.Email)
-		if err != nil {
-			fmt.Printf("📋 ACCESS DENIED: Could not fetch user from database: %v\n", err)
-			http.Error(w, "Access denied: Admin privileges required", http.StatusForbidden)
-			return
-		}
-
-		// Check if user is admin
-		if !userRecord.IsAdmin {
-			fmt.Printf("📋 ACCESS DENIED: User %s is not admin in database\n", userInfo.Email)
-			http.Error(w, "Access denied: Admin privileges required", http.StatusForbidden)
-			return
-		}
-	} else {
-		// Fallback: no database connection - deny access
-		fmt.Printf("📋 ACCESS DENIED: No database connection available\n")
-		http.Error(w, "Access denied: Admin privileges required", http.StatusForbidden)
-		return
-	}
 
 	fmt.Printf("📋 ADMIN: Access granted for admin %s\n", userInfo.Email)
 
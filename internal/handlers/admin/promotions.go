@@ -0,0 +1,59 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/middleware"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
+	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
+	"github.com/DraconDev/go-templ-htmx-ex/templates/pages"
+)
+
+// PromotionsHandler lists the project's promotion codes with their redemption counts
+func (h *AdminHandler) PromotionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	userInfo := middleware.GetUserFromContext(r)
+	fmt.Printf("📋 ADMIN: Promotion codes requested by %s\n", userInfo.Email)
+
+	var data pages.AdminPromotionsData
+	codes, err := h.PaymentClient.ListPromotionCodes(r.Context())
+	if err != nil {
+		fmt.Printf("❌ ADMIN: Error loading promotion codes: %v\n", err)
+		data.LoadFailed = true
+	}
+
+	now := time.Now()
+	for _, code := range codes {
+		row := pages.PromotionCodeRow{
+			Code:     code.Code,
+			Discount: services.PromotionTerms(code),
+			Redeemed: code.TimesRedeemed,
+			Limit:    code.MaxRedemptions,
+			Status:   "Active",
+			Active:   code.Active,
+			Expires:  "Never",
+		}
+		if !code.ExpiresAt.IsZero() {
+			row.Expires = code.ExpiresAt.Format("2006-01-02")
+		}
+		switch {
+		case !code.ExpiresAt.IsZero() && code.ExpiresAt.Before(now):
+			row.Status, row.Active = "Expired", false
+		case code.MaxRedemptions > 0 && code.TimesRedeemed >= code.MaxRedemptions:
+			row.Status, row.Active = "Used up", false
+		case !code.Active:
+			row.Status = "Inactive"
+		}
+		data.TotalRedemptions += code.TimesRedeemed
+		data.Codes = append(data.Codes, row)
+	}
+
+	component := layouts.Layout("Promotion Codes", "Promotion codes and their redemptions.", layouts.NavigationLoggedIn(userInfo), pages.AdminPromotionsContent(data))
+	if err := component.Render(r.Context(), w); err != nil {
+		fmt.Printf("🚨 ADMIN: Error rendering promotion codes: %v\n", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,114 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
+	"github.com/gorilla/mux"
+)
+
+// =============================================================================
+// ROLE MANAGEMENT API HANDLERS
+// =============================================================================
+
+// AssignRoleRequest represents a request to grant a role to a user
+type AssignRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// GetRolesHandler returns every defined role
+func (h *AdminHandler) GetRolesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	roles, err := h.RoleService.GetAllRoles(r.Context())
+	if err != nil {
+		fmt.Printf("📋 ADMIN: Error getting roles: %v\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Failed to load roles",
+		})
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"roles": roles,
+		"total": len(roles),
+	}); err != nil {
+		fmt.Printf("❌ Error encoding roles JSON: %v\n", err)
+	}
+}
+
+// AssignRoleHandler grants a role to the user in the path
+func (h *AdminHandler) AssignRoleHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	userID := mux.Vars(r)["id"]
+
+	var req AssignRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Role == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "role is required",
+		})
+		return
+	}
+
+	if err := h.RoleService.AssignRole(r.Context(), userID, req.Role); err != nil {
+		fmt.Printf("📋 ADMIN: Error assigning role %s to %s: %v\n", req.Role, userID, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Failed to assign role",
+		})
+		return
+	}
+
+	h.writeUserRoles(w, r, userID)
+}
+
+// RemoveRoleHandler revokes the role in the path from the user in the path
+func (h *AdminHandler) RemoveRoleHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	vars := mux.Vars(r)
+	userID := vars["id"]
+
+	err := h.RoleService.RemoveRole(r.Context(), userID, vars["role"])
+	if errors.Is(err, services.ErrAdminRoleFromFlag) {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "This user is flagged is_admin; clear the flag to revoke admin access",
+		})
+		return
+	}
+	if err != nil {
+		fmt.Printf("📋 ADMIN: Error removing role %s from %s: %v\n", vars["role"], userID, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Failed to remove role",
+		})
+		return
+	}
+
+	h.writeUserRoles(w, r, userID)
+}
+
+// writeUserRoles responds with the user's current roles
+func (h *AdminHandler) writeUserRoles(w http.ResponseWriter, r *http.Request, userID string) {
+	roles, err := h.RoleService.GetUserRoles(r.Context(), userID)
+	if err != nil {
+		fmt.Printf("📋 ADMIN: Error getting roles for %s: %v\n", userID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Failed to load user roles",
+		})
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"user_id": userID,
+		"roles":   roles,
+	}); err != nil {
+		fmt.Printf("❌ Error encoding user roles JSON: %v\n", err)
+	}
+}
@@ -1,21 +1,42 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/authms"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/middleware"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/config"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/oauthstate"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/providers"
 	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
 	"github.com/DraconDev/go-templ-htmx-ex/templates/pages"
 	"github.com/a-h/templ"
 )
 
-// HealthHandler handles health check requests
+// HealthHandler handles health check requests.
+// Reports "degraded" while the auth service circuit breaker is not closed.
 func HealthHandler(w http.ResponseWriter, r *http.Request) {
+	authStatus := middleware.AuthServiceStatus()
+	status := "healthy"
+	if authStatus.State != authms.BreakerClosed {
+		status = "degraded"
+	}
+
+	body := map[string]interface{}{
+		"status":       status,
+		"timestamp":    time.Now().Format(time.RFC3339),
+		"auth_service": authStatus,
+	}
+	if cacheStats, ok := middleware.SessionCacheStats(); ok {
+		body["session_cache"] = cacheStats
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write([]byte(`{"status": "healthy", "timestamp": "` + time.Now().Format(time.RFC3339) + `"}`)); err != nil {
+	if err := json.NewEncoder(w).Encode(body); err != nil {
 		fmt.Printf("Error writing health check response: %v\n", err)
 	}
 }
@@ -47,13 +68,8 @@ func HomeHandler(w http.ResponseWriter, r *http.Request) {
 // ProfileHandler handles the user profile page
 func ProfileHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
-	// Get user info from middleware context
+	// Get user info from middleware context (route policy guarantees a session)
 	userInfo := middleware.GetUserFromContext(r)
-	if !userInfo.LoggedIn {
-		// Redirect to home if not logged in
-		http.Redirect(w, r, "/", http.StatusFound)
-		return
-	}
 
 	// Create profile content with real user data
 	navigation := layouts.NavigationLoggedIn(userInfo)
@@ -67,7 +83,13 @@ func ProfileHandler(w http.ResponseWriter, r *http.Request) {
 // LoginHandler handles the login page
 func LoginHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
-	component := layouts.Layout("Login", "Secure authentication page with Google OAuth integration for user access.", layouts.NavigationLoggedOut(), pages.LoginContent())
+	// Carry the page the user was heading to through the provider buttons
+	returnTo := oauthstate.SafeReturnTo(r.URL.Query().Get("return_to"))
+	var registry *providers.Registry
+	if config.Current != nil {
+		registry = config.Current.Providers
+	}
+	component := layouts.Layout("Login", "Secure authentication page with Google OAuth integration for user access.", layouts.NavigationLoggedOut(), pages.LoginContent(registry.Enabled(), returnTo))
 	if err := component.Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render login page", http.StatusInternalServerError)
 		return
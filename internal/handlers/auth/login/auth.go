@@ -7,14 +7,16 @@ import (
 
 // LoginHandler handles authentication-related HTTP requests
 type LoginHandler struct {
-	Config      *config.Config        // App configuration
-	AuthService *services.AuthService // Auth service for session management (includes HTTP client)
+	Config       *config.Config         // App configuration
+	AuthService  *services.AuthService  // Auth service for session management (includes HTTP client)
+	LoginService *services.LoginService // Completes logins server-side (code exchange + user sync)
 }
 
 // NewLoginHandler creates a new authentication handler
-func NewLoginHandler(config *config.Config) *LoginHandler {
+func NewLoginHandler(config *config.Config, authService *services.AuthService, loginService *services.LoginService) *LoginHandler {
 	return &LoginHandler{
-		Config:      config,
-		AuthService: services.NewAuthService(config),
+		Config:       config,
+		AuthService:  authService,
+		LoginService: loginService,
 	}
 }
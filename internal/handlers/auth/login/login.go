@@ -3,6 +3,11 @@ package login
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/auth/session"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/oauthstate"
 
 	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
 	"github.com/DraconDev/go-templ-htmx-ex/templates/pages"
@@ -11,8 +16,8 @@ import (
 // =============================================================================
 // OAUTH LOGIN HANDLERS
 // =============================================================================
-// These handlers manage the OAuth login flow for all supported providers:
-// - Google, GitHub, Discord, Microsoft
+// These handlers manage the OAuth login flow for the configured providers:
+// - Providers come from the registry in config (OAUTH_PROVIDERS / OAUTH_PROVIDERS_FILE)
 // - Handles redirects to external providers
 // - Processes OAuth callbacks
 // =============================================================================
@@ -22,7 +27,7 @@ import (
 //
 //	Auth service handles OAuth -> Returns to our callback with session token
 //
-// Usage: /auth/login?provider=google|github|discord|microsoft
+// Usage: /auth/login?provider=<enabled provider name>[&return_to=/path]
 func (h *LoginHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	// Get provider from query parameter
 	provider := r.URL.Query().Get("provider")
@@ -32,56 +37,88 @@ func (h *LoginHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate provider
-	validProviders := map[string]bool{
-		"google":    true,
-		"github":    true,
-		"discord":   true,
-		"microsoft": true,
-	}
-
-	if !validProviders[provider] {
+	// Validate provider against the configured registry
+	oauthProvider, ok := h.Config.Providers.Get(provider)
+	if !ok {
 		fmt.Printf("🔐 LOGIN ERROR: Invalid provider '%s'\n", provider)
 		http.Redirect(w, r, "/login?error=invalid_provider", http.StatusFound)
 		return
 	}
 
-	fmt.Printf("🔐 LOGIN: Starting %s OAuth flow\n", provider)
+	fmt.Printf("🔐 LOGIN: Starting %s OAuth flow\n", oauthProvider.Label)
 	fmt.Printf("🔐 LOGIN: AuthServiceURL = %s\n", h.Config.AuthServiceURL)
 	fmt.Printf("🔐 LOGIN: RedirectURL = %s\n", h.Config.RedirectURL)
 
+	// Signed state carries the return path and binds the flow to this browser
+	state, err := oauthstate.New(h.Config.SessionSecret, oauthProvider.Name, r.URL.Query().Get("return_to"), oauthstate.DefaultTTL)
+	if err != nil {
+		fmt.Printf("🔐 LOGIN ERROR: Failed to create state: %v\n", err)
+		http.Redirect(w, r, "/login?error=state_failed", http.StatusFound)
+		return
+	}
+	oauthstate.SetCookie(w, state, oauthstate.DefaultTTL)
+
 	// Redirect to our auth microservice with redirect_uri parameter
 	// The auth service will handle the actual OAuth flow for the specified provider
-	authURL := fmt.Sprintf("%s/auth/%s?redirect_uri=%s/auth/callback",
-		h.Config.AuthServiceURL, provider, h.Config.RedirectURL)
+	// and echo state back to the callback
+	authURL := fmt.Sprintf("%s%s?redirect_uri=%s/auth/callback&state=%s",
+		h.Config.AuthServiceURL, oauthProvider.AuthPath, h.Config.RedirectURL, url.QueryEscape(state))
 
 	fmt.Printf("🔐 LOGIN: Redirecting to: %s\n", authURL)
 	http.Redirect(w, r, authURL, http.StatusFound)
 }
 
 // AuthCallbackHandler handles the OAuth callback
-// Flow: OAuth provider redirects here with authorization code in URL
-//
-//	Client-side JS extracts token and calls /api/auth/set-session
+// Flow (server mode): exchange auth_code server-side -> sync user -> set cookie -> redirect to return_to
+// Flow (client mode): render a page whose JS calls /api/auth/exchange-code
 func (h *LoginHandler) AuthCallbackHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Printf("🔐 CALLBACK: === OAuth callback STARTED ===\n")
-	fmt.Printf("🔐 CALLBACK: URL = %s\n", r.URL.String())
-	fmt.Printf("🔐 CALLBACK: Query params = %v\n", r.URL.Query())
-	fmt.Printf("🔐 CALLBACK: Fragment = %s\n", r.URL.Fragment)
+
+	query := r.URL.Query()
+	if h.Config.ServerSideCallback() && query.Get("auth_code") != "" {
+		h.completeCallback(w, r, query.Get("auth_code"), query.Get("state"))
+		return
+	}
 
 	fmt.Printf("🔐 CALLBACK: Setting content type and rendering template...\n")
 	w.Header().Set("Content-Type", "text/html")
 
-	// STEP 2: Render callback page with JavaScript to extract session token from URL fragment
-	// The fragment (#access_token=...) is not sent to server, so JS must handle it
-	component := layouts.Layout("Authenticating", "Authentication processing page for OAuth callback and session establishment.", layouts.NavigationLoggedOut(), pages.AuthCallbackContent())
+	// Name the provider from the signed state rather than guessing from the code
+	providerName := ""
+	if state, err := oauthstate.Verify(h.Config.SessionSecret, query.Get("state")); err == nil {
+		providerName = state.Provider
+	}
+
+	// Render callback page with JavaScript to extract the code from the URL
+	component := layouts.Layout("Authenticating", "Authentication processing page for OAuth callback and session establishment.", layouts.NavigationLoggedOut(), pages.AuthCallbackContent(h.Config.Providers.Label(providerName)))
 
-	fmt.Printf("🔐 CALLBACK: About to render component...\n")
 	if err := component.Render(r.Context(), w); err != nil {
 		fmt.Printf("🚨 CALLBACK: Error rendering component: %v\n", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
-	fmt.Printf("🔐 CALLBACK: Component rendered successfully\n")
-	fmt.Printf("🔐 CALLBACK: === OAuth callback COMPLETED ===\n")
+	fmt.Printf("🔐 CALLBACK: === OAuth callback COMPLETED (client mode) ===\n")
+}
+
+// completeCallback finishes the login without client JavaScript
+func (h *LoginHandler) completeCallback(w http.ResponseWriter, r *http.Request, authCode, stateValue string) {
+	state, err := oauthstate.VerifyRequest(r, h.Config.SessionSecret, stateValue)
+	if err != nil {
+		fmt.Printf("🔐 CALLBACK: ❌ State verification failed: %v\n", err)
+		http.Redirect(w, r, "/login?error=invalid_state", http.StatusFound)
+		return
+	}
+	oauthstate.ClearCookie(w)
+
+	sessionID, err := h.LoginService.CompleteLogin(r.Context(), authCode, services.DeviceFromRequest(r, h.Config.TrustedProxies))
+	if err != nil {
+		fmt.Printf("🔐 CALLBACK: ❌ Code exchange failed: %v\n", err)
+		http.Redirect(w, r, "/login?error=exchange_failed", http.StatusFound)
+		return
+	}
+
+	session.SetSessionCookie(w, sessionID, session.DefaultSessionCookieConfig())
+
+	fmt.Printf("🔐 CALLBACK: === OAuth callback COMPLETED (server mode), redirecting to %s ===\n", state.ReturnTo)
+	http.Redirect(w, r, state.ReturnTo, http.StatusSeeOther)
 }
@@ -0,0 +1,88 @@
+package session
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/middleware"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/templates/pages"
+	"github.com/gorilla/mux"
+)
+
+// =============================================================================
+// ACTIVE SESSIONS / DEVICE MANAGEMENT
+// =============================================================================
+
+// ActiveSessionsHandler renders the list of the user's active sessions (HTMX fragment)
+func (h *SessionHandler) ActiveSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.SessionRepository.GetActiveSessions(r.Context(), user.ID)
+	if err != nil {
+		fmt.Printf("⚠️ SESSION: Failed to load sessions: %v\n", err)
+		http.Error(w, "Failed to load sessions", http.StatusInternalServerError)
+		return
+	}
+
+	currentSessionID, _ := GetSessionCookie(r)
+	for i := range sessions {
+		sessions[i].Current = sessions[i].SessionID == currentSessionID
+	}
+
+	if err := pages.ActiveSessions(sessions).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render sessions", http.StatusInternalServerError)
+	}
+}
+
+// RevokeSessionHandler signs one of the user's devices out (HTMX fragment)
+func (h *SessionHandler) RevokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	target, err := h.SessionRepository.GetSession(r.Context(), mux.Vars(r)["id"], user.ID)
+	if err != nil {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.AuthService.Logout(r.Context(), target.SessionID); err != nil {
+		fmt.Printf("⚠️ SESSION: Failed to revoke session at auth service: %v\n", err)
+		http.Error(w, "Failed to revoke session", http.StatusBadGateway)
+		return
+	}
+	if err := h.SessionRepository.RevokeSession(r.Context(), target.SessionID); err != nil {
+		fmt.Printf("⚠️ SESSION: Failed to mark session revoked: %v\n", err)
+	}
+	middleware.InvalidateSession(target.SessionID)
+
+	// Revoking the current device is a logout
+	if currentSessionID, _ := GetSessionCookie(r); currentSessionID == target.SessionID {
+		ClearSessionCookie(w, DefaultSessionCookieConfig())
+		w.Header().Set("HX-Redirect", "/")
+	}
+
+	// Empty response removes the row via hx-swap="outerHTML"
+	w.WriteHeader(http.StatusOK)
+}
+
+// currentUser loads the local user record for the logged-in user
+func (h *SessionHandler) currentUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	if h.UserRepository == nil || h.SessionRepository == nil {
+		http.Error(w, "Session management unavailable", http.StatusServiceUnavailable)
+		return nil, false
+	}
+
+	userInfo := middleware.GetUserFromContext(r)
+	user, err := h.UserRepository.GetUserByEmail(r.Context(), userInfo.Email)
+	if err != nil {
+		http.Error(w, "User record not found", http.StatusInternalServerError)
+		return nil, false
+	}
+	return user, true
+}
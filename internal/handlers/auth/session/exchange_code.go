@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/oauthstate"
 )
 
 // ExchangeCodeHandler exchanges OAuth authorization code for tokens
@@ -20,6 +23,7 @@ func (h *SessionHandler) ExchangeCodeHandler(w http.ResponseWriter, r *http.Requ
 	fmt.Printf("🔄 CODE: Decoding request body...\n")
 	var req struct {
 		AuthCode string `json:"auth_code"`
+		State    string `json:"state"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -44,54 +48,43 @@ func (h *SessionHandler) ExchangeCodeHandler(w http.ResponseWriter, r *http.Requ
 
 	fmt.Printf("🔄 CODE: ✅ Authorization code received, length: %d\n", len(req.AuthCode))
 
-	// STEP 2: Call the auth service
-	fmt.Printf("🔄 CODE: Calling auth service to exchange code for tokens...\n")
-	fmt.Printf("🔄 CODE: AuthService address: %p\n", h.AuthService)
-
-	authResp, err := h.AuthService.ExchangeCodeForTokens(req.AuthCode)
+	// Verify the signed state issued by /auth/login for this browser (blocks login CSRF)
+	state, err := oauthstate.VerifyRequest(r, h.Config.SessionSecret, req.State)
 	if err != nil {
-		fmt.Printf("🔄 CODE: ❌ Auth service call failed: %v\n", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Printf("🔄 CODE: ❌ State verification failed: %v\n", err)
+		w.WriteHeader(http.StatusBadRequest)
 		_ = json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": err.Error(),
+			"error": "Login session expired or invalid, please sign in again",
 		})
 		return
 	}
+	oauthstate.ClearCookie(w)
 
-	fmt.Printf("🔄 CODE: ✅ Auth service call completed successfully\n")
-	fmt.Printf("🔄 CODE: Auth response: success=%v, id_token length=%d\n", authResp.Success, len(authResp.IdToken))
-
-	if !authResp.Success {
-		fmt.Printf("🔄 CODE: ❌ Auth service returned failure: %s\n", authResp.Error)
+	// STEP 2: Exchange the code and sync the user (shared with the server-side callback)
+	fmt.Printf("🔄 CODE: Calling auth service to exchange code for tokens...\n")
+	sessionID, err := h.LoginService.CompleteLogin(r.Context(), req.AuthCode, services.DeviceFromRequest(r, h.Config.TrustedProxies))
+	if err != nil {
+		fmt.Printf("🔄 CODE: ❌ Code exchange failed: %v\n", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": authResp.Error,
+			"error": err.Error(),
 		})
 		return
 	}
 
-	fmt.Printf("🔄 CODE: ✅ Auth service returned success: %v\n", authResp.Success)
+	fmt.Printf("🔄 CODE: ✅ Auth service call completed successfully, session_id length=%d\n", len(sessionID))
 
 	// STEP 3: Set the session cookie
-	fmt.Printf("🔄 CODE: Setting session cookie with session_id: %s\n", authResp.IdToken)
-	sessionCookie := &http.Cookie{
-		Name:     "session_id",
-		Value:    authResp.IdToken, // Using session_id from response
-		Path:     "/",
-		MaxAge:   2592000, // 30 days
-		HttpOnly: true,
-		Secure:   false, // Set to true in production with HTTPS
-	}
-
-	http.SetCookie(w, sessionCookie)
+	SetSessionCookie(w, sessionID, DefaultSessionCookieConfig())
 	fmt.Printf("🔄 CODE: ✅ Session cookie set successfully\n")
 
 	// STEP 4: Return success response
 	fmt.Printf("🔄 CODE: Returning success response...\n")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": true,
-		"message": "Tokens exchanged successfully",
+		"success":   true,
+		"message":   "Tokens exchanged successfully",
+		"return_to": state.ReturnTo,
 	}); err != nil {
 		fmt.Printf("🔄 CODE: ❌ Error encoding success response: %v\n", err)
 	}
@@ -2,14 +2,32 @@ package session
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/middleware"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/errors"
 )
 
 // LogoutHandler handles user logout
-// This handler is responsible ONLY for clearing session cookies
+// This handler revokes the session at the auth service, evicts it from the
+// middleware cache and clears the session cookie
 func (h *SessionHandler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
+	if sessionID, err := GetSessionCookie(r); err == nil && sessionID != "" {
+		if err := h.AuthService.Logout(r.Context(), sessionID); err != nil {
+			// Still clear the cookie locally; the session expires at the auth service eventually
+			fmt.Printf("⚠️ SESSION: Failed to revoke session at auth service: %v\n", err)
+		}
+		if h.SessionRepository != nil {
+			if err := h.SessionRepository.RevokeSession(r.Context(), sessionID); err != nil {
+				fmt.Printf("⚠️ SESSION: Failed to mark session revoked: %v\n", err)
+			}
+		}
+		middleware.InvalidateSession(sessionID)
+	}
+
 	// Use session utility to clear the cookie
 	sessionConfig := DefaultSessionCookieConfig()
 	ClearSessionCookie(w, sessionConfig)
@@ -25,3 +43,42 @@ func (h *SessionHandler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
 		_ = err // Suppress unused variable warning
 	}
 }
+
+// LogoutAllHandler revokes every session of the current user ("log out everywhere")
+func (h *SessionHandler) LogoutAllHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	sessionID, err := GetSessionCookie(r)
+	if err != nil || sessionID == "" {
+		handleJSONError(w, "Missing session", err, errors.NewUnauthorizedError)
+		return
+	}
+
+	// Unlike single logout, keep the session if revocation fails so the user can retry
+	if err := h.AuthService.LogoutAll(r.Context(), sessionID); err != nil {
+		handleJSONError(w, "Failed to revoke sessions", err, errors.NewInternalServerError)
+		return
+	}
+
+	userInfo := middleware.GetUserFromContext(r)
+	if h.UserRepository != nil && h.SessionRepository != nil {
+		if user, err := h.UserRepository.GetUserByEmail(r.Context(), userInfo.Email); err == nil {
+			if err := h.SessionRepository.RevokeAllSessions(r.Context(), user.ID); err != nil {
+				fmt.Printf("⚠️ SESSION: Failed to mark sessions revoked: %v\n", err)
+			}
+		}
+	}
+	middleware.InvalidateUserSessions(userInfo.Email)
+	middleware.InvalidateSession(sessionID)
+
+	sessionConfig := DefaultSessionCookieConfig()
+	ClearSessionCookie(w, sessionConfig)
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Logged out of all sessions",
+	}); err != nil {
+		_ = err
+	}
+}
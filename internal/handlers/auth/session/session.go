@@ -7,15 +7,19 @@ import (
 )
 
 type SessionHandler struct {
-	Config         *config.Config
-	AuthService    *services.AuthService
-	UserRepository *repositories.UserRepository
+	Config            *config.Config
+	AuthService       *services.AuthService
+	UserRepository    *repositories.UserRepository
+	SessionRepository *repositories.SessionRepository
+	LoginService      *services.LoginService
 }
 
-func NewSessionHandler(config *config.Config, userRepo *repositories.UserRepository) *SessionHandler {
+func NewSessionHandler(config *config.Config, authService *services.AuthService, loginService *services.LoginService, userRepo *repositories.UserRepository, sessionRepo *repositories.SessionRepository) *SessionHandler {
 	return &SessionHandler{
-		Config:         config,
-		AuthService:    services.NewAuthService(config),
-		UserRepository: userRepo,
+		Config:            config,
+		AuthService:       authService,
+		UserRepository:    userRepo,
+		SessionRepository: sessionRepo,
+		LoginService:      loginService,
 	}
 }
@@ -5,19 +5,23 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/errors"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/oauthstate"
 )
 
 // SetSessionHandler handles setting a new session cookie
 // This handler is responsible for:
 // 1. Setting session cookies from a provided session ID
 // 2. Syncing user data from Auth MS to local DB
+// The request must carry the signed state /auth/login issued to this browser,
+// otherwise a cross-site POST could sign the victim in to another session.
 func (h *SessionHandler) SetSessionHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	var req struct {
 		SessionID string `json:"session_id"`
+		State     string `json:"state"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -30,35 +34,23 @@ func (h *SessionHandler) SetSessionHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Verify the signed state issued by /auth/login for this browser (blocks login CSRF)
+	if _, err := oauthstate.VerifyRequest(r, h.Config.SessionSecret, req.State); err != nil {
+		handleJSONError(w, "Login session expired or invalid, please sign in again", err, errors.NewBadRequestError)
+		return
+	}
+	oauthstate.ClearCookie(w)
+
 	// 1. Fetch user info from Auth MS (via session refresh)
-	userContext, err := h.AuthService.GetUserInfo(req.SessionID)
+	userContext, err := h.AuthService.GetUserInfo(r.Context(), req.SessionID)
 	if err != nil {
 		// If we can't get user info, we shouldn't set the session
 		handleJSONError(w, "Failed to validate session with Auth Service", err, errors.NewUnauthorizedError)
 		return
 	}
 
-	// 2. Sync user to local DB
-	if h.UserRepository != nil {
-		// Convert UserSessionContext to User model
-		user := &models.User{
-			AuthID:  userContext.UserID,
-			Email:   userContext.Email,
-			Name:    userContext.Name,
-			Picture: userContext.Picture,
-			IsAdmin: false, // Default to false, admin can update later
-		}
-
-		// Upsert user
-		// We use a background context or the request context
-		_, err := h.UserRepository.UpsertUser(r.Context(), user)
-		if err != nil {
-			fmt.Printf("⚠️ SESSION: Failed to sync user to local DB: %v\n", err)
-			// We continue even if sync fails, to allow login
-		} else {
-			fmt.Printf("✅ SESSION: Synced user %s to local DB\n", user.Email)
-		}
-	}
+	// 2. Sync user to local DB and record the device
+	h.LoginService.SyncSession(r.Context(), req.SessionID, userContext, services.DeviceFromRequest(r, h.Config.TrustedProxies))
 
 	// Use session utility to set the cookie
 	sessionConfig := DefaultSessionCookieConfig()
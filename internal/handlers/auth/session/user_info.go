@@ -20,7 +20,7 @@ func (h *SessionHandler) GetUserInfo(r *http.Request) layouts.UserInfo {
 	}
 
 	// Get user context from auth service (via session refresh)
-	userContext, err := h.AuthService.GetUserInfo(sessionID)
+	userContext, err := h.AuthService.GetUserInfo(r.Context(), sessionID)
 	if err != nil {
 		return layouts.UserInfo{LoggedIn: false}
 	}
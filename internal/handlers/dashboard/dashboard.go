@@ -1,61 +1,370 @@
 package dashboard
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
-	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/auth/session"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/middleware"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/repositories"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/config"
 	"github.com/DraconDev/go-templ-htmx-ex/templates/pages"
 )
 
+// recentPurchasesLimit is how many purchases the dashboard lists
+const recentPurchasesLimit = 10
+
 type DashboardHandler struct {
-	config         *config.Config
-	paymentClient  *paymentms.Client
-	sessionHandler *session.SessionHandler
+	config        *config.Config
+	userRepo      *repositories.UserRepository
+	entitlements  *services.EntitlementService
+	purchaseRepo  *repositories.PurchaseRepository
+	subscriptions *services.SubscriptionService
 }
 
-func NewDashboardHandler(cfg *config.Config, paymentClient *paymentms.Client, sessionHandler *session.SessionHandler) *DashboardHandler {
+func NewDashboardHandler(cfg *config.Config, userRepo *repositories.UserRepository, entitlements *services.EntitlementService, purchaseRepo *repositories.PurchaseRepository, subscriptions *services.SubscriptionService) *DashboardHandler {
 	return &DashboardHandler{
-		config:         cfg,
-		paymentClient:  paymentClient,
-		sessionHandler: sessionHandler,
+		config:        cfg,
+		userRepo:      userRepo,
+		entitlements:  entitlements,
+		purchaseRepo:  purchaseRepo,
+		subscriptions: subscriptions,
 	}
 }
 
 func (h *DashboardHandler) DashboardHandler(w http.ResponseWriter, r *http.Request) {
-	// Get user info from session using SessionHandler
-	userInfo := h.sessionHandler.GetUserInfo(r)
-	if !userInfo.LoggedIn {
-		http.Redirect(w, r, "/login", http.StatusSeeOther)
-		return
-	}
+	// Get user info from middleware context (route policy guarantees a session)
+	userInfo := middleware.GetUserFromContext(r)
 
-	// Get subscription status
-	// Note: In a real app, you'd get the user ID from the session.
-	// For now, we'll use the email or a placeholder if ID is missing.
-	userID := userInfo.Email // Fallback since we might not have ID in session yet
+	// Prepare view model
+	data := pages.UserDashboard{
+		Name:       userInfo.Name,
+		Email:      userInfo.Email,
+		Picture:    userInfo.Picture,
+		PlanStatus: "Free Plan",
+	}
 
-	// Fetch subscription status
-	// We use the product ID from config
-	subStatus, err := h.paymentClient.GetSubscriptionStatus(r.Context(), userID, h.config.StripeProductID)
+	// The plan comes from the local subscriptions table, kept in sync by webhooks
+	user, err := h.userRepo.GetUserByEmail(r.Context(), userInfo.Email)
+	var entitlements *services.Entitlements
+	if err == nil {
+		entitlements, err = h.userEntitlements(r, user)
+	}
+	if err != nil {
+		// Don't tell a paying customer they are on the free plan because billing is down
+		fmt.Printf("❌ DASHBOARD: Failed to load entitlements: %v\n", err)
+		data.PlanStatus = "Unknown"
+		data.BillingNotice = "We couldn't load your subscription right now. Please check back shortly."
+	} else {
+		applySubscriptionStatus(&data, entitlements, h.config.Catalog, time.Now())
+	}
 
-	// Prepare view model
-	isPro := false
-	status := "Free Plan"
-	periodEnd := ""
-
-	if err == nil && subStatus != nil {
-		if subStatus.Status == "active" {
-			isPro = true
-			status = "Pro Plan"
-			periodEnd = subStatus.CurrentPeriodEnd.Format("Jan 02, 2006")
+	if user != nil {
+		purchases, err := h.purchaseRepo.GetUserPurchases(r.Context(), user.ID, recentPurchasesLimit)
+		if err != nil {
+			fmt.Printf("⚠️ DASHBOARD: Failed to load purchases: %v\n", err)
 		}
+		data.Purchases = purchases
 	}
 
 	// Render template
-	component := pages.Dashboard(userInfo.Name, userInfo.Email, userInfo.Picture, status, isPro, periodEnd)
+	component := pages.Dashboard(data)
 	if err := component.Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render dashboard", http.StatusInternalServerError)
 	}
 }
+
+// ProjectSubscriptionsHandler renders the user's subscriptions across all projects
+// sharing the payment service (HTMX fragment for the dashboard and profile)
+func (h *DashboardHandler) ProjectSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	userInfo := middleware.GetUserFromContext(r)
+
+	var subs []models.ProjectSubscription
+	user, err := h.userRepo.GetUserByEmail(r.Context(), userInfo.Email)
+	if err == nil {
+		subs, err = h.entitlements.ProjectSubscriptions(r.Context(), user)
+	}
+	if err != nil {
+		fmt.Printf("⚠️ DASHBOARD: Failed to list project subscriptions: %v\n", err)
+	}
+
+	if err := pages.ProjectSubscriptions(subs, err != nil).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render subscriptions", http.StatusInternalServerError)
+	}
+}
+
+// SubscriptionManageHandler renders the current subscription and its actions (HTMX fragment)
+func (h *DashboardHandler) SubscriptionManageHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.fragmentUser(w, r)
+	if !ok {
+		return
+	}
+	current, err := h.subscriptions.Current(r.Context(), user)
+	h.renderSubscription(w, r, current, err, pages.SubscriptionManagement{})
+}
+
+// CancelDialogHandler renders the cancellation confirmation (HTMX fragment)
+func (h *DashboardHandler) CancelDialogHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.fragmentUser(w, r)
+	if !ok {
+		return
+	}
+
+	current, err := h.subscriptions.Current(r.Context(), user)
+	if err != nil {
+		h.renderDialogError(w, r, err)
+		return
+	}
+	if err := pages.CancelSubscriptionDialog(current.CurrentPeriodEnd.Format("Jan 02, 2006")).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render dialog", http.StatusInternalServerError)
+	}
+}
+
+// CancelSubscriptionHandler cancels the subscription at the end of the period
+func (h *DashboardHandler) CancelSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.fragmentUser(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.subscriptions.Cancel(r.Context(), user)
+	if err != nil {
+		fmt.Printf("❌ DASHBOARD: Failed to cancel subscription for %s: %v\n", user.ID, err)
+		h.renderActionError(w, r, user, err)
+		return
+	}
+	fmt.Printf("✅ DASHBOARD: Subscription %s set to cancel at period end\n", updated.SubscriptionID)
+	h.renderSubscription(w, r, updated, nil, pages.SubscriptionManagement{
+		Message: "Your subscription has been cancelled. You keep access until " + updated.CurrentPeriodEnd.Format("Jan 02, 2006") + ".",
+	})
+}
+
+// ResumeSubscriptionHandler undoes a pending cancellation
+func (h *DashboardHandler) ResumeSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.fragmentUser(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.subscriptions.Resume(r.Context(), user)
+	if err != nil {
+		fmt.Printf("❌ DASHBOARD: Failed to resume subscription for %s: %v\n", user.ID, err)
+		h.renderActionError(w, r, user, err)
+		return
+	}
+	fmt.Printf("✅ DASHBOARD: Subscription %s resumed\n", updated.SubscriptionID)
+	h.renderSubscription(w, r, updated, nil, pages.SubscriptionManagement{Message: "Your subscription will renew as usual."})
+}
+
+// ChangePreviewHandler renders the prorated cost of a plan change for confirmation (HTMX fragment)
+func (h *DashboardHandler) ChangePreviewHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.fragmentUser(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	preview, err := h.subscriptions.PreviewChange(r.Context(), user, query.Get("plan"), query.Get("interval"))
+	if err != nil {
+		fmt.Printf("⚠️ DASHBOARD: Failed to preview plan change: %v\n", err)
+		h.renderDialogError(w, r, err)
+		return
+	}
+
+	data := pages.SubscriptionChangePreview{
+		SubscriptionOption: subscriptionOption(preview.PlanOption),
+		AmountDue:          catalog.FormatAmount(preview.AmountDue, preview.Currency),
+	}
+	if !preview.ProrationDate.IsZero() {
+		data.ProrationDate = preview.ProrationDate.Unix()
+	}
+	if preview.AmountDue < 0 {
+		data.Credit = true
+		data.AmountDue = catalog.FormatAmount(-preview.AmountDue, preview.Currency)
+	}
+	if !preview.NextPaymentDate.IsZero() {
+		data.NextPayment = catalog.FormatAmount(preview.NextPaymentAmount, preview.Currency) + " on " + preview.NextPaymentDate.Format("Jan 02, 2006")
+	}
+
+	if err := pages.ChangeSubscriptionDialog(data).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render dialog", http.StatusInternalServerError)
+	}
+}
+
+// ChangeSubscriptionHandler moves the subscription to the confirmed plan and interval
+func (h *DashboardHandler) ChangeSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.fragmentUser(w, r)
+	if !ok {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	// Charge what the user saw in the preview, not what it would cost by now
+	var prorationDate time.Time
+	if unix, err := strconv.ParseInt(r.FormValue("proration_date"), 10, 64); err == nil && unix > 0 {
+		prorationDate = time.Unix(unix, 0)
+	}
+
+	updated, err := h.subscriptions.Change(r.Context(), user, r.FormValue("plan"), r.FormValue("interval"), prorationDate)
+	if err != nil {
+		fmt.Printf("❌ DASHBOARD: Failed to change plan for %s: %v\n", user.ID, err)
+		h.renderActionError(w, r, user, err)
+		return
+	}
+	fmt.Printf("✅ DASHBOARD: Subscription %s moved to price %s\n", updated.SubscriptionID, updated.PriceID)
+	h.renderSubscription(w, r, updated, nil, pages.SubscriptionManagement{Message: "Your plan has been changed to " + planOptionLabel(updated.Plan, updated.Price) + "."})
+}
+
+// fragmentUser loads the signed-in user for an HTMX fragment, answering with an error if it can't
+func (h *DashboardHandler) fragmentUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	w.Header().Set("Content-Type", "text/html")
+
+	userInfo := middleware.GetUserFromContext(r)
+	user, err := h.userRepo.GetUserByEmail(r.Context(), userInfo.Email)
+	if err != nil {
+		http.Error(w, "User record not found", http.StatusInternalServerError)
+		return nil, false
+	}
+	return user, true
+}
+
+// renderSubscription renders the subscription actions fragment; data carries the
+// outcome message of the action just taken, if any
+func (h *DashboardHandler) renderSubscription(w http.ResponseWriter, r *http.Request, current *services.ManagedSubscription, err error, data pages.SubscriptionManagement) {
+	switch {
+	case errors.Is(err, services.ErrNoSubscription):
+		data.None = true
+	case err != nil:
+		fmt.Printf("⚠️ DASHBOARD: Failed to load subscription: %v\n", err)
+		data.LoadFailed = true
+	default:
+		data.PlanLabel = current.Plan.Label
+		if data.PlanLabel == "" {
+			data.PlanLabel = current.ProductID
+		}
+		if current.Price != nil {
+			data.PriceLabel = current.Price.Display() + " / " + current.Price.Interval
+		}
+		data.Status = current.Status
+		data.CancelAtPeriodEnd = current.CancelAtPeriodEnd
+		if !current.CurrentPeriodEnd.IsZero() {
+			data.PeriodEnd = current.CurrentPeriodEnd.Format("Jan 02, 2006")
+		}
+		for _, option := range current.Options {
+			data.Options = append(data.Options, subscriptionOption(option))
+		}
+	}
+
+	if err := pages.SubscriptionManage(data).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render subscription", http.StatusInternalServerError)
+	}
+}
+
+// renderActionError re-renders the stored subscription with the reason an action failed
+func (h *DashboardHandler) renderActionError(w http.ResponseWriter, r *http.Request, user *models.User, actionErr error) {
+	current, err := h.subscriptions.Current(r.Context(), user)
+	h.renderSubscription(w, r, current, err, pages.SubscriptionManagement{Error: subscriptionErrorMessage(actionErr)})
+}
+
+// renderDialogError renders the dialog shown when a confirmation can't be prepared
+func (h *DashboardHandler) renderDialogError(w http.ResponseWriter, r *http.Request, err error) {
+	if err := pages.SubscriptionDialogError(subscriptionErrorMessage(err)).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render dialog", http.StatusInternalServerError)
+	}
+}
+
+// applySubscriptionStatus fills the plan card from the subscription granting the
+// plan or, without one, from the latest subscription (past_due or canceled)
+func applySubscriptionStatus(data *pages.UserDashboard, entitlements *services.Entitlements, cat *catalog.Catalog, now time.Time) {
+	sub, planName := entitlements.Subscription, entitlements.Plan
+	if sub == nil {
+		sub, planName = entitlements.Latest, entitlements.LatestPlan
+	}
+	if sub == nil {
+		return
+	}
+
+	label := planName
+	if plan, ok := cat.Plan(planName); ok {
+		label = plan.Label
+	}
+
+	data.SubscriptionStatus = sub.Status
+	if !sub.CurrentPeriodEnd.IsZero() {
+		data.PeriodEnd = sub.CurrentPeriodEnd.Format("Jan 02, 2006")
+	}
+
+	switch {
+	case entitlements.Subscription != nil:
+		data.IsPaid = true
+		data.PlanStatus = label + " Plan"
+		data.CancelAtPeriodEnd = sub.CancelAtPeriodEnd
+		if sub.Trialing(now) {
+			data.PlanStatus = label + " Trial"
+			data.TrialEnd = sub.TrialEnd.Format("Jan 02, 2006")
+			data.TrialDaysLeft = models.TrialDaysLeft(sub.TrialEnd, now)
+		}
+	case sub.Status == models.SubscriptionStatusPastDue:
+		// Access is paused until the failed payment goes through
+		data.PlanStatus = label + " Plan"
+	}
+}
+
+// userEntitlements loads the entitlements of the signed-in user, syncing from
+// the payment service first if this process hasn't done so recently
+func (h *DashboardHandler) userEntitlements(r *http.Request, user *models.User) (*services.Entitlements, error) {
+	h.entitlements.EnsureSynced(r.Context(), user)
+	return h.entitlements.Entitlements(r.Context(), user.ID)
+}
+
+// subscriptionOption converts a plan change option for display
+func subscriptionOption(option services.PlanOption) pages.SubscriptionOption {
+	return pages.SubscriptionOption{
+		Plan:     option.Plan.Name,
+		Interval: option.Price.Interval,
+		Label:    planOptionLabel(option.Plan, &option.Price),
+		Price:    option.Price.Display() + " / " + option.Price.Interval,
+	}
+}
+
+// planOptionLabel names a plan and billing interval, e.g. "Pro yearly"
+func planOptionLabel(plan catalog.Plan, price *catalog.Price) string {
+	if price == nil {
+		return plan.Label
+	}
+	if price.Interval == catalog.IntervalYear {
+		return plan.Label + " yearly"
+	}
+	return plan.Label + " monthly"
+}
+
+// subscriptionErrorMessage is what the user is told when a subscription action fails
+func subscriptionErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, services.ErrNoSubscription):
+		return "You don't have a subscription to change."
+	case errors.Is(err, services.ErrSamePrice):
+		return "You're already on this plan."
+	case errors.Is(err, services.ErrCancellationPending):
+		return "Resume your subscription before changing plans."
+	case errors.Is(err, catalog.ErrUnknownPlan), errors.Is(err, catalog.ErrNotPurchasable):
+		return "This plan is not available for the selected billing interval."
+	case errors.Is(err, paymentms.ErrValidation):
+		return "The payment service didn't accept this change. Please refresh and try again."
+	case errors.Is(err, models.ErrDatabaseNotConnected):
+		return "We couldn't load your subscription right now."
+	default:
+		return paymentms.UserMessage(err)
+	}
+}
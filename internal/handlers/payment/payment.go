@@ -2,11 +2,18 @@ package payment
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/middleware"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/repositories"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/config"
 	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
 	"github.com/DraconDev/go-templ-htmx-ex/templates/pages"
@@ -14,15 +21,21 @@ import (
 
 // PaymentHandler handles payment-related requests
 type PaymentHandler struct {
-	Config *config.Config
-	Client *paymentms.Client
+	Config       *config.Config
+	Client       *paymentms.Client
+	UserRepo     *repositories.UserRepository
+	Entitlements *services.EntitlementService
+	Promotions   *services.PromotionService
 }
 
 // NewPaymentHandler creates a new payment handler
-func NewPaymentHandler(config *config.Config, client *paymentms.Client) *PaymentHandler {
+func NewPaymentHandler(config *config.Config, client *paymentms.Client, userRepo *repositories.UserRepository, entitlements *services.EntitlementService, promotions *services.PromotionService) *PaymentHandler {
 	return &PaymentHandler{
-		Config: config,
-		Client: client,
+		Config:       config,
+		Client:       client,
+		UserRepo:     userRepo,
+		Entitlements: entitlements,
+		Promotions:   promotions,
 	}
 }
 
@@ -34,28 +47,122 @@ func (h *PaymentHandler) PricingPageHandler(w http.ResponseWriter, r *http.Reque
 	userInfo := middleware.GetUserFromContext(r)
 
 	// Render pricing page
-	component := pages.Pricing(userInfo)
+	component := pages.Pricing(userInfo, h.pricingData(r))
 	if err := component.Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render pricing page", http.StatusInternalServerError)
 		return
 	}
 }
 
-// PaymentPageHandler handles the payment page display
-func (h *PaymentHandler) PaymentPageHandler(w http.ResponseWriter, r *http.Request) {
+// PricingPlansHandler renders the plan grid for a billing interval (HTMX fragment)
+func (h *PaymentHandler) PricingPlansHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
 
-	// Get user info from middleware context
 	userInfo := middleware.GetUserFromContext(r)
-	if !userInfo.LoggedIn {
-		// Redirect to home if not logged in
-		http.Redirect(w, r, "/", http.StatusFound)
+
+	// An applied promotion code re-quotes itself for the new interval
+	w.Header().Set("HX-Trigger-After-Settle", "pricingIntervalChanged")
+
+	component := pages.PricingPlans(userInfo, h.pricingData(r))
+	if err := component.Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render pricing plans", http.StatusInternalServerError)
 		return
 	}
+}
+
+// PromotionHandler shows each plan's price with a promotion code applied (HTMX fragment)
+func (h *PaymentHandler) PromotionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	userInfo := middleware.GetUserFromContext(r)
+	code := strings.TrimSpace(r.URL.Query().Get("promotion_code"))
+	var data pages.PromotionResult
+	if code != "" {
+		data = h.promotionResult(r, userInfo, code)
+	}
+
+	if err := pages.PromotionResultContent(data).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render promotion", http.StatusInternalServerError)
+	}
+}
+
+// promotionResult quotes the code for every plan purchasable for the request's
+// interval. Visitors who are not signed in get a quote without customer checks;
+// checkout validates the code again for the user.
+func (h *PaymentHandler) promotionResult(r *http.Request, userInfo layouts.UserInfo, code string) pages.PromotionResult {
+	var data pages.PromotionResult
+
+	var user *models.User
+	if userInfo.LoggedIn {
+		found, err := h.UserRepo.GetUserByEmail(r.Context(), userInfo.Email)
+		if err != nil {
+			fmt.Printf("⚠️ PAYMENT: No local user for promotion code, quoting without one: %v\n", err)
+		} else {
+			user = found
+		}
+	}
+
+	interval := catalog.ParseInterval(r.URL.Query().Get("interval"))
+	quotes, err := h.Promotions.Quote(r.Context(), user, code, interval)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPromotionCode) {
+			data.Error = "This promotion code is not valid."
+		} else {
+			fmt.Printf("⚠️ PAYMENT: Failed to validate promotion code: %v\n", err)
+			data.Error = paymentms.UserMessage(err)
+		}
+		return data
+	}
+
+	applies := false
+	for _, quote := range quotes {
+		line := pages.PromotionLine{
+			PlanLabel:  quote.Plan.Label,
+			Interval:   quote.Price.Interval,
+			Original:   quote.Price.Display(),
+			Discounted: catalog.FormatAmount(quote.Discounted, quote.Price.Currency),
+			Terms:      services.PromotionTerms(quote.Promotion),
+		}
+		if !quote.Applies {
+			line.Reason = quote.Reason
+			if line.Reason == "" {
+				line.Reason = "This code does not apply to this plan"
+			}
+		}
+		applies = applies || quote.Applies
+		data.Lines = append(data.Lines, line)
+	}
+	if !applies {
+		data.Error = "This promotion code does not apply to any plan."
+		data.Lines = nil
+	}
+	return data
+}
+
+// pricingData builds the pricing view model for the ?interval= of the request
+func (h *PaymentHandler) pricingData(r *http.Request) pages.PricingData {
+	data := pages.PricingData{
+		Plans:      h.Config.Catalog.Plans(),
+		Interval:   catalog.IntervalMonth,
+		HasYearly:  h.Config.Catalog.HasYearly(),
+		MaxSavings: h.Config.Catalog.MaxYearlySavings(),
+	}
+	if data.HasYearly {
+		data.Interval = catalog.ParseInterval(r.URL.Query().Get("interval"))
+	}
+	return data
+}
+
+// PaymentPageHandler handles the payment page display
+func (h *PaymentHandler) PaymentPageHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	// Get user info from middleware context (route policy guarantees a session)
+	userInfo := middleware.GetUserFromContext(r)
 
 	// Create payment page content with user data
 	navigation := layouts.NavigationLoggedIn(userInfo)
-	component := layouts.Layout("Payment", "Subscribe to access premium features and content.", navigation, pages.PaymentContent(userInfo))
+	component := layouts.Layout("Payment", "Subscribe to access premium features and content.", navigation, pages.PaymentContent(userInfo, h.Config.Catalog.Plans()))
 	if err := component.Render(r.Context(), w); err != nil {
 		http.Error(w, "Failed to render payment page", http.StatusInternalServerError)
 		return
@@ -66,37 +173,45 @@ func (h *PaymentHandler) PaymentPageHandler(w http.ResponseWriter, r *http.Reque
 func (h *PaymentHandler) CheckoutHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	// Get user info from middleware context
+	// Get user info from middleware context (route policy guarantees a session)
 	userInfo := middleware.GetUserFromContext(r)
-	if !userInfo.LoggedIn {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Authentication required",
-		})
-		return
-	}
 
 	// Parse request body
 	var req struct {
-		PriceID    string `json:"price_id"`
-		ProductID  string `json:"product_id"`
-		SuccessURL string `json:"success_url"`
-		CancelURL  string `json:"cancel_url"`
+		Plan          string `json:"plan"`
+		Interval      string `json:"interval"`
+		PromotionCode string `json:"promotion_code"`
+		SuccessURL    string `json:"success_url"`
+		CancelURL     string `json:"cancel_url"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
 			"error": "Invalid request body",
 		})
 		return
 	}
 
 	// Validate required fields
-	if req.PriceID == "" || req.ProductID == "" {
+	if req.Plan == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Missing required field: plan",
+		})
+		return
+	}
+	if req.Interval == "" {
+		req.Interval = catalog.IntervalMonth
+	}
+
+	// The price is picked from the catalog, never taken from the client
+	plan, price, err := h.Config.Catalog.CheckoutPrice(req.Plan, req.Interval)
+	if err != nil {
+		fmt.Printf("⚠️ PAYMENT: Rejected checkout for plan %s (%s): %v\n", req.Plan, req.Interval, err)
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Missing required fields: price_id, product_id",
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "This plan is not available for the selected billing interval",
 		})
 		return
 	}
@@ -110,32 +225,80 @@ func (h *PaymentHandler) CheckoutHandler(w http.ResponseWriter, r *http.Request)
 		req.CancelURL = baseURL + "/payment/cancel"
 	}
 
+	// Subscriptions are keyed by the local user ID so webhooks map back to users.id
+	user, err := h.UserRepo.GetUserByEmail(r.Context(), userInfo.Email)
+	if err != nil {
+		fmt.Printf("❌ PAYMENT: No local user for checkout: %v\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "User record not found",
+		})
+		return
+	}
+
 	// Call payment microservice using client
 	checkoutReq := paymentms.SubscriptionCheckoutRequest{
-		UserID:     userInfo.Email, // Using email as user ID for now
+		UserID:     user.ID,
 		Email:      userInfo.Email,
-		ProductID:  req.ProductID,
-		PriceID:    req.PriceID,
+		ProductID:  plan.ProductID,
+		PriceID:    price.ID,
 		SuccessURL: req.SuccessURL,
 		CancelURL:  req.CancelURL,
 	}
 
-	checkoutResp, err := h.Client.CreateSubscriptionCheckout(r.Context(), checkoutReq)
+	// Only first-time subscribers get the plan's free trial
+	if plan.TrialDays > 0 {
+		eligible, err := h.Entitlements.TrialEligible(r.Context(), user)
+		if err != nil {
+			fmt.Printf("⚠️ PAYMENT: Could not check trial eligibility, checking out without trial: %v\n", err)
+		}
+		if eligible {
+			checkoutReq.TrialPeriodDays = plan.TrialDays
+		}
+	}
+
+	// Promotion codes are checked here too: the fragment's quote is only a preview
+	if code := strings.TrimSpace(req.PromotionCode); code != "" {
+		promo, err := h.Promotions.Check(r.Context(), user, code, plan, price)
+		if err != nil {
+			fmt.Printf("⚠️ PAYMENT: Rejected promotion code %q for plan %s: %v\n", code, plan.Name, err)
+			if !errors.Is(err, services.ErrInvalidPromotionCode) {
+				writeCheckoutError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": "This promotion code is not valid for this plan",
+			})
+			return
+		}
+		checkoutReq.PromotionCode = promo.Code
+		if checkoutReq.PromotionCode == "" {
+			checkoutReq.PromotionCode, _ = services.NormalizePromotionCode(code)
+		}
+	}
+
+	// A client resubmitting the same checkout sends the same key and gets the same session
+	ctx := r.Context()
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		ctx = paymentms.WithIdempotencyKey(ctx, key)
+	}
+
+	checkoutResp, err := h.Client.CreateSubscriptionCheckout(ctx, checkoutReq)
 	if err != nil {
 		fmt.Printf("❌ PAYMENT: Failed to create checkout session: %v\n", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Failed to create checkout session",
-		})
+		writeCheckoutError(w, err)
 		return
 	}
 
 	// Return checkout URL to frontend
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]interface{}{
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
 		"checkout_url":        checkoutResp.CheckoutURL,
 		"checkout_session_id": checkoutResp.CheckoutSessionID,
-	})
+	}); err != nil {
+		fmt.Printf("❌ PAYMENT: Failed to encode checkout response: %v\n", err)
+	}
 }
 
 // SuccessHandler handles successful payment redirects
@@ -143,6 +306,14 @@ func (h *PaymentHandler) SuccessHandler(w http.ResponseWriter, r *http.Request)
 	w.Header().Set("Content-Type", "text/html")
 
 	userInfo := middleware.GetUserFromContext(r)
+
+	// The webhook may arrive after the redirect: pull the new subscription now
+	if user, err := h.UserRepo.GetUserByEmail(r.Context(), userInfo.Email); err == nil {
+		if err := h.Entitlements.SyncUser(r.Context(), user); err != nil {
+			fmt.Printf("⚠️ PAYMENT: Failed to sync subscription after checkout: %v\n", err)
+		}
+	}
+
 	navigation := layouts.NavigationLoggedIn(userInfo)
 	component := layouts.Layout("Payment Success", "Thank you for your purchase!", navigation, pages.PaymentSuccessContent())
 	if err := component.Render(r.Context(), w); err != nil {
@@ -163,3 +334,21 @@ func (h *PaymentHandler) CancelHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// writeCheckoutError answers with the status and message matching a payment service error
+func writeCheckoutError(w http.ResponseWriter, err error) {
+	resp := map[string]interface{}{
+		"error": paymentms.UserMessage(err),
+	}
+	if fields := paymentms.FieldErrors(err); errors.Is(err, paymentms.ErrValidation) && len(fields) > 0 {
+		resp["fields"] = fields
+	}
+
+	var apiErr *paymentms.Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(apiErr.RetryAfter.Seconds())))
+	}
+
+	w.WriteHeader(paymentms.HTTPStatus(err))
+	_ = json.NewEncoder(w).Encode(resp)
+}
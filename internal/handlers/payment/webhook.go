@@ -0,0 +1,159 @@
+package payment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/repositories"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/config"
+)
+
+// maxWebhookBodyBytes bounds the webhook payload read into memory
+const maxWebhookBodyBytes = 1 << 20
+
+// webhookClaimLease is how long a delivery may hold an event before another
+// delivery may take it over, in case the handler crashed mid-way
+const webhookClaimLease = 5 * time.Minute
+
+// WebhookHandler receives event notifications from the payment service
+type WebhookHandler struct {
+	Config       *config.Config
+	EventRepo    *repositories.PaymentEventRepository
+	Entitlements *services.EntitlementService
+	Cart         *services.CartService
+}
+
+// NewWebhookHandler creates a new payment webhook handler
+func NewWebhookHandler(config *config.Config, eventRepo *repositories.PaymentEventRepository, entitlements *services.EntitlementService, cart *services.CartService) *WebhookHandler {
+	return &WebhookHandler{
+		Config:       config,
+		EventRepo:    eventRepo,
+		Entitlements: entitlements,
+		Cart:         cart,
+	}
+}
+
+// PaymentWebhookHandler verifies, records and handles a payment service event.
+// Events are stored once by ID and claimed before handling, so redeliveries,
+// even concurrent ones, are acknowledged without being handled twice.
+func (h *WebhookHandler) PaymentWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if h.Config.PaymentWebhookSecret == "" {
+		fmt.Printf("❌ WEBHOOK: PAYMENT_WEBHOOK_SECRET is not set, refusing payment webhook\n")
+		writeWebhookError(w, http.StatusServiceUnavailable, "Webhook receiver not configured")
+		return
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		writeWebhookError(w, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+
+	event, err := paymentms.ParseWebhookEvent(h.Config.PaymentWebhookSecret, payload, r.Header.Get(paymentms.SignatureHeader), paymentms.DefaultSignatureTolerance)
+	if err != nil {
+		fmt.Printf("⚠️ WEBHOOK: Rejected payment webhook: %v\n", err)
+		if errors.Is(err, paymentms.ErrInvalidSignature) || errors.Is(err, paymentms.ErrSignatureExpired) {
+			writeWebhookError(w, http.StatusUnauthorized, "Invalid signature")
+		} else {
+			writeWebhookError(w, http.StatusBadRequest, "Invalid event")
+		}
+		return
+	}
+
+	if _, err := h.EventRepo.RecordEvent(r.Context(), models.PaymentEvent{
+		EventID:        event.ID,
+		EventType:      event.Type,
+		UserID:         event.Data.UserID,
+		SubscriptionID: event.Data.SubscriptionID,
+		Status:         event.Data.Status,
+	}, payload); err != nil {
+		// A 5xx makes the payment service redeliver the event later
+		fmt.Printf("❌ WEBHOOK: Failed to record event %s: %v\n", event.ID, err)
+		writeWebhookError(w, http.StatusServiceUnavailable, "Failed to record event")
+		return
+	}
+
+	// Only the delivery holding the claim handles the event, so concurrent
+	// redeliveries never run it twice
+	claimed, err := h.EventRepo.ClaimEvent(r.Context(), event.ID, webhookClaimLease)
+	if err != nil {
+		fmt.Printf("❌ WEBHOOK: Failed to claim event %s: %v\n", event.ID, err)
+		writeWebhookError(w, http.StatusServiceUnavailable, "Failed to record event")
+		return
+	}
+	if !claimed {
+		processed, err := h.EventRepo.IsProcessed(r.Context(), event.ID)
+		if err != nil {
+			fmt.Printf("❌ WEBHOOK: Failed to look up event %s: %v\n", event.ID, err)
+			writeWebhookError(w, http.StatusServiceUnavailable, "Failed to record event")
+			return
+		}
+		if !processed {
+			// Another delivery is handling it; ask for a redelivery in case that one fails
+			fmt.Printf("🔁 WEBHOOK: Event %s is being handled by another delivery\n", event.ID)
+			writeWebhookError(w, http.StatusConflict, "Event is being handled")
+			return
+		}
+		fmt.Printf("🔁 WEBHOOK: Event %s already handled, skipping\n", event.ID)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"received": true, "duplicate": true})
+		return
+	}
+
+	if project := event.Data.ProjectID; project != "" && project != h.Config.PaymentProjectID {
+		// A payment service shared by several projects may send us their events too
+		fmt.Printf("🔍 WEBHOOK: Ignoring event %s for project %s\n", event.ID, project)
+	} else if err := h.handleEvent(r, event); err != nil {
+		fmt.Printf("❌ WEBHOOK: Failed to handle event %s (%s): %v\n", event.ID, event.Type, err)
+		if err := h.EventRepo.ReleaseEvent(r.Context(), event.ID); err != nil {
+			fmt.Printf("⚠️ WEBHOOK: Failed to release event %s: %v\n", event.ID, err)
+		}
+		writeWebhookError(w, http.StatusInternalServerError, "Failed to handle event")
+		return
+	}
+
+	if err := h.EventRepo.MarkProcessed(r.Context(), event.ID); err != nil {
+		fmt.Printf("⚠️ WEBHOOK: Failed to mark event %s processed: %v\n", event.ID, err)
+	}
+
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"received": true}); err != nil {
+		fmt.Printf("⚠️ WEBHOOK: Failed to encode response: %v\n", err)
+	}
+}
+
+// handleEvent applies an event to local state. Unknown event types are recorded and acknowledged.
+func (h *WebhookHandler) handleEvent(r *http.Request, event *paymentms.WebhookEvent) error {
+	switch event.Type {
+	case paymentms.EventSubscriptionCreated, paymentms.EventSubscriptionUpdated, paymentms.EventSubscriptionCancelled:
+		fmt.Printf("💳 WEBHOOK: Subscription %s for %s is now %s\n", event.Data.SubscriptionID, event.Data.UserID, event.Data.Status)
+		return h.Entitlements.ApplyWebhookEvent(r.Context(), event)
+	case paymentms.EventCheckoutCompleted:
+		purchases, err := h.Cart.CompleteCheckout(r.Context(), event.Data.CheckoutSessionID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("🛒 WEBHOOK: Checkout %s completed, %d purchase(s) recorded\n", event.Data.CheckoutSessionID, len(purchases))
+	case paymentms.EventInvoicePaid:
+		fmt.Printf("💳 WEBHOOK: Invoice %s paid by %s (%d %s)\n", event.Data.InvoiceID, event.Data.UserID, event.Data.AmountPaid, event.Data.Currency)
+	case paymentms.EventInvoicePaymentFailed:
+		fmt.Printf("⚠️ WEBHOOK: Payment failed for invoice %s of %s\n", event.Data.InvoiceID, event.Data.UserID)
+	default:
+		fmt.Printf("🔍 WEBHOOK: Ignoring unhandled event type %s\n", event.Type)
+	}
+	return nil
+}
+
+func writeWebhookError(w http.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": message,
+	})
+}
@@ -1,48 +1,48 @@
 package settings
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
-	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/auth/session"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/middleware"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/repositories"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/config"
 	"github.com/DraconDev/go-templ-htmx-ex/templates/pages"
 )
 
+// billingHistoryLimit is how many past invoices the billing tab lists
+const billingHistoryLimit = 24
+
 type SettingsHandler struct {
-	config         *config.Config
-	sessionHandler *session.SessionHandler
-	userRepo       *repositories.UserRepository
-	prefsRepo      *repositories.PreferencesRepository
-	paymentClient  *paymentms.Client
+	config        *config.Config
+	userRepo      *repositories.UserRepository
+	prefsRepo     *repositories.PreferencesRepository
+	paymentClient *paymentms.Client
 }
 
 func NewSettingsHandler(
 	cfg *config.Config,
-	sessionHandler *session.SessionHandler,
 	userRepo *repositories.UserRepository,
 	prefsRepo *repositories.PreferencesRepository,
 	paymentClient *paymentms.Client,
 ) *SettingsHandler {
 	return &SettingsHandler{
-		config:         cfg,
-		sessionHandler: sessionHandler,
-		userRepo:       userRepo,
-		prefsRepo:      prefsRepo,
-		paymentClient:  paymentClient,
+		config:        cfg,
+		userRepo:      userRepo,
+		prefsRepo:     prefsRepo,
+		paymentClient: paymentClient,
 	}
 }
 
 func (h *SettingsHandler) SettingsPageHandler(w http.ResponseWriter, r *http.Request) {
-	// 1. Get session user info
-	userInfo := h.sessionHandler.GetUserInfo(r)
-	if !userInfo.LoggedIn {
-		http.Redirect(w, r, "/login", http.StatusSeeOther)
-		return
-	}
+	// 1. Get session user info (route policy guarantees a session)
+	userInfo := middleware.GetUserFromContext(r)
 
 	// 2. Get local user record to get UUID
 	user, err := h.userRepo.GetUserByEmail(r.Context(), userInfo.Email)
@@ -81,12 +81,8 @@ func (h *SettingsHandler) UpdateSettingsHandler(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// 1. Get session user info
-	userInfo := h.sessionHandler.GetUserInfo(r)
-	if !userInfo.LoggedIn {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
+	// 1. Get session user info (route policy guarantees a session)
+	userInfo := middleware.GetUserFromContext(r)
 
 	// 2. Get local user record
 	user, err := h.userRepo.GetUserByEmail(r.Context(), userInfo.Email)
@@ -139,12 +135,8 @@ func (h *SettingsHandler) UpdateSettingsHandler(w http.ResponseWriter, r *http.R
 }
 
 func (h *SettingsHandler) BillingPortalHandler(w http.ResponseWriter, r *http.Request) {
-	// 1. Get session user info
-	userInfo := h.sessionHandler.GetUserInfo(r)
-	if !userInfo.LoggedIn {
-		http.Redirect(w, r, "/login", http.StatusSeeOther)
-		return
-	}
+	// 1. Get session user info (route policy guarantees a session)
+	userInfo := middleware.GetUserFromContext(r)
 
 	// 2. Get local user for user_id
 	user, err := h.userRepo.GetUserByEmail(r.Context(), userInfo.Email)
@@ -159,10 +151,107 @@ func (h *SettingsHandler) BillingPortalHandler(w http.ResponseWriter, r *http.Re
 	portalURL, err := h.paymentClient.CreateCustomerPortal(r.Context(), user.ID, returnURL)
 	if err != nil {
 		fmt.Printf("Error creating portal session: %v\n", err)
-		http.Redirect(w, r, "/settings?error=portal_failed", http.StatusSeeOther)
+		switch {
+		case errors.Is(err, paymentms.ErrNotFound):
+			// No billing customer yet: nothing to manage, offer a plan instead
+			http.Redirect(w, r, "/pricing", http.StatusSeeOther)
+		case errors.Is(err, paymentms.ErrUnavailable), errors.Is(err, paymentms.ErrRateLimited):
+			http.Redirect(w, r, "/settings?error=billing_unavailable", http.StatusSeeOther)
+		default:
+			http.Redirect(w, r, "/settings?error=portal_failed", http.StatusSeeOther)
+		}
 		return
 	}
 
 	// 4. Redirect to portal
 	http.Redirect(w, r, portalURL, http.StatusSeeOther)
 }
+
+// BillingHistoryHandler renders past invoices and the card on file in the
+// user's timezone (HTMX fragment for the settings Billing tab)
+func (h *SettingsHandler) BillingHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	userInfo := middleware.GetUserFromContext(r)
+
+	user, err := h.userRepo.GetUserByEmail(r.Context(), userInfo.Email)
+	if err != nil {
+		http.Error(w, "User record not found", http.StatusInternalServerError)
+		return
+	}
+
+	loc := h.userLocation(r, user.ID)
+	data := pages.BillingHistory{Timezone: loc.String()}
+
+	invoices, err := h.paymentClient.ListInvoices(r.Context(), user.ID, billingHistoryLimit)
+	if err != nil && !errors.Is(err, paymentms.ErrNotFound) {
+		fmt.Printf("⚠️ SETTINGS: Failed to list invoices: %v\n", err)
+		data.LoadFailed = true
+	}
+	methods, err := h.paymentClient.ListPaymentMethods(r.Context(), user.ID)
+	if err != nil && !errors.Is(err, paymentms.ErrNotFound) {
+		fmt.Printf("⚠️ SETTINGS: Failed to list payment methods: %v\n", err)
+		data.LoadFailed = true
+	}
+
+	for _, invoice := range invoices {
+		amount := invoice.AmountPaid
+		if invoice.Status != "paid" {
+			amount = invoice.AmountDue
+		}
+		number := invoice.Number
+		if number == "" {
+			number = invoice.ID
+		}
+		data.Invoices = append(data.Invoices, pages.InvoiceRow{
+			Number: number,
+			Date:   invoice.Created.In(loc).Format("Jan 02, 2006 15:04"),
+			Amount: catalog.FormatAmount(amount, invoice.Currency),
+			Status: invoice.Status,
+			PDFURL: invoice.PDFURL,
+		})
+	}
+
+	if card := cardOnFile(methods); card != nil {
+		brand := card.Brand
+		if brand != "" {
+			brand = strings.ToUpper(brand[:1]) + brand[1:]
+		}
+		data.Card = &pages.CardOnFile{
+			Brand:   brand,
+			Last4:   card.Last4,
+			Expires: fmt.Sprintf("%02d/%d", card.ExpMonth, card.ExpYear),
+		}
+	}
+
+	if err := pages.BillingHistoryContent(data).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render billing history", http.StatusInternalServerError)
+	}
+}
+
+// userLocation returns the timezone from the user's preferences, UTC if unset or unknown
+func (h *SettingsHandler) userLocation(r *http.Request, userID string) *time.Location {
+	prefs, err := h.prefsRepo.GetPreferences(r.Context(), userID)
+	if err != nil || prefs.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(prefs.Timezone)
+	if err != nil {
+		fmt.Printf("⚠️ SETTINGS: Unknown timezone %q: %v\n", prefs.Timezone, err)
+		return time.UTC
+	}
+	return loc
+}
+
+// cardOnFile picks the default card, or the first one
+func cardOnFile(methods []paymentms.PaymentMethod) *paymentms.PaymentMethod {
+	for i := range methods {
+		if methods[i].Default {
+			return &methods[i]
+		}
+	}
+	if len(methods) > 0 {
+		return &methods[0]
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package shop
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/middleware"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/repositories"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/config"
+	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
+	"github.com/DraconDev/go-templ-htmx-ex/templates/pages"
+	"github.com/gorilla/mux"
+)
+
+// ShopHandler handles the shop, the session-backed cart and cart checkout
+type ShopHandler struct {
+	Config   *config.Config
+	Cart     *services.CartService
+	UserRepo *repositories.UserRepository
+}
+
+// NewShopHandler creates a new shop handler
+func NewShopHandler(config *config.Config, cart *services.CartService, userRepo *repositories.UserRepository) *ShopHandler {
+	return &ShopHandler{
+		Config:   config,
+		Cart:     cart,
+		UserRepo: userRepo,
+	}
+}
+
+// ShopPageHandler renders the shop items with a cart summary
+func (h *ShopHandler) ShopPageHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	// Get user info from middleware context (route policy guarantees a session)
+	userInfo := middleware.GetUserFromContext(r)
+
+	cart, err := h.Cart.Cart(r.Context(), sessionID(r))
+	if err != nil {
+		fmt.Printf("⚠️ SHOP: Failed to load cart: %v\n", err)
+		cart = &models.Cart{}
+	}
+
+	content := pages.ShopContent(h.Config.Catalog.Items(), cart)
+	component := layouts.Layout("Shop", "One-off add-ons for your account.", layouts.NavigationLoggedIn(userInfo), content)
+	if err := component.Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render shop page", http.StatusInternalServerError)
+	}
+}
+
+// CartPageHandler renders the cart page
+func (h *ShopHandler) CartPageHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	userInfo := middleware.GetUserFromContext(r)
+
+	cart, err := h.Cart.Cart(r.Context(), sessionID(r))
+	if err != nil {
+		fmt.Printf("❌ SHOP: Failed to load cart: %v\n", err)
+		http.Error(w, "Failed to load cart", http.StatusInternalServerError)
+		return
+	}
+
+	component := layouts.Layout("Cart", "Review your cart and check out.", layouts.NavigationLoggedIn(userInfo), pages.CartPageContent(cart))
+	if err := component.Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render cart page", http.StatusInternalServerError)
+	}
+}
+
+// AddToCartHandler adds an item to the cart and renders the cart summary (HTMX fragment)
+func (h *ShopHandler) AddToCartHandler(w http.ResponseWriter, r *http.Request) {
+	quantity, err := strconv.Atoi(r.FormValue("quantity"))
+	if err != nil {
+		quantity = 1
+	}
+
+	message := ""
+	if err := h.Cart.Add(r.Context(), sessionID(r), r.FormValue("item"), quantity); err != nil {
+		fmt.Printf("⚠️ SHOP: Failed to add %s to cart: %v\n", r.FormValue("item"), err)
+		message = cartErrorMessage(err)
+	}
+
+	cart, err := h.Cart.Cart(r.Context(), sessionID(r))
+	if err != nil {
+		http.Error(w, "Failed to load cart", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	if err := pages.CartSummary(cart, message).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render cart", http.StatusInternalServerError)
+	}
+}
+
+// UpdateCartItemHandler changes a line's quantity and renders the cart (HTMX fragment)
+func (h *ShopHandler) UpdateCartItemHandler(w http.ResponseWriter, r *http.Request) {
+	message := ""
+	quantity, err := strconv.Atoi(r.FormValue("quantity"))
+	if err != nil {
+		message = cartErrorMessage(services.ErrInvalidQuantity)
+	} else if err := h.Cart.SetQuantity(r.Context(), sessionID(r), mux.Vars(r)["price_id"], quantity); err != nil {
+		fmt.Printf("⚠️ SHOP: Failed to update cart quantity: %v\n", err)
+		message = cartErrorMessage(err)
+	}
+
+	h.renderCart(w, r, message)
+}
+
+// RemoveCartItemHandler removes a line and renders the cart (HTMX fragment)
+func (h *ShopHandler) RemoveCartItemHandler(w http.ResponseWriter, r *http.Request) {
+	message := ""
+	if err := h.Cart.Remove(r.Context(), sessionID(r), mux.Vars(r)["price_id"]); err != nil {
+		fmt.Printf("⚠️ SHOP: Failed to remove cart item: %v\n", err)
+		message = cartErrorMessage(err)
+	}
+
+	h.renderCart(w, r, message)
+}
+
+// CartCheckoutHandler starts a payment service checkout for the cart.
+// HTMX follows the HX-Redirect header to the hosted checkout page.
+func (h *ShopHandler) CartCheckoutHandler(w http.ResponseWriter, r *http.Request) {
+	userInfo := middleware.GetUserFromContext(r)
+
+	user, err := h.UserRepo.GetUserByEmail(r.Context(), userInfo.Email)
+	if err != nil {
+		fmt.Printf("❌ SHOP: No local user for checkout: %v\n", err)
+		h.renderCart(w, r, "We couldn't find your account. Please sign in again.")
+		return
+	}
+
+	baseURL := h.Config.RedirectURL
+	checkout, err := h.Cart.Checkout(r.Context(), sessionID(r), user, baseURL+"/cart/success", baseURL+"/cart")
+	if err != nil {
+		fmt.Printf("❌ SHOP: Failed to create cart checkout: %v\n", err)
+		h.renderCart(w, r, cartErrorMessage(err))
+		return
+	}
+
+	fmt.Printf("🛒 SHOP: Created cart checkout %s for %s\n", checkout.CheckoutSessionID, user.ID)
+	w.Header().Set("HX-Redirect", checkout.CheckoutURL)
+	w.WriteHeader(http.StatusOK)
+}
+
+// CartSuccessHandler empties the cart after the hosted checkout redirects back
+func (h *ShopHandler) CartSuccessHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	userInfo := middleware.GetUserFromContext(r)
+
+	// Purchases are completed by the checkout.completed webhook, not by this redirect
+	if err := h.Cart.Clear(r.Context(), sessionID(r)); err != nil {
+		fmt.Printf("⚠️ SHOP: Failed to clear cart: %v\n", err)
+	}
+
+	component := layouts.Layout("Order Received", "Thank you for your order.", layouts.NavigationLoggedIn(userInfo), pages.CartSuccessContent())
+	if err := component.Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+func (h *ShopHandler) renderCart(w http.ResponseWriter, r *http.Request, message string) {
+	cart, err := h.Cart.Cart(r.Context(), sessionID(r))
+	if err != nil {
+		fmt.Printf("❌ SHOP: Failed to load cart: %v\n", err)
+		http.Error(w, "Failed to load cart", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	if err := pages.CartContent(cart, message).Render(r.Context(), w); err != nil {
+		http.Error(w, "Failed to render cart", http.StatusInternalServerError)
+	}
+}
+
+// sessionID returns the login session the cart belongs to
+func sessionID(r *http.Request) string {
+	cookie, err := r.Cookie("session_id")
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// cartErrorMessage returns a message that is safe to show to end users
+func cartErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, services.ErrUnknownItem):
+		return "That item is not available."
+	case errors.Is(err, services.ErrInvalidQuantity):
+		return fmt.Sprintf("Please choose a quantity between 1 and %d.", models.MaxCartQuantity)
+	case errors.Is(err, services.ErrMixedCurrency):
+		return "Items priced in different currencies can't be bought together."
+	case errors.Is(err, services.ErrCartEmpty):
+		return "Your cart is empty."
+	case errors.Is(err, paymentms.ErrValidation), errors.Is(err, paymentms.ErrNotFound),
+		errors.Is(err, paymentms.ErrRateLimited), errors.Is(err, paymentms.ErrUnavailable),
+		errors.Is(err, paymentms.ErrUnauthorized):
+		return paymentms.UserMessage(err)
+	default:
+		return "Something went wrong with your cart. Please try again."
+	}
+}
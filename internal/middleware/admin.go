@@ -1,32 +1,72 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/config"
+	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
 )
 
-// RequireAdmin middleware that checks if user is admin
-func RequireAdmin(next http.Handler, adminEmail string) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		userInfo := GetUserFromContext(r)
+// PermissionChecker decides whether a logged-in user holds a permission.
+// Without a database only the configured ADMIN_EMAIL is granted anything;
+// main replaces it with DatabasePermissionChecker when a database is available.
+var PermissionChecker = func(r *http.Request, userInfo layouts.UserInfo, permission string) bool {
+	return config.Current != nil && config.Current.IsAdmin(userInfo.Email)
+}
 
-		if !userInfo.LoggedIn {
-			http.Redirect(w, r, "/", http.StatusFound)
-			return
-		}
+// RequirePermission middleware that only lets users holding the permission through.
+// It relies on AuthMiddleware having placed the user in the request context.
+func RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userInfo := GetUserFromContext(r)
 
-		// Check if user is admin
-		if userInfo.Email != adminEmail {
-			http.Error(w, "Access denied: Admin privileges required", http.StatusForbidden)
-			return
-		}
+			if !userInfo.LoggedIn {
+				if hasPrefix(r.URL.Path, "/api/") {
+					writeJSONError(w, http.StatusUnauthorized, "Authentication required")
+					return
+				}
+				http.Redirect(w, r, loginRedirectURL(r), http.StatusFound)
+				return
+			}
+
+			if !PermissionChecker(r, userInfo, permission) {
+				fmt.Printf("🔐 MIDDLEWARE: Permission %s denied for %s\n", permission, userInfo.Email)
+				if hasPrefix(r.URL.Path, "/api/") {
+					writeJSONError(w, http.StatusForbidden, fmt.Sprintf("Missing permission: %s", permission))
+					return
+				}
+				http.Error(w, "Access denied: Insufficient privileges", http.StatusForbidden)
+				return
+			}
 
-		next.ServeHTTP(w, r)
-	})
+			next.ServeHTTP(w, r)
+		})
+	}
 }
 
-// RequireConfigAdmin middleware that uses config admin email
-func RequireConfigAdmin(next http.Handler) http.Handler {
-	return RequireAdmin(next, config.Current.AdminEmail)
+// RequireAdmin middleware that checks the user may open the admin area
+func RequireAdmin(next http.Handler) http.Handler {
+	return RequirePermission(models.PermissionAdminAccess)(next)
+}
+
+// DatabasePermissionChecker returns a PermissionChecker backed by the user's
+// roles, cached by the role service
+func DatabasePermissionChecker(roles *services.RoleService) func(*http.Request, layouts.UserInfo, string) bool {
+	return func(r *http.Request, userInfo layouts.UserInfo, permission string) bool {
+		permissions, err := roles.UserPermissions(r.Context(), userInfo.Email)
+		if err != nil {
+			fmt.Printf("🔐 MIDDLEWARE: Could not fetch permissions for %s: %v\n", userInfo.Email, err)
+			return false
+		}
+		for _, granted := range permissions {
+			if granted == permission {
+				return true
+			}
+		}
+		return false
+	}
 }
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
+)
+
+func TestRequirePermission(t *testing.T) {
+	originalChecker := PermissionChecker
+	defer func() { PermissionChecker = originalChecker }()
+
+	grants := map[string][]string{
+		"admin@example.com":   {"admin:access", "users:read", "logs:read"},
+		"support@example.com": {"admin:access", "logs:read"},
+	}
+	PermissionChecker = func(r *http.Request, userInfo layouts.UserInfo, permission string) bool {
+		for _, granted := range grants[userInfo.Email] {
+			if granted == permission {
+				return true
+			}
+		}
+		return false
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	protected := AuthMiddleware(RequirePermission("users:read")(handler))
+
+	t.Run("anonymous_api_unauthorized", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/admin/users", nil)
+		rr := httptest.NewRecorder()
+
+		protected.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for anonymous user, got %d", rr.Code)
+		}
+	})
+
+	t.Run("missing_permission_api_forbidden", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/admin/users", nil)
+		req.AddCookie(withCachedSession(t, "support-session-123", layouts.UserInfo{LoggedIn: true, Email: "support@example.com"}))
+		rr := httptest.NewRecorder()
+
+		protected.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 without users:read, got %d", rr.Code)
+		}
+		if contentType := rr.Header().Get("Content-Type"); contentType != "application/json" {
+			t.Errorf("Expected JSON response, got %q", contentType)
+		}
+	})
+
+	t.Run("missing_permission_page_forbidden", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.AddCookie(withCachedSession(t, "user-session-456", layouts.UserInfo{LoggedIn: true, Email: "user@example.com"}))
+		rr := httptest.NewRecorder()
+
+		AuthMiddleware(RequireAdmin(handler)).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 for user without admin:access, got %d", rr.Code)
+		}
+	})
+
+	t.Run("support_role_reaches_admin_page", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.AddCookie(withCachedSession(t, "support-session-789", layouts.UserInfo{LoggedIn: true, Email: "support@example.com"}))
+		rr := httptest.NewRecorder()
+
+		AuthMiddleware(RequireAdmin(handler)).ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected 200 for support role, got %d", rr.Code)
+		}
+	})
+
+	t.Run("permission_granted", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/admin/users", nil)
+		req.AddCookie(withCachedSession(t, "admin-session-789", layouts.UserInfo{LoggedIn: true, Email: "admin@example.com"}))
+		rr := httptest.NewRecorder()
+
+		protected.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected 200 with users:read, got %d", rr.Code)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
 )
@@ -14,13 +15,13 @@ type UserContextKey string
 
 const userContextKey UserContextKey = "user"
 
-// AuthMiddleware validates server sessions for protected routes
+// AuthMiddleware validates server sessions and enforces each route's declared access policy
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
-		category := getRouteCategory(path)
+		policy := GetRoutePolicy(r)
 
-		fmt.Printf("🔐 MIDDLEWARE: Processing route %s [Category: %s]\n", path, category)
+		fmt.Printf("🔐 MIDDLEWARE: Processing route %s [Policy: %s]\n", path, policy)
 
 		// Skip session validation for auth callback route (no session yet during callback flow)
 		var userInfo layouts.UserInfo
@@ -31,32 +32,30 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		}
 		ctx := context.WithValue(r.Context(), userContextKey, userInfo)
 
-		// Check if this route requires authentication
-		if requiresAuthentication(path) {
-			// If route requires auth but user is not logged in, redirect
-			if !userInfo.LoggedIn {
-				if r.URL.Path[:5] == "/api/" {
-					// For API routes, return JSON error
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(http.StatusUnauthorized)
-					if err := json.NewEncoder(w).Encode(map[string]interface{}{
-						"error": "Authentication required",
-					}); err != nil {
-						fmt.Printf("🔐 MIDDLEWARE: Failed to encode error response: %v\n", err)
-					}
-					return
-				}
-
-				// For web routes, redirect to login
-				http.Redirect(w, r, "/login", http.StatusFound)
+		if policy.RequiresAuthentication() && !userInfo.LoggedIn {
+			if hasPrefix(path, "/api/") {
+				writeJSONError(w, http.StatusUnauthorized, "Authentication required")
 				return
 			}
+
+			// For web routes, redirect to login and come back here afterwards
+			http.Redirect(w, r, loginRedirectURL(r), http.StatusFound)
+			return
 		}
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// loginRedirectURL returns the login page URL, carrying the current page as
+// return_to for GET requests so deep links survive the login round-trip
+func loginRedirectURL(r *http.Request) string {
+	if r.Method != http.MethodGet {
+		return "/login"
+	}
+	return "/login?return_to=" + url.QueryEscape(r.URL.RequestURI())
+}
+
 // GetUserFromContext gets user info from request context
 func GetUserFromContext(r *http.Request) layouts.UserInfo {
 	userInfo, ok := r.Context().Value(userContextKey).(layouts.UserInfo)
@@ -66,34 +65,15 @@ func GetUserFromContext(r *http.Request) layouts.UserInfo {
 	return userInfo
 }
 
-// getRouteCategory returns the category of a route for debugging
-func getRouteCategory(path string) string {
-	// Protected routes that require authentication
-	if path == "/profile" || path == "/admin" || hasPrefix(path, "/api/admin") {
-		return "PROTECTED"
-	}
-
-	// Public routes
-	if path == "/" || path == "/health" || path == "/login" || path == "/test" || path == "/auth/callback" || hasPrefix(path, "/auth/") {
-		return "PUBLIC"
-	}
-
-	// Auth API routes
-	if hasPrefix(path, "/api/auth/") {
-		return "AUTH_API"
-	}
-
-	return "UNKNOWN"
-}
-
-// requiresAuthentication checks if a route requires authentication
-func requiresAuthentication(path string) bool {
-	// Authentication API routes should NOT require authentication (they handle auth tokens)
-	if hasPrefix(path, "/api/auth/") {
-		return false
+// writeJSONError writes a JSON error body for API routes
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": message,
+	}); err != nil {
+		fmt.Printf("🔐 MIDDLEWARE: Failed to encode error response: %v\n", err)
 	}
-
-	return path == "/profile" || path == "/admin" || hasPrefix(path, "/api/admin")
 }
 
 // hasPrefix is a simple string prefix check
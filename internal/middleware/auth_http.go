@@ -1,91 +1,80 @@
 package middleware
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"time"
+	"sync"
 
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/authms"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/config"
 	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
 )
 
-// validateSessionWithAuthService validates session by calling auth microservice
-func validateSessionWithAuthService(sessionID string) (layouts.UserInfo, error) {
-	fmt.Printf("🔐 MIDDLEWARE: Calling auth service to validate session %s\n", sessionID[:8]+"...")
-
-	// Create HTTP client with timeout
-	client := &http.Client{Timeout: 10 * time.Second}
-
-	// Prepare request to auth service
-	reqBody := map[string]string{"session_id": sessionID}
-	jsonData, err := json.Marshal(reqBody)
-	if err != nil {
-		fmt.Printf("🔐 MIDDLEWARE: Failed to marshal request: %v\n", err)
-		return layouts.UserInfo{LoggedIn: false}, err
-	}
+var (
+	authClientMu sync.Mutex
+	authClient   *authms.Client
+)
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/auth/session/refresh", config.Current.AuthServiceURL), bytes.NewBuffer(jsonData))
-	if err != nil {
-		fmt.Printf("🔐 MIDDLEWARE: Failed to create request: %v\n", err)
-		return layouts.UserInfo{LoggedIn: false}, err
-	}
+// SetAuthClient sets the auth service client used for session validation
+func SetAuthClient(client *authms.Client) {
+	authClientMu.Lock()
+	defer authClientMu.Unlock()
 
-	req.Header.Set("Content-Type", "application/json")
+	authClient = client
+}
 
-	fmt.Printf("🔐 MIDDLEWARE: Sending validation request to auth service\n")
+// getAuthClient returns the configured auth client, building one from config on first use
+func getAuthClient() *authms.Client {
+	authClientMu.Lock()
+	defer authClientMu.Unlock()
 
-	// Send request to auth service
-	resp, err := client.Do(req)
-	if err != nil {
-		fmt.Printf("🔐 MIDDLEWARE: Failed to call auth service: %v\n", err)
-		// Don't fail the request if auth service is unavailable
-		return layouts.UserInfo{LoggedIn: false}, nil
+	if authClient == nil {
+		authClient = authms.New(config.Current.AuthServiceURL)
 	}
-	defer resp.Body.Close()
+	return authClient
+}
 
-	fmt.Printf("🔐 MIDDLEWARE: Auth service response status: %s\n", resp.Status)
+// AuthServiceStatus reports the auth service circuit breaker state for /health
+func AuthServiceStatus() authms.BreakerStatus {
+	authClientMu.Lock()
+	defer authClientMu.Unlock()
 
-	// Parse response
-	var respData map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&respData); err != nil {
-		fmt.Printf("🔐 MIDDLEWARE: Failed to parse response: %v\n", err)
-		return layouts.UserInfo{LoggedIn: false}, nil
+	if authClient == nil {
+		return authms.BreakerStatus{State: authms.BreakerClosed}
 	}
+	return authClient.BreakerStatus()
+}
 
-	fmt.Printf("🔐 MIDDLEWARE: Auth service response: %v\n", respData)
-
-	// Check if session is valid by looking for user_context or success response
-	var userInfo layouts.UserInfo
-
-	// Try user_context first (existing format)
-	if userContext, ok := respData["user_context"].(map[string]interface{}); ok && userContext != nil {
-		userInfo.LoggedIn = true
+// validateSessionWithAuthService validates session by calling auth microservice.
+// A session the auth service rejects returns LoggedIn false with no error; an
+// unreachable auth service returns the error so the result is not cached.
+func validateSessionWithAuthService(ctx context.Context, sessionID string) (layouts.UserInfo, error) {
+	fmt.Printf("🔐 MIDDLEWARE: Calling auth service to validate session %s\n", sessionPrefix(sessionID))
 
-		if name, ok := userContext["name"].(string); ok && name != "" {
-			userInfo.Name = name
-		}
-		if email, ok := userContext["email"].(string); ok && email != "" {
-			userInfo.Email = email
-		}
-		if picture, ok := userContext["picture"].(string); ok && picture != "" {
-			userInfo.Picture = picture
-		}
-		if userID, ok := userContext["user_id"].(string); ok && userID != "" {
-			fmt.Printf("🔐 MIDDLEWARE: Session valid for user: %s (%s)\n", userInfo.Name, userInfo.Email)
+	session, err := getAuthClient().RefreshSession(ctx, authms.RefreshSessionRequest{SessionID: sessionID})
+	if err != nil {
+		if authms.IsRejected(err) {
+			fmt.Printf("🔐 MIDDLEWARE: Session rejected by auth service\n")
+			return layouts.UserInfo{LoggedIn: false}, nil
 		}
+		fmt.Printf("🔐 MIDDLEWARE: Failed to call auth service: %v\n", err)
+		return layouts.UserInfo{LoggedIn: false}, err
+	}
 
-		return userInfo, nil
+	// Prefer the user context; a bare success response still counts as logged in
+	if session.HasUser() {
+		fmt.Printf("🔐 MIDDLEWARE: Session valid for user: %s (%s)\n", session.UserContext.Name, session.UserContext.Email)
+		return layouts.UserInfo{
+			LoggedIn: true,
+			Name:     session.UserContext.Name,
+			Email:    session.UserContext.Email,
+			Picture:  session.UserContext.Picture,
+		}, nil
 	}
 
-	// Try session_id validation response (new format from working reference)
-	if success, ok := respData["success"].(bool); ok && success {
-		// For session_id based validation, just mark as logged in
-		// User details would come from other endpoints if needed
-		userInfo.LoggedIn = true
+	if session.Success {
 		fmt.Printf("🔐 MIDDLEWARE: Session validated successfully (session_id format)\n")
-		return userInfo, nil
+		return layouts.UserInfo{LoggedIn: true}, nil
 	}
 
 	fmt.Printf("🔐 MIDDLEWARE: Session validation failed\n")
@@ -4,9 +4,29 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
 	"testing"
 )
 
+// TestMain registers the route policies that routes.SetupRoutes would declare
+// for the paths exercised in this package
+func TestMain(m *testing.M) {
+	RegisterRoutePolicy("/", PolicyPublic)
+	RegisterRoutePolicy("/login", PolicyPublic)
+	RegisterRoutePolicy("/health", PolicyPublic)
+	RegisterRoutePolicy("/test", PolicyPublic)
+	RegisterRoutePolicy("/profile", PolicyAuthenticated)
+	RegisterRoutePolicy("/admin", PolicyAdmin)
+	RegisterRoutePolicy("/api/admin/users", PolicyAdmin)
+	RegisterRoutePolicy("/api/admin/analytics", PolicyAdmin)
+	RegisterRoutePolicy("/api/auth/exchange-code", PolicyAuthAPI)
+	RegisterRoutePolicy("/api/auth/set-session", PolicyAuthAPI)
+	RegisterRoutePolicy("/api/auth/logout", PolicyAuthAPI)
+
+	os.Exit(m.Run())
+}
+
 func TestAuthMiddlewareBehavior(t *testing.T) {
 	fmt.Println("🧪 Testing AuthMiddleware Behavior")
 
@@ -77,8 +97,8 @@ func TestAuthMiddlewareBehavior(t *testing.T) {
 				}
 
 				location := rr.Header().Get("Location")
-				if location != "/login" {
-					t.Errorf("Expected redirect to /login, got %q", location)
+				if expected := "/login?return_to=" + url.QueryEscape(route); location != expected {
+					t.Errorf("Expected redirect to %q, got %q", expected, location)
 				}
 			})
 		}
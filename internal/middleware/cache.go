@@ -1,30 +1,199 @@
 package middleware
 
 import (
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
 	"github.com/dracondev/go-templ-htmx-ex/libs/cachex"
 )
 
-// SessionCache stores validation results with 15-second TTL
+// SessionGracePeriod is how long a validated session is remembered as a fallback
+// for when the auth service is unreachable. Set before the cache is initialized;
+// 0 disables the fallback.
+var SessionGracePeriod = 5 * time.Minute
+
+// SessionCacheMaxEntries bounds each in-process session cache; the least
+// recently used entries are evicted beyond it. Set before the cache is initialized.
+var SessionCacheMaxEntries = 10000
+
+const (
+	// sessionCacheTTL is how long a validation result is trusted without asking the auth service
+	sessionCacheTTL = 15 * time.Second
+	// rejectedSessionTTL is how long a session ID the auth service rejected is remembered
+	rejectedSessionTTL = 5 * time.Minute
+)
+
+// cachedSession is a validation result and when it was obtained
+type cachedSession struct {
+	User        layouts.UserInfo `json:"user"`
+	ValidatedAt time.Time        `json:"validated_at"`
+}
+
+// SessionCache stores validation results with 15-second TTL.
+// Its backends may be in-process or shared between replicas.
 type SessionCache struct {
-	*cachex.Cache[layouts.UserInfo]
+	entries cachex.Backend[cachedSession]
+
+	// rejected is a negative cache of session IDs the auth service refused, so
+	// a bad cookie is not re-validated on every request
+	rejected cachex.Backend[struct{}]
+
+	// lastKnown keeps validated sessions for SessionGracePeriod so they keep
+	// working during an auth service outage (stale-while-revalidate)
+	lastKnown cachex.Backend[cachedSession]
+
+	// revokedUsers records when all of a user's sessions were revoked. Entries
+	// validated before that time are ignored, which reaches other replicas
+	// sharing the backend without them knowing the session IDs.
+	revokedUsers cachex.Backend[time.Time]
+
+	// userSessions indexes session IDs cached by this process by user email.
+	// It expires with the entries it points to and is bounded like them.
+	userMu       sync.Mutex
+	userSessions *cachex.Cache[map[string]struct{}]
 }
 
-// NewSessionCache creates a new session cache
+// NewSessionCache creates a new in-process session cache
 func NewSessionCache() *SessionCache {
+	return newSessionCache(
+		cachex.NewLRU[cachedSession](sessionCacheTTL, SessionCacheMaxEntries),
+		cachex.NewLRU[struct{}](rejectedSessionTTL, SessionCacheMaxEntries),
+		cachex.NewLRU[cachedSession](SessionGracePeriod, SessionCacheMaxEntries),
+		cachex.NewLRU[time.Time](revokedUserTTL(), SessionCacheMaxEntries),
+	)
+}
+
+// NewRedisSessionCache creates a session cache shared between replicas through Redis
+func NewRedisSessionCache(client *cachex.RedisClient) *SessionCache {
+	entries := cachex.NewRedis[cachedSession](client, "session:", sessionCacheTTL)
+	rejected := cachex.NewRedis[struct{}](client, "session-rejected:", rejectedSessionTTL)
+	lastKnown := cachex.NewRedis[cachedSession](client, "session-stale:", SessionGracePeriod)
+	revokedUsers := cachex.NewRedis[time.Time](client, "session-revoked-user:", revokedUserTTL())
+
+	reportError := func(err error) {
+		fmt.Printf("🔐 MIDDLEWARE: ⚠️ Session cache backend error: %v\n", err)
+	}
+	entries.OnError = reportError
+	rejected.OnError = reportError
+	lastKnown.OnError = reportError
+	revokedUsers.OnError = reportError
+
+	return newSessionCache(entries, rejected, lastKnown, revokedUsers)
+}
+
+func newSessionCache(entries cachex.Backend[cachedSession], rejected cachex.Backend[struct{}], lastKnown cachex.Backend[cachedSession], revokedUsers cachex.Backend[time.Time]) *SessionCache {
 	return &SessionCache{
-		Cache: cachex.New[layouts.UserInfo](15 * time.Second),
+		entries:      entries,
+		rejected:     rejected,
+		lastKnown:    lastKnown,
+		revokedUsers: revokedUsers,
+		userSessions: cachex.NewLRU[map[string]struct{}](revokedUserTTL(), SessionCacheMaxEntries),
+	}
+}
+
+// revokedUserTTL must outlive any entry cached before the revocation
+func revokedUserTTL() time.Duration {
+	return sessionCacheTTL + SessionGracePeriod
+}
+
+// Stats returns the validation cache counters, when the backend is in-process
+func (c *SessionCache) Stats() (cachex.Stats, bool) {
+	if cache, ok := c.entries.(*cachex.Cache[cachedSession]); ok {
+		return cache.Stats(), true
 	}
+	return cachex.Stats{}, false
 }
 
 // Get retrieves cached user info if not expired
 func (c *SessionCache) Get(sessionID string) (layouts.UserInfo, bool) {
-	return c.Cache.Get(sessionID)
+	return c.lookup(c.entries, sessionID)
 }
 
 // Set caches user info with 15-second TTL
 func (c *SessionCache) Set(sessionID string, userInfo layouts.UserInfo) {
-	c.Cache.Set(sessionID, userInfo)
+	entry := cachedSession{User: userInfo, ValidatedAt: time.Now()}
+	c.entries.SetWithTTL(sessionID, entry, sessionCacheTTL)
+	if userInfo.LoggedIn && SessionGracePeriod > 0 {
+		c.lastKnown.SetWithTTL(sessionID, entry, SessionGracePeriod)
+	}
+
+	if userInfo.Email == "" {
+		return
+	}
+
+	c.userMu.Lock()
+	defer c.userMu.Unlock()
+
+	sessions, ok := c.userSessions.Get(userInfo.Email)
+	if !ok {
+		sessions = make(map[string]struct{})
+	}
+	sessions[sessionID] = struct{}{}
+	c.userSessions.Set(userInfo.Email, sessions) // refreshes the index TTL
+}
+
+// GetStale returns the last validated user info for a session within the grace period
+func (c *SessionCache) GetStale(sessionID string) (layouts.UserInfo, bool) {
+	return c.lookup(c.lastKnown, sessionID)
+}
+
+// lookup reads an entry, ignoring it if the user's sessions were revoked after it was cached
+func (c *SessionCache) lookup(backend cachex.Backend[cachedSession], sessionID string) (layouts.UserInfo, bool) {
+	entry, found := backend.Get(sessionID)
+	if !found {
+		return layouts.UserInfo{}, false
+	}
+
+	if entry.User.Email != "" {
+		if revokedAt, revoked := c.revokedUsers.Get(entry.User.Email); revoked && !entry.ValidatedAt.After(revokedAt) {
+			backend.Delete(sessionID)
+			return layouts.UserInfo{}, false
+		}
+	}
+	return entry.User, true
+}
+
+// MarkRejected remembers that the auth service refused a session ID
+func (c *SessionCache) MarkRejected(sessionID string) {
+	c.rejected.SetWithTTL(sessionID, struct{}{}, rejectedSessionTTL)
+}
+
+// IsRejected reports whether a session ID was recently refused by the auth service
+func (c *SessionCache) IsRejected(sessionID string) bool {
+	_, found := c.rejected.Get(sessionID)
+	return found
+}
+
+// Delete evicts a single session
+func (c *SessionCache) Delete(sessionID string) {
+	if entry, found := c.entries.Get(sessionID); found && entry.User.Email != "" {
+		c.userMu.Lock()
+		if sessions, ok := c.userSessions.Get(entry.User.Email); ok {
+			delete(sessions, sessionID)
+		}
+		c.userMu.Unlock()
+	}
+
+	c.entries.Delete(sessionID)
+	c.lastKnown.Delete(sessionID)
+}
+
+// DeleteUser evicts every cached session belonging to the user and returns how
+// many this process had cached. Sessions cached by other replicas are
+// invalidated through the shared revocation marker.
+func (c *SessionCache) DeleteUser(email string) int {
+	c.revokedUsers.SetWithTTL(email, time.Now(), revokedUserTTL())
+
+	c.userMu.Lock()
+	sessions, _ := c.userSessions.Get(email)
+	c.userSessions.Delete(email)
+	c.userMu.Unlock()
+
+	for sessionID := range sessions {
+		c.entries.Delete(sessionID)
+		c.lastKnown.Delete(sessionID)
+	}
+	return len(sessions)
 }
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
+	"github.com/dracondev/go-templ-htmx-ex/libs/cachex"
+)
+
+func TestSessionCacheInvalidation(t *testing.T) {
+	cache := NewSessionCache()
+	alice := layouts.UserInfo{LoggedIn: true, Email: "alice@example.com"}
+	bob := layouts.UserInfo{LoggedIn: true, Email: "bob@example.com"}
+
+	cache.Set("alice-laptop", alice)
+	cache.Set("alice-phone", alice)
+	cache.Set("bob-laptop", bob)
+
+	t.Run("delete_single_session", func(t *testing.T) {
+		cache.Delete("alice-laptop")
+
+		if _, found := cache.Get("alice-laptop"); found {
+			t.Error("Expected deleted session to be evicted")
+		}
+		if _, found := cache.Get("alice-phone"); !found {
+			t.Error("Expected other sessions of the user to remain")
+		}
+	})
+
+	t.Run("delete_all_user_sessions", func(t *testing.T) {
+		if removed := cache.DeleteUser("alice@example.com"); removed != 1 {
+			t.Errorf("Expected 1 remaining session evicted, got %d", removed)
+		}
+
+		if _, found := cache.Get("alice-phone"); found {
+			t.Error("Expected all of the user's sessions to be evicted")
+		}
+		if _, found := cache.Get("bob-laptop"); !found {
+			t.Error("Expected other users' sessions to remain")
+		}
+	})
+}
+
+func TestSessionCacheSharedBackend(t *testing.T) {
+	// Two replicas sharing one backend, as with Redis
+	entries := cachex.New[cachedSession](sessionCacheTTL)
+	rejected := cachex.New[struct{}](rejectedSessionTTL)
+	lastKnown := cachex.New[cachedSession](SessionGracePeriod)
+	revokedUsers := cachex.New[time.Time](revokedUserTTL())
+	replicaA := newSessionCache(entries, rejected, lastKnown, revokedUsers)
+	replicaB := newSessionCache(entries, rejected, lastKnown, revokedUsers)
+
+	alice := layouts.UserInfo{LoggedIn: true, Email: "alice@example.com"}
+
+	t.Run("delete_reaches_other_replica", func(t *testing.T) {
+		replicaB.Set("alice-laptop", alice)
+		replicaA.Delete("alice-laptop")
+
+		if _, found := replicaB.Get("alice-laptop"); found {
+			t.Error("Expected a revoke on replica A to evict the session on replica B")
+		}
+	})
+
+	t.Run("delete_user_reaches_other_replica", func(t *testing.T) {
+		replicaB.Set("alice-phone", alice)
+
+		// Replica A never cached this session but the revocation marker still applies
+		replicaA.DeleteUser("alice@example.com")
+
+		if _, found := replicaB.Get("alice-phone"); found {
+			t.Error("Expected log-out-everywhere on replica A to evict sessions cached by replica B")
+		}
+		if _, found := replicaB.GetStale("alice-phone"); found {
+			t.Error("Expected the grace cache to honour the revocation too")
+		}
+	})
+
+	t.Run("sessions_after_revocation_are_cached", func(t *testing.T) {
+		time.Sleep(time.Millisecond)
+		replicaB.Set("alice-new-login", alice)
+
+		if _, found := replicaA.Get("alice-new-login"); !found {
+			t.Error("Expected a session validated after the revocation to be served")
+		}
+	})
+}
+
+func TestSessionCacheBounded(t *testing.T) {
+	previous := SessionCacheMaxEntries
+	SessionCacheMaxEntries = 2
+	t.Cleanup(func() { SessionCacheMaxEntries = previous })
+
+	cache := NewSessionCache()
+	for _, id := range []string{"session-1", "session-2", "session-3"} {
+		cache.Set(id, layouts.UserInfo{LoggedIn: true, Email: id + "@example.com"})
+	}
+
+	if _, found := cache.Get("session-1"); found {
+		t.Error("Expected the least recently used session to be evicted")
+	}
+
+	stats, ok := cache.Stats()
+	if !ok {
+		t.Fatal("Expected stats for the in-process cache")
+	}
+	if stats.Size != 2 || stats.Evictions != 1 {
+		t.Errorf("Expected size 2 with 1 eviction, got %+v", stats)
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
+	"github.com/gorilla/mux"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	var renderedToken string
+	handler := CSRFMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		renderedToken = layouts.CSRFToken(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+	sessionCookie := &http.Cookie{Name: "session_id", Value: "csrf-session-123"}
+	token := CSRFToken(sessionCookie.Value)
+
+	t.Run("get_exposes_token", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/settings", nil)
+		req.AddCookie(sessionCookie)
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected 200 for GET, got %d", rr.Code)
+		}
+		if renderedToken != token {
+			t.Errorf("Expected token %q in context, got %q", token, renderedToken)
+		}
+	})
+
+	t.Run("post_without_token_forbidden", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/settings/update", nil)
+		req.AddCookie(sessionCookie)
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 without token, got %d", rr.Code)
+		}
+	})
+
+	t.Run("post_with_token_for_other_session_forbidden", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/settings/update", nil)
+		req.AddCookie(sessionCookie)
+		req.Header.Set(CSRFHeader, CSRFToken("someone-elses-session"))
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 for foreign token, got %d", rr.Code)
+		}
+	})
+
+	t.Run("post_with_header_allowed", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/payment/checkout", nil)
+		req.AddCookie(sessionCookie)
+		req.Header.Set(CSRFHeader, token)
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected 200 with header token, got %d", rr.Code)
+		}
+	})
+
+	t.Run("post_with_form_field_allowed", func(t *testing.T) {
+		form := url.Values{CSRFFormField: {token}}
+		req := httptest.NewRequest("POST", "/settings/billing", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(sessionCookie)
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected 200 with form token, got %d", rr.Code)
+		}
+	})
+
+	t.Run("post_without_session_allowed", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/auth/logout", nil)
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected 200 without session cookie, got %d", rr.Code)
+		}
+	})
+
+	t.Run("exempt_route_allowed", func(t *testing.T) {
+		RegisterCSRFExempt("/api/auth/set-session")
+
+		router := mux.NewRouter()
+		router.Handle("/api/auth/set-session", handler)
+		req := httptest.NewRequest("POST", "/api/auth/set-session", nil)
+		req.AddCookie(sessionCookie)
+		rr := httptest.NewRecorder()
+
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Errorf("Expected 200 for exempt route, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/repositories"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
+	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
+	"github.com/DraconDev/go-templ-htmx-ex/templates/pages"
+)
+
+// EntitlementLookup returns what a logged-in user's subscriptions grant.
+// Without a database nobody holds a paid plan; main replaces it with
+// LocalEntitlementLookup when a database is available.
+var EntitlementLookup = func(r *http.Request, userInfo layouts.UserInfo) (*services.Entitlements, error) {
+	return &services.Entitlements{Plan: models.PlanFree}, nil
+}
+
+// RequirePlan middleware that only lets users on the plan (or a higher one) through.
+// It relies on AuthMiddleware having placed the user in the request context.
+func RequirePlan(plan string) func(http.Handler) http.Handler {
+	return requireEntitlements(fmt.Sprintf("The %s plan", titleCase(plan)), map[string]interface{}{"required_plan": plan},
+		func(e *services.Entitlements) bool { return e.HasPlan(plan) })
+}
+
+// RequireEntitlement middleware that only lets users whose plan grants the feature through.
+// It relies on AuthMiddleware having placed the user in the request context.
+func RequireEntitlement(feature string) func(http.Handler) http.Handler {
+	return requireEntitlements(titleCase(feature), map[string]interface{}{"required_entitlement": feature},
+		func(e *services.Entitlements) bool { return e.Has(feature) })
+}
+
+// requireEntitlements answers 402 with an upgrade prompt when allowed is false
+func requireEntitlements(requirement string, details map[string]interface{}, allowed func(*services.Entitlements) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userInfo := GetUserFromContext(r)
+			isAPI := hasPrefix(r.URL.Path, "/api/")
+
+			if !userInfo.LoggedIn {
+				if isAPI {
+					writeJSONError(w, http.StatusUnauthorized, "Authentication required")
+					return
+				}
+				http.Redirect(w, r, loginRedirectURL(r), http.StatusFound)
+				return
+			}
+
+			entitlements, err := EntitlementLookup(r, userInfo)
+			if err != nil {
+				// Never grant on error, but don't ask a paying user to upgrade either
+				fmt.Printf("🔐 MIDDLEWARE: Could not load entitlements for %s: %v\n", userInfo.Email, err)
+				if isAPI {
+					writeJSONError(w, http.StatusServiceUnavailable, "Subscription status is temporarily unavailable")
+					return
+				}
+				http.Error(w, "Subscription status is temporarily unavailable. Please try again shortly.", http.StatusServiceUnavailable)
+				return
+			}
+
+			if !allowed(entitlements) {
+				fmt.Printf("🔐 MIDDLEWARE: %s not available on %s's %s plan\n", requirement, userInfo.Email, entitlements.Plan)
+				if isAPI {
+					writeUpgradeRequired(w, requirement, entitlements.Plan, details)
+					return
+				}
+				w.Header().Set("Content-Type", "text/html")
+				w.WriteHeader(http.StatusPaymentRequired)
+				if err := pages.UpgradeRequired(userInfo, requirement).Render(r.Context(), w); err != nil {
+					fmt.Printf("🔐 MIDDLEWARE: Failed to render upgrade prompt: %v\n", err)
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// LocalEntitlementLookup returns an EntitlementLookup backed by the local subscriptions table
+func LocalEntitlementLookup(userRepo *repositories.UserRepository, entitlements *services.EntitlementService) func(*http.Request, layouts.UserInfo) (*services.Entitlements, error) {
+	return func(r *http.Request, userInfo layouts.UserInfo) (*services.Entitlements, error) {
+		user, err := userRepo.GetUserByEmail(r.Context(), userInfo.Email)
+		if err != nil {
+			return nil, err
+		}
+		return entitlements.Entitlements(r.Context(), user.ID)
+	}
+}
+
+func writeUpgradeRequired(w http.ResponseWriter, requirement, currentPlan string, details map[string]interface{}) {
+	resp := map[string]interface{}{
+		"error":        fmt.Sprintf("%s requires an upgrade", requirement),
+		"current_plan": currentPlan,
+		"upgrade_url":  "/pricing",
+	}
+	for k, v := range details {
+		resp[k] = v
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusPaymentRequired)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		fmt.Printf("🔐 MIDDLEWARE: Failed to encode error response: %v\n", err)
+	}
+}
+
+// titleCase turns identifiers like "advanced_analytics" into "Advanced Analytics"
+func titleCase(s string) string {
+	words := strings.Fields(strings.ReplaceAll(s, "_", " "))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
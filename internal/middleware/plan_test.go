@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
+	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
+)
+
+func TestRequirePlan(t *testing.T) {
+	originalLookup := EntitlementLookup
+	defer func() { EntitlementLookup = originalLookup }()
+
+	plans := map[string]string{
+		"pro@example.com":  models.PlanPro,
+		"free@example.com": models.PlanFree,
+	}
+	EntitlementLookup = func(r *http.Request, userInfo layouts.UserInfo) (*services.Entitlements, error) {
+		if userInfo.Email == "broken@example.com" {
+			return nil, errors.New("database unavailable")
+		}
+		plan := plans[userInfo.Email]
+		if plan == models.PlanPro {
+			return &services.Entitlements{Plan: plan, Features: []string{models.FeatureAdvancedAnalytics}}, nil
+		}
+		return &services.Entitlements{Plan: plan}, nil
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	byPlan := AuthMiddleware(RequirePlan(models.PlanPro)(handler))
+	byFeature := AuthMiddleware(RequireEntitlement(models.FeatureAdvancedAnalytics)(handler))
+
+	serve := func(h http.Handler, path, sessionID, email string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if email != "" {
+			req.AddCookie(withCachedSession(t, sessionID, layouts.UserInfo{LoggedIn: true, Email: email}))
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("anonymous_api_unauthorized", func(t *testing.T) {
+		if rr := serve(byPlan, "/api/reports", "", ""); rr.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for anonymous user, got %d", rr.Code)
+		}
+	})
+
+	t.Run("anonymous_page_redirects_to_login", func(t *testing.T) {
+		rr := serve(byPlan, "/reports", "", "")
+		if rr.Code != http.StatusFound || !strings.HasPrefix(rr.Header().Get("Location"), "/login") {
+			t.Errorf("Expected redirect to login, got %d %s", rr.Code, rr.Header().Get("Location"))
+		}
+	})
+
+	t.Run("pro_user_allowed", func(t *testing.T) {
+		if rr := serve(byPlan, "/api/reports", "pro-session", "pro@example.com"); rr.Code != http.StatusOK {
+			t.Errorf("Expected 200 for pro user, got %d", rr.Code)
+		}
+		if rr := serve(byFeature, "/reports", "pro-session", "pro@example.com"); rr.Code != http.StatusOK {
+			t.Errorf("Expected 200 for pro user with the feature, got %d", rr.Code)
+		}
+	})
+
+	t.Run("free_user_api_payment_required", func(t *testing.T) {
+		rr := serve(byFeature, "/api/reports", "free-session", "free@example.com")
+		if rr.Code != http.StatusPaymentRequired {
+			t.Fatalf("Expected 402 for free user, got %d", rr.Code)
+		}
+
+		var body map[string]interface{}
+		if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+			t.Fatalf("Expected JSON body: %v", err)
+		}
+		if body["required_entitlement"] != models.FeatureAdvancedAnalytics || body["upgrade_url"] != "/pricing" {
+			t.Errorf("Unexpected 402 body: %v", body)
+		}
+	})
+
+	t.Run("free_user_page_shows_upgrade_prompt", func(t *testing.T) {
+		rr := serve(byPlan, "/reports", "free-session", "free@example.com")
+		if rr.Code != http.StatusPaymentRequired {
+			t.Errorf("Expected 402 for free user, got %d", rr.Code)
+		}
+		if !strings.Contains(rr.Body.String(), "/pricing") {
+			t.Error("Expected the upgrade prompt to link to pricing")
+		}
+	})
+
+	t.Run("lookup_failure_is_not_an_upgrade_prompt", func(t *testing.T) {
+		if rr := serve(byPlan, "/api/reports", "broken-session", "broken@example.com"); rr.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected 503 when entitlements can't be loaded, got %d", rr.Code)
+		}
+	})
+}
+
+func TestTitleCase(t *testing.T) {
+	if got := titleCase("advanced_analytics"); got != "Advanced Analytics" {
+		t.Errorf("Expected Advanced Analytics, got %s", got)
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+)
+
+// AccessPolicy describes who is allowed to reach a route
+type AccessPolicy string
+
+const (
+	// PolicyPublic routes are reachable by anyone
+	PolicyPublic AccessPolicy = "PUBLIC"
+	// PolicyAuthenticated routes require a valid session
+	PolicyAuthenticated AccessPolicy = "AUTHENTICATED"
+	// PolicyAdmin routes require a valid session; the route's permission is checked by RequirePermission
+	PolicyAdmin AccessPolicy = "ADMIN"
+	// PolicyAuthAPI routes handle session tokens themselves and must not be blocked
+	PolicyAuthAPI AccessPolicy = "AUTH_API"
+)
+
+// RequiresAuthentication reports whether the policy needs a logged-in user
+func (p AccessPolicy) RequiresAuthentication() bool {
+	return p == PolicyAuthenticated || p == PolicyAdmin
+}
+
+// routePolicies maps route patterns to their declared access policy.
+// It is populated by routes.SetupRoutes so the route table stays the single source.
+var (
+	routePoliciesMu sync.RWMutex
+	routePolicies   = map[string]AccessPolicy{}
+)
+
+// RegisterRoutePolicy records the access policy for a route pattern
+func RegisterRoutePolicy(pattern string, policy AccessPolicy) {
+	routePoliciesMu.Lock()
+	defer routePoliciesMu.Unlock()
+
+	routePolicies[pattern] = policy
+}
+
+// LookupRoutePolicy returns the access policy registered for a route pattern
+func LookupRoutePolicy(pattern string) (AccessPolicy, bool) {
+	routePoliciesMu.RLock()
+	defer routePoliciesMu.RUnlock()
+
+	policy, ok := routePolicies[pattern]
+	return policy, ok
+}
+
+// GetRoutePolicy returns the access policy that applies to a request.
+// The matched mux route template is preferred so parameterised routes resolve
+// correctly; unregistered routes require authentication so a route added
+// outside the route table is never public by accident.
+func GetRoutePolicy(r *http.Request) AccessPolicy {
+	for _, key := range routeKeys(r) {
+		if policy, ok := LookupRoutePolicy(key); ok {
+			return policy
+		}
+	}
+
+	fmt.Printf("⚠️ POLICY: No access policy registered for %s, requiring authentication\n", r.URL.Path)
+	return PolicyAuthenticated
+}
+
+// routeKeys returns the registry keys to try for a request: the matched mux
+// route template first, then the literal path
+func routeKeys(r *http.Request) []string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return []string{template, r.URL.Path}
+		}
+	}
+	return []string{r.URL.Path}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
+	"github.com/gorilla/mux"
+)
+
+// withCachedSession seeds the session cache so validateSession skips the auth service
+func withCachedSession(t *testing.T, sessionID string, userInfo layouts.UserInfo) *http.Cookie {
+	t.Helper()
+	InitializeSessionCache()
+	sessionCache.Set(sessionID, userInfo)
+	t.Cleanup(func() { sessionCache.Delete(sessionID) })
+	return &http.Cookie{Name: "session_id", Value: sessionID}
+}
+
+func TestGetRoutePolicy(t *testing.T) {
+	t.Run("unregistered_route_requires_authentication", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/not-registered", nil)
+		if policy := GetRoutePolicy(req); policy != PolicyAuthenticated {
+			t.Errorf("Expected %s, got %s", PolicyAuthenticated, policy)
+		}
+	})
+
+	t.Run("mux_route_template", func(t *testing.T) {
+		RegisterRoutePolicy("/api/items/{id}", PolicyAuthenticated)
+
+		var got AccessPolicy
+		router := mux.NewRouter()
+		router.HandleFunc("/api/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+			got = GetRoutePolicy(r)
+		})
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/items/42", nil))
+
+		if got != PolicyAuthenticated {
+			t.Errorf("Expected %s for templated route, got %s", PolicyAuthenticated, got)
+		}
+	})
+}
@@ -1,15 +1,26 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
+	"github.com/dracondev/go-templ-htmx-ex/libs/cachex"
+	"golang.org/x/sync/singleflight"
 )
 
 // Global session cache instance - will be initialized in service.go
 var sessionCache *SessionCache
 
+// validationGroup coalesces concurrent validations of the same session ID
+// (e.g. several HTMX requests from one page on a cold cache) into one auth call
+var validationGroup singleflight.Group
+
+// OnSessionValidated, when set, is called after the auth service confirms a
+// session (at most once per cache TTL), e.g. to update the device's last-seen time
+var OnSessionValidated func(r *http.Request, sessionID string)
+
 // InitializeSessionCache initializes the global session cache
 func InitializeSessionCache() {
 	if sessionCache == nil {
@@ -17,6 +28,19 @@ func InitializeSessionCache() {
 	}
 }
 
+// SessionCacheStats reports the session cache counters for /health
+func SessionCacheStats() (cachex.Stats, bool) {
+	if sessionCache == nil {
+		return cachex.Stats{}, false
+	}
+	return sessionCache.Stats()
+}
+
+// UseSessionCache replaces the session cache, e.g. with a Redis-backed one shared between replicas
+func UseSessionCache(cache *SessionCache) {
+	sessionCache = cache
+}
+
 // validateSession validates server session from session_id cookie with 15-second caching
 func validateSession(r *http.Request) layouts.UserInfo {
 	// Ensure cache is initialized
@@ -40,22 +64,82 @@ func validateSession(r *http.Request) layouts.UserInfo {
 
 	// Check cache first (15-second TTL)
 	if cached, found := sessionCache.Get(cookie.Value); found {
-		fmt.Printf("🔐 MIDDLEWARE: Cache hit for session %s\n", cookie.Value[:8]+"...")
+		fmt.Printf("🔐 MIDDLEWARE: Cache hit for session %s\n", sessionPrefix(cookie.Value))
 		return cached
 	}
 
-	fmt.Printf("🔐 MIDDLEWARE: Cache miss - calling auth service for session %s\n", cookie.Value[:8]+"...")
+	// Recently rejected session IDs are not sent to the auth service again
+	if sessionCache.IsRejected(cookie.Value) {
+		fmt.Printf("🔐 MIDDLEWARE: Session %s was recently rejected\n", sessionPrefix(cookie.Value))
+		return layouts.UserInfo{LoggedIn: false}
+	}
 
-	// Cache miss - call auth service to validate session
-	userInfo, err := validateSessionWithAuthService(cookie.Value)
+	fmt.Printf("🔐 MIDDLEWARE: Cache miss - calling auth service for session %s\n", sessionPrefix(cookie.Value))
+
+	// Cache miss - call auth service once for all concurrent requests with this session
+	result, err, shared := validationGroup.Do(cookie.Value, func() (interface{}, error) {
+		return validateAndCache(r, cookie.Value)
+	})
 	if err != nil {
 		fmt.Printf("🔐 MIDDLEWARE: Auth service validation failed: %v\n", err)
 		// Return unauthenticated instead of crashing
 		return layouts.UserInfo{LoggedIn: false}
 	}
+	if shared {
+		fmt.Printf("🔐 MIDDLEWARE: Shared in-flight validation for session %s\n", sessionPrefix(cookie.Value))
+	}
+
+	return result.(layouts.UserInfo)
+}
+
+// sessionPrefix shortens a session ID for logs; IDs from cookies can be any length
+func sessionPrefix(sessionID string) string {
+	return sessionID[:min(len(sessionID), 8)] + "..."
+}
+
+// validateAndCache performs the upstream validation and records the result.
+// It runs detached from the request's cancellation because its result is shared
+// with every request waiting on the same session.
+func validateAndCache(r *http.Request, sessionID string) (layouts.UserInfo, error) {
+	userInfo, err := validateSessionWithAuthService(context.WithoutCancel(r.Context()), sessionID)
+	if err != nil {
+		// Auth service unreachable: keep a recently validated session working
+		if stale, found := sessionCache.GetStale(sessionID); found {
+			fmt.Printf("🔐 MIDDLEWARE: ⚠️ Auth service unavailable, using last known session for %s\n", stale.Email)
+			return stale, nil
+		}
+		return userInfo, err
+	}
+
+	if !userInfo.LoggedIn {
+		sessionCache.MarkRejected(sessionID)
+		return userInfo, nil
+	}
 
 	// Cache result for 15 seconds
-	sessionCache.Set(cookie.Value, userInfo)
+	sessionCache.Set(sessionID, userInfo)
+
+	if OnSessionValidated != nil {
+		OnSessionValidated(r, sessionID)
+	}
+
+	return userInfo, nil
+}
 
-	return userInfo
+// InvalidateSession evicts a session from the validation cache so a revoked
+// session stops working immediately instead of after the cache TTL
+func InvalidateSession(sessionID string) {
+	if sessionCache == nil || sessionID == "" {
+		return
+	}
+	sessionCache.Delete(sessionID)
+}
+
+// InvalidateUserSessions evicts every cached session belonging to the user
+func InvalidateUserSessions(email string) {
+	if sessionCache == nil || email == "" {
+		return
+	}
+	removed := sessionCache.DeleteUser(email)
+	fmt.Printf("🔐 MIDDLEWARE: Evicted %d cached sessions for %s\n", removed, email)
 }
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/authms"
+)
+
+// withAuthService points session validation at a fake auth service
+func withAuthService(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	SetAuthClient(authms.New(server.URL).WithRetries(0, 0))
+	sessionCache = NewSessionCache()
+	t.Cleanup(func() {
+		server.Close()
+		SetAuthClient(nil)
+		sessionCache = nil
+	})
+}
+
+func sessionRequest(sessionID string) *http.Request {
+	req := httptest.NewRequest("GET", "/dashboard", nil)
+	req.AddCookie(&http.Cookie{Name: "session_id", Value: sessionID})
+	return req
+}
+
+func TestValidateSessionCoalescesConcurrentCalls(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	withAuthService(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		json.NewEncoder(w).Encode(authms.SessionResponse{
+			UserContext: authms.UserContext{UserID: "u1", Email: "alice@example.com"},
+		})
+	})
+
+	var wg sync.WaitGroup
+	results := make([]bool, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = validateSession(sessionRequest("session-abc-123")).LoggedIn
+		}(i)
+	}
+
+	// Let all requests join the in-flight call before the auth service answers
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected 1 auth service call, got %d", calls)
+	}
+	for i, loggedIn := range results {
+		if !loggedIn {
+			t.Errorf("Request %d: expected session to be valid", i)
+		}
+	}
+}
+
+func TestValidateSessionShortCookie(t *testing.T) {
+	var calls int32
+	withAuthService(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	// Shorter than the logged prefix: validated and rejected, not a panic
+	for i := 0; i < 2; i++ {
+		if validateSession(sessionRequest("abc")).LoggedIn {
+			t.Fatal("Expected a short session ID to be logged out")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected the short session ID to be validated once, got %d calls", calls)
+	}
+	if got := sessionPrefix("session-abc-123"); got != "session-..." {
+		t.Errorf("Expected an 8 character prefix, got %q", got)
+	}
+}
+
+func TestValidateSessionNegativeCache(t *testing.T) {
+	var calls int32
+	withAuthService(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	for i := 0; i < 5; i++ {
+		if validateSession(sessionRequest("bad-session-id")).LoggedIn {
+			t.Fatal("Expected rejected session to be logged out")
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected rejected session to be validated once, got %d calls", calls)
+	}
+}
+
+func TestValidateSessionDoesNotCacheOutages(t *testing.T) {
+	var calls int32
+	withAuthService(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	validateSession(sessionRequest("session-during-outage"))
+	validateSession(sessionRequest("session-during-outage"))
+
+	if calls != 2 {
+		t.Errorf("Expected outage results not to be cached, got %d calls", calls)
+	}
+	if sessionCache.IsRejected("session-during-outage") {
+		t.Error("Expected an unreachable auth service not to mark the session rejected")
+	}
+}
+
+func TestValidateSessionGraceWindow(t *testing.T) {
+	var down atomic.Bool
+	withAuthService(t, func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(authms.SessionResponse{
+			UserContext: authms.UserContext{UserID: "u1", Email: "alice@example.com"},
+		})
+	})
+
+	if !validateSession(sessionRequest("session-grace-123")).LoggedIn {
+		t.Fatal("Expected session to validate while the auth service is up")
+	}
+
+	// Outage after the short-lived cache entry expired
+	down.Store(true)
+	sessionCache.entries.Delete("session-grace-123")
+
+	userInfo := validateSession(sessionRequest("session-grace-123"))
+	if !userInfo.LoggedIn || userInfo.Email != "alice@example.com" {
+		t.Errorf("Expected last known session during outage, got %+v", userInfo)
+	}
+
+	// Revocation must also end the grace window
+	InvalidateSession("session-grace-123")
+	if validateSession(sessionRequest("session-grace-123")).LoggedIn {
+		t.Error("Expected a revoked session not to be served from the grace cache")
+	}
+
+	if validateSession(sessionRequest("never-validated-1")).LoggedIn {
+		t.Error("Expected unknown sessions to be logged out during an outage")
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
+)
+
+// TrialBannerMiddleware places the signed-in user's trial end in the request
+// context so Layout can count it down. Only full page loads are looked up;
+// API calls, static files and HTMX fragments never render the layout.
+func TrialBannerMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userInfo := GetUserFromContext(r)
+		if !userInfo.LoggedIn || r.Method != http.MethodGet || r.Header.Get("HX-Request") == "true" ||
+			hasPrefix(r.URL.Path, "/api/") || hasPrefix(r.URL.Path, "/static/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		entitlements, err := EntitlementLookup(r, userInfo)
+		if err != nil {
+			// The banner is a courtesy; render the page without it
+			fmt.Printf("⚠️ MIDDLEWARE: Could not load trial status for %s: %v\n", userInfo.Email, err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if sub := entitlements.Subscription; sub != nil && sub.Trialing(time.Now()) {
+			r = r.WithContext(layouts.WithTrialEnd(r.Context(), sub.TrialEnd))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/services"
+	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
+)
+
+func TestTrialBannerMiddleware(t *testing.T) {
+	originalLookup := EntitlementLookup
+	defer func() { EntitlementLookup = originalLookup }()
+
+	trialEnd := time.Now().Add(72 * time.Hour)
+	lookups := 0
+	EntitlementLookup = func(r *http.Request, userInfo layouts.UserInfo) (*services.Entitlements, error) {
+		lookups++
+		sub := &models.Subscription{Status: models.SubscriptionStatusActive}
+		if userInfo.Email == "trial@example.com" {
+			sub = &models.Subscription{Status: models.SubscriptionStatusTrialing, TrialEnd: trialEnd}
+		}
+		return &services.Entitlements{Plan: models.PlanPro, Subscription: sub}, nil
+	}
+
+	var gotEnd time.Time
+	var gotOK bool
+	handler := AuthMiddleware(TrialBannerMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEnd, gotOK = layouts.TrialEnd(r.Context())
+	})))
+
+	serve := func(path, sessionID, email string, htmx bool) {
+		gotEnd, gotOK = time.Time{}, false
+		req := httptest.NewRequest("GET", path, nil)
+		if email != "" {
+			req.AddCookie(withCachedSession(t, sessionID, layouts.UserInfo{LoggedIn: true, Email: email}))
+		}
+		if htmx {
+			req.Header.Set("HX-Request", "true")
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("trialing_user_gets_trial_end", func(t *testing.T) {
+		serve("/dashboard", "trial-session-1", "trial@example.com", false)
+		if !gotOK || !gotEnd.Equal(trialEnd) {
+			t.Errorf("Expected trial end %v, got %v (ok=%v)", trialEnd, gotEnd, gotOK)
+		}
+	})
+
+	t.Run("active_user_has_no_banner", func(t *testing.T) {
+		serve("/dashboard", "active-session-1", "active@example.com", false)
+		if gotOK {
+			t.Error("Expected no trial end for an active subscription")
+		}
+	})
+
+	t.Run("fragments_and_api_skip_lookup", func(t *testing.T) {
+		lookups = 0
+		serve("/account/subscription", "trial-session-2", "trial@example.com", true)
+		serve("/api/user", "trial-session-3", "trial@example.com", false)
+		serve("/", "", "", false)
+		if lookups != 0 || gotOK {
+			t.Errorf("Expected no entitlement lookups, got %d", lookups)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// PaymentEvent is a webhook event received from the payment service
+type PaymentEvent struct {
+	ID             string     `json:"id"`
+	EventID        string     `json:"event_id"` // payment service event ID, unique
+	EventType      string     `json:"event_type"`
+	UserID         string     `json:"user_id"` // payment service user ID (currently the email)
+	SubscriptionID string     `json:"subscription_id"`
+	Status         string     `json:"status"`
+	ReceivedAt     time.Time  `json:"received_at"`
+	ProcessedAt    *time.Time `json:"processed_at,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Built-in role names seeded by 003_roles_permissions.sql
+const (
+	RoleAdmin          = "admin"
+	RoleSupport        = "support"
+	RoleBillingManager = "billing-manager"
+)
+
+// Permission names checked by RequirePermission
+const (
+	PermissionAdminAccess   = "admin:access"
+	PermissionUsersRead     = "users:read"
+	PermissionUsersWrite    = "users:write"
+	PermissionAnalyticsRead = "analytics:read"
+	PermissionSettingsRead  = "settings:read"
+	PermissionLogsRead      = "logs:read"
+	PermissionBillingRead   = "billing:read"
+	PermissionBillingWrite  = "billing:write"
+)
+
+// Role represents a named group of permissions
+type Role struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// UserSession represents a login on one device
+type UserSession struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	SessionID  string    `json:"-"` // auth service session, never exposed
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"` // true for the session making the request
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Purchase statuses
+const (
+	PurchaseStatusPending   = "pending"
+	PurchaseStatusCompleted = "completed"
+)
+
+// MaxCartQuantity caps the quantity of a single cart line
+const MaxCartQuantity = 99
+
+// CartItem is a line stored in a session's cart
+type CartItem struct {
+	PriceID  string `json:"price_id"`
+	Quantity int    `json:"quantity"`
+}
+
+// CartLine is a cart item priced from the catalog
+type CartLine struct {
+	ItemName  string `json:"item_name"` // catalog item key
+	Label     string `json:"label"`
+	PriceID   string `json:"price_id"`
+	Quantity  int    `json:"quantity"`
+	Amount    int64  `json:"amount"` // unit amount in minor units
+	Currency  string `json:"currency"`
+	Subtotal  int64  `json:"subtotal"`
+	Available bool   `json:"available"` // false once the item left the catalog
+}
+
+// Cart is a session's cart as shown on the cart page
+type Cart struct {
+	Lines    []CartLine `json:"lines"`
+	Total    int64      `json:"total"`
+	Currency string     `json:"currency"`
+	Count    int        `json:"count"` // total quantity of available lines
+}
+
+// Purchase is one line of a one-off order
+type Purchase struct {
+	ID                string     `json:"id"`
+	UserID            string     `json:"user_id"`
+	CheckoutSessionID string     `json:"checkout_session_id"`
+	PriceID           string     `json:"price_id"`
+	ItemName          string     `json:"item_name"`
+	Quantity          int        `json:"quantity"`
+	UnitAmount        int64      `json:"unit_amount"`
+	Currency          string     `json:"currency"`
+	Status            string     `json:"status"`
+	CreatedAt         time.Time  `json:"created_at"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+}
+
+// Total is the amount paid for the line in minor units
+func (p Purchase) Total() int64 {
+	return p.UnitAmount * int64(p.Quantity)
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// Plans a user can be on
+const (
+	PlanFree = "free"
+	PlanPro  = "pro"
+)
+
+// Features checked with the entitlement service; the catalog lists which
+// plans grant them
+const (
+	FeatureUnlimitedProjects = "unlimited_projects"
+	FeatureAdvancedAnalytics = "advanced_analytics"
+	FeaturePrioritySupport   = "priority_support"
+	FeatureAPIAccess         = "api_access"
+)
+
+// Subscription statuses reported by the payment service
+const (
+	SubscriptionStatusActive   = "active"
+	SubscriptionStatusTrialing = "trialing"
+	SubscriptionStatusPastDue  = "past_due"
+	SubscriptionStatusCanceled = "canceled"
+)
+
+// Subscription is the local record of a subscription at the payment service
+type Subscription struct {
+	ID               string    `json:"id"`
+	UserID           string    `json:"user_id"`         // local users.id
+	SubscriptionID   string    `json:"subscription_id"` // payment service subscription ID
+	ProductID        string    `json:"product_id"`
+	PriceID          string    `json:"price_id"`
+	Status           string    `json:"status"`
+	CurrentPeriodEnd time.Time `json:"current_period_end"`
+	// CancelAtPeriodEnd is set while a cancellation is pending; access lasts until CurrentPeriodEnd
+	CancelAtPeriodEnd bool `json:"cancel_at_period_end"`
+	// TrialEnd is when a free trial converts to a paid subscription; zero without a trial
+	TrialEnd time.Time `json:"trial_end,omitzero"`
+	// StateAt is the creation time of the webhook event carrying this state; zero
+	// for states fetched from the payment service. Older events are not stored.
+	StateAt   time.Time `json:"state_at,omitzero"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TrialReminder is a trial ending soon whose reminder email is due
+type TrialReminder struct {
+	SubscriptionID string    `json:"subscription_id"`
+	UserID         string    `json:"user_id"`
+	ProductID      string    `json:"product_id"`
+	Email          string    `json:"email"`
+	Name           string    `json:"name"`
+	TrialEnd       time.Time `json:"trial_end"`
+}
+
+// ProjectSubscription is a subscription in any project sharing the payment service
+type ProjectSubscription struct {
+	ProjectID        string    `json:"project_id"`
+	SubscriptionID   string    `json:"subscription_id"`
+	ProductID        string    `json:"product_id"`
+	Status           string    `json:"status"`
+	CurrentPeriodEnd time.Time `json:"current_period_end"`
+	Current          bool      `json:"current"` // true for this app's own project
+}
+
+// GrantsAccess reports whether the subscription currently unlocks its plan
+func (s Subscription) GrantsAccess() bool {
+	return s.Status == SubscriptionStatusActive || s.Status == SubscriptionStatusTrialing
+}
+
+// Trialing reports whether the subscription is in a free trial that hasn't ended
+func (s Subscription) Trialing(now time.Time) bool {
+	return s.Status == SubscriptionStatusTrialing && s.TrialEnd.After(now)
+}
+
+// TrialDaysLeft is the number of started days left in the trial, 0 when not trialing
+func TrialDaysLeft(trialEnd, now time.Time) int {
+	if !trialEnd.After(now) {
+		return 0
+	}
+	return int((trialEnd.Sub(now) + 24*time.Hour - 1) / (24 * time.Hour))
+}
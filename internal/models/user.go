@@ -10,6 +10,8 @@ import (
 // Database errors
 var (
 	ErrDatabaseNotConnected = errors.New("database not connected")
+	// ErrStaleSubscription is returned when storing a subscription state older than the stored one
+	ErrStaleSubscription = errors.New("subscription state is older than the stored one")
 )
 
 // UserSessionContext represents the user context from Auth MS (session/create and session/refresh)
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	dbSqlc "github.com/DraconDev/go-templ-htmx-ex/database/sqlc"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+)
+
+// CartRepository handles the session-backed shopping carts. Carts are stored
+// under a hash of the session ID, never the session ID itself.
+type CartRepository struct {
+	queries *dbSqlc.Queries
+}
+
+// NewCartRepository creates a new cart repository
+func NewCartRepository(queries *dbSqlc.Queries) *CartRepository {
+	return &CartRepository{
+		queries: queries,
+	}
+}
+
+// AddItem adds quantity of a price to the cart, capped at models.MaxCartQuantity
+func (r *CartRepository) AddItem(ctx context.Context, sessionID, priceID string, quantity int) error {
+	if r.queries == nil {
+		return models.ErrDatabaseNotConnected
+	}
+
+	_, err := r.queries.AddCartItem(ctx, dbSqlc.AddCartItemParams{
+		SessionHash: cartKey(sessionID),
+		PriceID:     priceID,
+		Quantity:    int32(quantity),
+		MaxQuantity: models.MaxCartQuantity,
+	})
+	return err
+}
+
+// SetQuantity changes the quantity of a cart line
+func (r *CartRepository) SetQuantity(ctx context.Context, sessionID, priceID string, quantity int) error {
+	if r.queries == nil {
+		return models.ErrDatabaseNotConnected
+	}
+
+	return r.queries.SetCartItemQuantity(ctx, dbSqlc.SetCartItemQuantityParams{
+		SessionHash: cartKey(sessionID),
+		PriceID:     priceID,
+		Quantity:    int32(quantity),
+	})
+}
+
+// RemoveItem removes a line from the cart
+func (r *CartRepository) RemoveItem(ctx context.Context, sessionID, priceID string) error {
+	if r.queries == nil {
+		return models.ErrDatabaseNotConnected
+	}
+
+	return r.queries.RemoveCartItem(ctx, dbSqlc.RemoveCartItemParams{
+		SessionHash: cartKey(sessionID),
+		PriceID:     priceID,
+	})
+}
+
+// GetItems retrieves the cart lines in the order they were added
+func (r *CartRepository) GetItems(ctx context.Context, sessionID string) ([]models.CartItem, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	dbItems, err := r.queries.GetCartItems(ctx, cartKey(sessionID))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.CartItem, len(dbItems))
+	for i, item := range dbItems {
+		items[i] = models.CartItem{
+			PriceID:  item.PriceID,
+			Quantity: int(item.Quantity),
+		}
+	}
+	return items, nil
+}
+
+// Clear empties the cart
+func (r *CartRepository) Clear(ctx context.Context, sessionID string) error {
+	if r.queries == nil {
+		return models.ErrDatabaseNotConnected
+	}
+
+	return r.queries.ClearCart(ctx, cartKey(sessionID))
+}
+
+// cartKey is the stored key of a session's cart: the hex SHA-256 of the session ID
+func cartKey(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	dbSqlc "github.com/DraconDev/go-templ-htmx-ex/database/sqlc"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+)
+
+// PaymentEventRepository stores webhook events received from the payment service
+type PaymentEventRepository struct {
+	queries *dbSqlc.Queries
+}
+
+// NewPaymentEventRepository creates a new payment event repository
+func NewPaymentEventRepository(queries *dbSqlc.Queries) *PaymentEventRepository {
+	return &PaymentEventRepository{
+		queries: queries,
+	}
+}
+
+// RecordEvent stores an event. It returns false without error when the event
+// was already recorded, so redelivered webhooks can be acknowledged and skipped.
+func (r *PaymentEventRepository) RecordEvent(ctx context.Context, event models.PaymentEvent, payload []byte) (bool, error) {
+	if r.queries == nil {
+		return false, models.ErrDatabaseNotConnected
+	}
+
+	_, err := r.queries.CreatePaymentEvent(ctx, dbSqlc.CreatePaymentEventParams{
+		EventID:        event.EventID,
+		EventType:      event.EventType,
+		UserID:         sql.NullString{String: event.UserID, Valid: event.UserID != ""},
+		SubscriptionID: sql.NullString{String: event.SubscriptionID, Valid: event.SubscriptionID != ""},
+		Status:         sql.NullString{String: event.Status, Valid: event.Status != ""},
+		Payload:        payload,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// ON CONFLICT DO NOTHING returns no row for a duplicate
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// IsProcessed reports whether an event was recorded and fully handled
+func (r *PaymentEventRepository) IsProcessed(ctx context.Context, eventID string) (bool, error) {
+	if r.queries == nil {
+		return false, models.ErrDatabaseNotConnected
+	}
+
+	dbEvent, err := r.queries.GetPaymentEvent(ctx, eventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return dbEvent.ProcessedAt.Valid, nil
+}
+
+// ClaimEvent takes an unhandled event for the calling delivery. It returns false
+// when the event was already handled or another delivery holds an unexpired claim.
+func (r *PaymentEventRepository) ClaimEvent(ctx context.Context, eventID string, lease time.Duration) (bool, error) {
+	if r.queries == nil {
+		return false, models.ErrDatabaseNotConnected
+	}
+
+	rows, err := r.queries.ClaimPaymentEvent(ctx, dbSqlc.ClaimPaymentEventParams{
+		EventID: eventID,
+		Lease:   fmt.Sprintf("%d seconds", int(lease.Seconds())),
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// ReleaseEvent gives up a claim after handling failed, so a redelivery can retry
+func (r *PaymentEventRepository) ReleaseEvent(ctx context.Context, eventID string) error {
+	if r.queries == nil {
+		return models.ErrDatabaseNotConnected
+	}
+
+	return r.queries.ReleasePaymentEvent(ctx, eventID)
+}
+
+// MarkProcessed records that an event was handled
+func (r *PaymentEventRepository) MarkProcessed(ctx context.Context, eventID string) error {
+	if r.queries == nil {
+		return models.ErrDatabaseNotConnected
+	}
+
+	return r.queries.MarkPaymentEventProcessed(ctx, eventID)
+}
+
+// GetUserEvents retrieves a user's most recent payment events
+func (r *PaymentEventRepository) GetUserEvents(ctx context.Context, userID string, limit int) ([]models.PaymentEvent, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	dbEvents, err := r.queries.GetPaymentEventsByUser(ctx, dbSqlc.GetPaymentEventsByUserParams{
+		UserID: sql.NullString{String: userID, Valid: userID != ""},
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]models.PaymentEvent, len(dbEvents))
+	for i, e := range dbEvents {
+		events[i] = *toModelPaymentEvent(e)
+	}
+	return events, nil
+}
+
+func toModelPaymentEvent(e dbSqlc.PaymentEvent) *models.PaymentEvent {
+	event := &models.PaymentEvent{
+		ID:             e.ID.String(),
+		EventID:        e.EventID,
+		EventType:      e.EventType,
+		UserID:         e.UserID.String,
+		SubscriptionID: e.SubscriptionID.String,
+		Status:         e.Status.String,
+		ReceivedAt:     e.ReceivedAt.Time,
+	}
+	if e.ProcessedAt.Valid {
+		processedAt := e.ProcessedAt.Time
+		event.ProcessedAt = &processedAt
+	}
+	return event
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+
+	dbSqlc "github.com/DraconDev/go-templ-htmx-ex/database/sqlc"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/google/uuid"
+)
+
+// PurchaseRepository handles one-off purchases
+type PurchaseRepository struct {
+	queries *dbSqlc.Queries
+}
+
+// NewPurchaseRepository creates a new purchase repository
+func NewPurchaseRepository(queries *dbSqlc.Queries) *PurchaseRepository {
+	return &PurchaseRepository{
+		queries: queries,
+	}
+}
+
+// CreatePending records the lines of a checkout session as pending purchases
+// in one statement: either every line is stored or none is. Lines already
+// recorded for the session are left unchanged.
+func (r *PurchaseRepository) CreatePending(ctx context.Context, userID, checkoutSessionID string, lines []models.Purchase) error {
+	if r.queries == nil {
+		return models.ErrDatabaseNotConnected
+	}
+
+	uuidID, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	params := dbSqlc.CreatePurchasesParams{
+		UserID:            uuidID,
+		CheckoutSessionID: checkoutSessionID,
+	}
+	for _, line := range lines {
+		params.PriceIds = append(params.PriceIds, line.PriceID)
+		params.ItemNames = append(params.ItemNames, line.ItemName)
+		params.Quantities = append(params.Quantities, int32(line.Quantity))
+		params.UnitAmounts = append(params.UnitAmounts, line.UnitAmount)
+		params.Currencies = append(params.Currencies, line.Currency)
+	}
+	return r.queries.CreatePurchases(ctx, params)
+}
+
+// CompleteCheckout marks the pending lines of a checkout session completed and returns them
+func (r *PurchaseRepository) CompleteCheckout(ctx context.Context, checkoutSessionID string) ([]models.Purchase, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	dbPurchases, err := r.queries.CompletePurchases(ctx, checkoutSessionID)
+	if err != nil {
+		return nil, err
+	}
+	return toModelPurchases(dbPurchases), nil
+}
+
+// GetUserPurchases retrieves a user's completed purchases, most recent first
+func (r *PurchaseRepository) GetUserPurchases(ctx context.Context, userID string, limit int) ([]models.Purchase, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	uuidID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dbPurchases, err := r.queries.GetCompletedPurchasesByUser(ctx, dbSqlc.GetCompletedPurchasesByUserParams{
+		UserID: uuidID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toModelPurchases(dbPurchases), nil
+}
+
+func toModelPurchases(dbPurchases []dbSqlc.Purchase) []models.Purchase {
+	purchases := make([]models.Purchase, len(dbPurchases))
+	for i, p := range dbPurchases {
+		purchases[i] = models.Purchase{
+			ID:                p.ID.String(),
+			UserID:            p.UserID.String(),
+			CheckoutSessionID: p.CheckoutSessionID,
+			PriceID:           p.PriceID,
+			ItemName:          p.ItemName,
+			Quantity:          int(p.Quantity),
+			UnitAmount:        p.UnitAmount,
+			Currency:          p.Currency,
+			Status:            p.Status,
+			CreatedAt:         p.CreatedAt.Time,
+		}
+		if p.CompletedAt.Valid {
+			completedAt := p.CompletedAt.Time
+			purchases[i].CompletedAt = &completedAt
+		}
+	}
+	return purchases
+}
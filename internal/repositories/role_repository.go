@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+
+	dbSqlc "github.com/DraconDev/go-templ-htmx-ex/database/sqlc"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/google/uuid"
+)
+
+// RoleRepository handles role and permission data access operations
+type RoleRepository struct {
+	queries *dbSqlc.Queries
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(queries *dbSqlc.Queries) *RoleRepository {
+	return &RoleRepository{
+		queries: queries,
+	}
+}
+
+// GetAllRoles retrieves every defined role
+func (r *RoleRepository) GetAllRoles(ctx context.Context) ([]models.Role, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	dbRoles, err := r.queries.GetAllRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toModelRoles(dbRoles), nil
+}
+
+// GetUserRoles retrieves the roles assigned to a user
+func (r *RoleRepository) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]models.Role, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	dbRoles, err := r.queries.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toModelRoles(dbRoles), nil
+}
+
+// GetUserPermissionsByEmail retrieves the distinct permission names granted to a user through their roles
+func (r *RoleRepository) GetUserPermissionsByEmail(ctx context.Context, email string) ([]string, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	return r.queries.GetUserPermissionsByEmail(ctx, email)
+}
+
+// AssignRole grants a named role to a user
+func (r *RoleRepository) AssignRole(ctx context.Context, userID uuid.UUID, roleName string) error {
+	if r.queries == nil {
+		return models.ErrDatabaseNotConnected
+	}
+
+	return r.queries.AssignRoleToUser(ctx, dbSqlc.AssignRoleToUserParams{
+		UserID: userID,
+		Name:   roleName,
+	})
+}
+
+// RemoveRole revokes a named role from a user
+func (r *RoleRepository) RemoveRole(ctx context.Context, userID uuid.UUID, roleName string) error {
+	if r.queries == nil {
+		return models.ErrDatabaseNotConnected
+	}
+
+	return r.queries.RemoveRoleFromUser(ctx, dbSqlc.RemoveRoleFromUserParams{
+		UserID: userID,
+		Name:   roleName,
+	})
+}
+
+func toModelRoles(dbRoles []dbSqlc.Role) []models.Role {
+	roles := make([]models.Role, len(dbRoles))
+	for i, role := range dbRoles {
+		roles[i] = models.Role{
+			ID:          role.ID.String(),
+			Name:        role.Name,
+			Description: role.Description.String,
+			CreatedAt:   role.CreatedAt.Time,
+		}
+	}
+	return roles
+}
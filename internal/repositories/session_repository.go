@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	dbSqlc "github.com/DraconDev/go-templ-htmx-ex/database/sqlc"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/google/uuid"
+)
+
+// SessionRepository handles the per-device session records
+type SessionRepository struct {
+	queries *dbSqlc.Queries
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(queries *dbSqlc.Queries) *SessionRepository {
+	return &SessionRepository{
+		queries: queries,
+	}
+}
+
+// RecordSession stores a newly established session, or refreshes its last-seen time if already known
+func (r *SessionRepository) RecordSession(ctx context.Context, userID, sessionID, userAgent, ipAddress string) (*models.UserSession, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	uuidID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dbSession, err := r.queries.CreateUserSession(ctx, dbSqlc.CreateUserSessionParams{
+		UserID:    uuidID,
+		SessionID: sessionID,
+		UserAgent: sql.NullString{String: userAgent, Valid: userAgent != ""},
+		IpAddress: sql.NullString{String: ipAddress, Valid: ipAddress != ""},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toModelSession(dbSession), nil
+}
+
+// GetActiveSessions retrieves a user's sessions that have not been revoked, most recently used first
+func (r *SessionRepository) GetActiveSessions(ctx context.Context, userID string) ([]models.UserSession, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	uuidID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dbSessions, err := r.queries.GetActiveUserSessions(ctx, uuidID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]models.UserSession, len(dbSessions))
+	for i, s := range dbSessions {
+		sessions[i] = *toModelSession(s)
+	}
+	return sessions, nil
+}
+
+// GetSession retrieves one of the user's sessions by its record ID
+func (r *SessionRepository) GetSession(ctx context.Context, id, userID string) (*models.UserSession, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	sessionUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dbSession, err := r.queries.GetUserSession(ctx, dbSqlc.GetUserSessionParams{
+		ID:     sessionUUID,
+		UserID: userUUID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toModelSession(dbSession), nil
+}
+
+// TouchSession updates the last-seen time of an active session
+func (r *SessionRepository) TouchSession(ctx context.Context, sessionID string) error {
+	if r.queries == nil {
+		return models.ErrDatabaseNotConnected
+	}
+
+	return r.queries.TouchUserSession(ctx, sessionID)
+}
+
+// RevokeSession marks a session as revoked
+func (r *SessionRepository) RevokeSession(ctx context.Context, sessionID string) error {
+	if r.queries == nil {
+		return models.ErrDatabaseNotConnected
+	}
+
+	return r.queries.RevokeUserSession(ctx, sessionID)
+}
+
+// RevokeAllSessions marks every active session of a user as revoked
+func (r *SessionRepository) RevokeAllSessions(ctx context.Context, userID string) error {
+	if r.queries == nil {
+		return models.ErrDatabaseNotConnected
+	}
+
+	uuidID, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.RevokeAllUserSessions(ctx, uuidID)
+}
+
+func toModelSession(s dbSqlc.UserSession) *models.UserSession {
+	return &models.UserSession{
+		ID:         s.ID.String(),
+		UserID:     s.UserID.String(),
+		SessionID:  s.SessionID,
+		UserAgent:  s.UserAgent.String,
+		IPAddress:  s.IpAddress.String,
+		CreatedAt:  s.CreatedAt.Time,
+		LastSeenAt: s.LastSeenAt.Time,
+	}
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	dbSqlc "github.com/DraconDev/go-templ-htmx-ex/database/sqlc"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/google/uuid"
+)
+
+// SubscriptionRepository handles the local subscription projection
+type SubscriptionRepository struct {
+	queries *dbSqlc.Queries
+}
+
+// NewSubscriptionRepository creates a new subscription repository
+func NewSubscriptionRepository(queries *dbSqlc.Queries) *SubscriptionRepository {
+	return &SubscriptionRepository{
+		queries: queries,
+	}
+}
+
+// UpsertSubscription stores a subscription, updating it if already known.
+// An event older than the last stored one is skipped with ErrStaleSubscription.
+func (r *SubscriptionRepository) UpsertSubscription(ctx context.Context, sub models.Subscription) (*models.Subscription, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	userID, err := uuid.Parse(sub.UserID)
+	if err != nil {
+		return nil, err
+	}
+	dbSub, err := r.queries.UpsertSubscription(ctx, dbSqlc.UpsertSubscriptionParams{
+		UserID:             userID,
+		SubscriptionID:     sub.SubscriptionID,
+		ProductID:          sub.ProductID,
+		PriceID:            sql.NullString{String: sub.PriceID, Valid: sub.PriceID != ""},
+		Status:             sub.Status,
+		CurrentPeriodEnd:   sql.NullTime{Time: sub.CurrentPeriodEnd, Valid: !sub.CurrentPeriodEnd.IsZero()},
+		CancelAtPeriodEnd:  sub.CancelAtPeriodEnd,
+		TrialEnd:           sql.NullTime{Time: sub.TrialEnd, Valid: !sub.TrialEnd.IsZero()},
+		UpdatedFromEventAt: sql.NullTime{Time: sub.StateAt, Valid: !sub.StateAt.IsZero()},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrStaleSubscription
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toModelSubscription(dbSub), nil
+}
+
+// GetUserSubscriptions retrieves all of a user's subscriptions, most recently updated first
+func (r *SubscriptionRepository) GetUserSubscriptions(ctx context.Context, userID string) ([]models.Subscription, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	uuidID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dbSubs, err := r.queries.GetSubscriptionsByUser(ctx, uuidID)
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]models.Subscription, len(dbSubs))
+	for i, s := range dbSubs {
+		subs[i] = *toModelSubscription(s)
+	}
+	return subs, nil
+}
+
+// GetTrialsEndingBefore lists trials ending by the given time whose reminder is still due
+func (r *SubscriptionRepository) GetTrialsEndingBefore(ctx context.Context, before time.Time) ([]models.TrialReminder, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	rows, err := r.queries.GetTrialsEndingBefore(ctx, sql.NullTime{Time: before, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	reminders := make([]models.TrialReminder, len(rows))
+	for i, row := range rows {
+		reminders[i] = models.TrialReminder{
+			SubscriptionID: row.SubscriptionID,
+			UserID:         row.UserID.String(),
+			ProductID:      row.ProductID,
+			Email:          row.Email,
+			Name:           row.Name,
+			TrialEnd:       row.TrialEnd.Time,
+		}
+	}
+	return reminders, nil
+}
+
+// ClaimTrialReminder marks a trial's reminder as sent, reporting false when
+// another process already claimed it
+func (r *SubscriptionRepository) ClaimTrialReminder(ctx context.Context, subscriptionID string) (bool, error) {
+	if r.queries == nil {
+		return false, models.ErrDatabaseNotConnected
+	}
+
+	claimed, err := r.queries.ClaimTrialReminder(ctx, subscriptionID)
+	if err != nil {
+		return false, err
+	}
+	return claimed == 1, nil
+}
+
+// ReleaseTrialReminder makes a claimed reminder due again after sending it failed
+func (r *SubscriptionRepository) ReleaseTrialReminder(ctx context.Context, subscriptionID string) error {
+	if r.queries == nil {
+		return models.ErrDatabaseNotConnected
+	}
+	return r.queries.ReleaseTrialReminder(ctx, subscriptionID)
+}
+
+func toModelSubscription(s dbSqlc.Subscription) *models.Subscription {
+	return &models.Subscription{
+		ID:                s.ID.String(),
+		UserID:            s.UserID.String(),
+		SubscriptionID:    s.SubscriptionID,
+		ProductID:         s.ProductID,
+		PriceID:           s.PriceID.String,
+		Status:            s.Status,
+		CurrentPeriodEnd:  s.CurrentPeriodEnd.Time,
+		UpdatedAt:         s.UpdatedAt.Time,
+		CancelAtPeriodEnd: s.CancelAtPeriodEnd,
+		TrialEnd:          s.TrialEnd.Time,
+		StateAt:           s.UpdatedFromEventAt.Time,
+	}
+}
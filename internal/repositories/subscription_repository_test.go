@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	dbSqlc "github.com/DraconDev/go-templ-htmx-ex/database/sqlc"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+// testQueries connects to TEST_DATABASE_URL and applies the migrations,
+// skipping the test when no test database is configured
+func testQueries(t *testing.T) *dbSqlc.Queries {
+	t.Helper()
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := filepath.Glob("../../database/migrations/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		migrationSQL, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(string(migrationSQL)); err != nil {
+			t.Fatalf("Failed to apply %s: %v", migration, err)
+		}
+	}
+	return dbSqlc.New(db)
+}
+
+func TestUpsertSubscriptionEventOrder(t *testing.T) {
+	queries := testQueries(t)
+	ctx := context.Background()
+
+	suffix := uuid.NewString()
+	user, err := NewUserRepository(queries).CreateUser(ctx, &models.User{
+		AuthID: "auth_" + suffix,
+		Email:  suffix + "@example.com",
+		Name:   "Event Order",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := NewSubscriptionRepository(queries)
+
+	created := time.Now().Truncate(time.Second)
+	sub := models.Subscription{
+		UserID:         user.ID,
+		SubscriptionID: "sub_" + suffix,
+		ProductID:      "prod_pro",
+		Status:         models.SubscriptionStatusTrialing,
+		StateAt:        created,
+	}
+	if _, err := repo.UpsertSubscription(ctx, sub); err != nil {
+		t.Fatal(err)
+	}
+
+	// subscription.updated created in the same second as subscription.created
+	sub.Status = models.SubscriptionStatusActive
+	if _, err := repo.UpsertSubscription(ctx, sub); err != nil {
+		t.Fatalf("Expected an event from the same second to apply, got %v", err)
+	}
+
+	// A sync applies without moving the event time
+	sub.StateAt = time.Time{}
+	sub.CancelAtPeriodEnd = true
+	stored, err := repo.UpsertSubscription(ctx, sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != models.SubscriptionStatusActive || !stored.CancelAtPeriodEnd || !stored.StateAt.Equal(created) {
+		t.Errorf("Expected the active state as of the last event, got %+v", stored)
+	}
+
+	// An event from an earlier second is stale
+	sub.StateAt = created.Add(-time.Second)
+	sub.Status = models.SubscriptionStatusTrialing
+	if _, err := repo.UpsertSubscription(ctx, sub); !errors.Is(err, models.ErrStaleSubscription) {
+		t.Errorf("Expected ErrStaleSubscription, got %v", err)
+	}
+}
@@ -79,6 +79,34 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 	}, nil
 }
 
+// GetUserByID retrieves a user by their local ID
+func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	if r.queries == nil {
+		return nil, models.ErrDatabaseNotConnected
+	}
+
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	dbUser, err := r.queries.GetUserByID(ctx, uuidID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.User{
+		ID:        dbUser.ID.String(),
+		AuthID:    dbUser.AuthID,
+		Email:     dbUser.Email,
+		Name:      dbUser.Name,
+		Picture:   dbUser.Picture.String,
+		IsAdmin:   dbUser.IsAdmin.Bool,
+		CreatedAt: dbUser.CreatedAt.Time,
+		UpdatedAt: dbUser.UpdatedAt.Time,
+	}, nil
+}
+
 // GetAllUsers retrieves all users
 func (r *UserRepository) GetAllUsers(ctx context.Context) ([]models.User, error) {
 	if r.queries == nil {
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/admin"
@@ -10,6 +11,9 @@ import (
 	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/dashboard"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/payment"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/settings"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/shop"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/middleware"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
 	"github.com/gorilla/mux"
 )
 
@@ -21,104 +25,167 @@ type HandlerInstances struct {
 	PaymentHandler   *payment.PaymentHandler
 	DashboardHandler *dashboard.DashboardHandler
 	SettingsHandler  *settings.SettingsHandler
+	WebhookHandler   *payment.WebhookHandler
+	ShopHandler      *shop.ShopHandler
 }
 
-// SetupRoutes configures and returns the router with all routes
-func SetupRoutes(handlerInstances *HandlerInstances) *mux.Router {
-	router := mux.NewRouter()
-
-	// =============================================================================
-	// PUBLIC ROUTES - No authentication required
-	// =============================================================================
-
-	// Homepage - Main landing page with platform showcase
-	router.HandleFunc("/", handlers.HomeHandler).Methods("GET")
-
-	// Health check - API health monitoring endpoint
-	router.HandleFunc("/health", handlers.HealthHandler).Methods("GET")
-
-	// Login page - OAuth provider selection UI
-	router.HandleFunc("/login", handlers.LoginHandler).Methods("GET")
-
-	// Pricing page - Public pricing information
-	if handlerInstances.PaymentHandler != nil {
-		router.HandleFunc("/pricing", handlerInstances.PaymentHandler.PricingPageHandler).Methods("GET")
-	}
-
-	// =============================================================================
-	// OAUTH AUTHENTICATION FLOW
-	// =============================================================================
-
-	// OAuth Login Route - Consolidated with provider parameter
-	if handlerInstances.LoginHandler != nil {
-		router.HandleFunc("/auth/login", handlerInstances.LoginHandler.LoginHandler).Methods("GET")
-		router.HandleFunc("/auth/callback", handlerInstances.LoginHandler.AuthCallbackHandler).Methods("GET")
-	}
-
-	// =============================================================================
-	// PROTECTED USER ROUTES - Authentication required
-	// =============================================================================
-
-	// Dashboard - Main user interface
-	if handlerInstances.DashboardHandler != nil {
-		router.HandleFunc("/dashboard", handlerInstances.DashboardHandler.DashboardHandler).Methods("GET")
-	}
-
-	// User profile page - Display user information and account details
-	router.HandleFunc("/profile", handlers.ProfileHandler).Methods("GET")
-
-	// Settings Routes
-	if handlerInstances.SettingsHandler != nil {
-		router.HandleFunc("/settings", handlerInstances.SettingsHandler.SettingsPageHandler).Methods("GET")
-		router.HandleFunc("/settings/update", handlerInstances.SettingsHandler.UpdateSettingsHandler).Methods("POST")
-		router.HandleFunc("/settings/billing", handlerInstances.SettingsHandler.BillingPortalHandler).Methods("POST", "GET")
-	}
+// route is a single entry of the route table
+type route struct {
+	RouteInfo
+	methods    []string
+	handler    http.HandlerFunc
+	enabled    bool // false when the owning handler instance is not available
+	csrfExempt bool // true for routes that cannot carry a token: session setup and signed webhooks
+}
 
-	// Payment page - Subscription and billing management
-	if handlerInstances.PaymentHandler != nil {
-		router.HandleFunc("/payment", handlerInstances.PaymentHandler.PaymentPageHandler).Methods("GET")
-		router.HandleFunc("/payment/success", handlerInstances.PaymentHandler.SuccessHandler).Methods("GET")
-		router.HandleFunc("/payment/cancel", handlerInstances.PaymentHandler.CancelHandler).Methods("GET")
+// routeTable declares every application route together with its access policy.
+// It is the single source for router registration, AuthMiddleware enforcement
+// and the route listing helpers below.
+func routeTable(h *HandlerInstances) []route {
+	return []route{
+		// =============================================================================
+		// PUBLIC ROUTES - No authentication required
+		// =============================================================================
+		newRoute("home", "/", middleware.PolicyPublic, "Main landing page", handlers.HomeHandler, true, "GET"),
+		newRoute("health", "/health", middleware.PolicyPublic, "Health check endpoint", handlers.HealthHandler, true, "GET"),
+		newRoute("login", "/login", middleware.PolicyPublic, "Login page", handlers.LoginHandler, true, "GET"),
+		newRoute("pricing", "/pricing", middleware.PolicyPublic, "Pricing page", h.PaymentHandler.PricingPageHandler, h.PaymentHandler != nil, "GET"),
+		newRoute("pricing_plans", "/pricing/plans", middleware.PolicyPublic, "Pricing plans for a billing interval (HTMX)", h.PaymentHandler.PricingPlansHandler, h.PaymentHandler != nil, "GET"),
+		newRoute("pricing_promotion", "/pricing/promotion", middleware.PolicyPublic, "Prices with a promotion code applied (HTMX)", h.PaymentHandler.PromotionHandler, h.PaymentHandler != nil, "GET"),
+
+		// =============================================================================
+		// OAUTH AUTHENTICATION FLOW
+		// =============================================================================
+		newRoute("oauth_login", "/auth/login", middleware.PolicyPublic, "OAuth provider login", h.LoginHandler.LoginHandler, h.LoginHandler != nil, "GET"),
+		newRoute("oauth_callback", "/auth/callback", middleware.PolicyPublic, "OAuth callback handler", h.LoginHandler.AuthCallbackHandler, h.LoginHandler != nil, "GET"),
+
+		// =============================================================================
+		// PROTECTED USER ROUTES - Authentication required
+		// =============================================================================
+		newRoute("dashboard", "/dashboard", middleware.PolicyAuthenticated, "User dashboard", h.DashboardHandler.DashboardHandler, h.DashboardHandler != nil, "GET"),
+		newRoute("profile", "/profile", middleware.PolicyAuthenticated, "User profile page", handlers.ProfileHandler, true, "GET"),
+		newRoute("account_subscriptions", "/account/subscriptions", middleware.PolicyAuthenticated, "Subscriptions across projects (HTMX)", h.DashboardHandler.ProjectSubscriptionsHandler, h.DashboardHandler != nil, "GET"),
+		newRoute("account_subscription", "/account/subscription", middleware.PolicyAuthenticated, "Current subscription and its actions (HTMX)", h.DashboardHandler.SubscriptionManageHandler, h.DashboardHandler != nil, "GET"),
+		newRoute("account_subscription_cancel_dialog", "/account/subscription/cancel", middleware.PolicyAuthenticated, "Cancellation confirmation (HTMX)", h.DashboardHandler.CancelDialogHandler, h.DashboardHandler != nil, "GET"),
+		newRoute("account_subscription_cancel", "/account/subscription/cancel", middleware.PolicyAuthenticated, "Cancel subscription at period end (HTMX)", h.DashboardHandler.CancelSubscriptionHandler, h.DashboardHandler != nil, "POST"),
+		newRoute("account_subscription_resume", "/account/subscription/resume", middleware.PolicyAuthenticated, "Resume a pending cancellation (HTMX)", h.DashboardHandler.ResumeSubscriptionHandler, h.DashboardHandler != nil, "POST"),
+		newRoute("account_subscription_change_preview", "/account/subscription/change", middleware.PolicyAuthenticated, "Plan change proration preview (HTMX)", h.DashboardHandler.ChangePreviewHandler, h.DashboardHandler != nil, "GET"),
+		newRoute("account_subscription_change", "/account/subscription/change", middleware.PolicyAuthenticated, "Change plan or billing interval (HTMX)", h.DashboardHandler.ChangeSubscriptionHandler, h.DashboardHandler != nil, "POST"),
+		newRoute("settings", "/settings", middleware.PolicyAuthenticated, "User settings page", h.SettingsHandler.SettingsPageHandler, h.SettingsHandler != nil, "GET"),
+		newRoute("settings_update", "/settings/update", middleware.PolicyAuthenticated, "Update user preferences", h.SettingsHandler.UpdateSettingsHandler, h.SettingsHandler != nil, "POST"),
+		newRoute("settings_billing", "/settings/billing", middleware.PolicyAuthenticated, "Open billing portal", h.SettingsHandler.BillingPortalHandler, h.SettingsHandler != nil, "POST"),
+		newRoute("settings_billing_history", "/settings/billing/history", middleware.PolicyAuthenticated, "Invoices and card on file (HTMX)", h.SettingsHandler.BillingHistoryHandler, h.SettingsHandler != nil, "GET"),
+		newRoute("settings_sessions", "/settings/sessions", middleware.PolicyAuthenticated, "Active sessions list", h.SessionHandler.ActiveSessionsHandler, h.SessionHandler != nil, "GET"),
+		newRoute("settings_revoke_session", "/settings/sessions/{id}/revoke", middleware.PolicyAuthenticated, "Sign out a device", h.SessionHandler.RevokeSessionHandler, h.SessionHandler != nil, "POST"),
+		newRoute("payment", "/payment", middleware.PolicyAuthenticated, "Payment and subscription page", h.PaymentHandler.PaymentPageHandler, h.PaymentHandler != nil, "GET"),
+		newRoute("payment_success", "/payment/success", middleware.PolicyAuthenticated, "Payment success page", h.PaymentHandler.SuccessHandler, h.PaymentHandler != nil, "GET"),
+		newRoute("payment_cancel", "/payment/cancel", middleware.PolicyAuthenticated, "Payment cancelled page", h.PaymentHandler.CancelHandler, h.PaymentHandler != nil, "GET"),
+		newRoute("shop", "/shop", middleware.PolicyAuthenticated, "Shop for one-off purchases", h.ShopHandler.ShopPageHandler, h.ShopHandler != nil, "GET"),
+		newRoute("cart", "/cart", middleware.PolicyAuthenticated, "Shopping cart", h.ShopHandler.CartPageHandler, h.ShopHandler != nil, "GET"),
+		newRoute("cart_add", "/cart/items", middleware.PolicyAuthenticated, "Add an item to the cart (HTMX)", h.ShopHandler.AddToCartHandler, h.ShopHandler != nil, "POST"),
+		newRoute("cart_update", "/cart/items/{price_id}/quantity", middleware.PolicyAuthenticated, "Change a cart line quantity (HTMX)", h.ShopHandler.UpdateCartItemHandler, h.ShopHandler != nil, "POST"),
+		newRoute("cart_remove", "/cart/items/{price_id}/remove", middleware.PolicyAuthenticated, "Remove a cart line (HTMX)", h.ShopHandler.RemoveCartItemHandler, h.ShopHandler != nil, "POST"),
+		newRoute("cart_checkout", "/cart/checkout", middleware.PolicyAuthenticated, "Check out the cart", h.ShopHandler.CartCheckoutHandler, h.ShopHandler != nil, "POST"),
+		newRoute("cart_success", "/cart/success", middleware.PolicyAuthenticated, "Cart checkout success page", h.ShopHandler.CartSuccessHandler, h.ShopHandler != nil, "GET"),
+
+		// =============================================================================
+		// ADMIN ROUTES - Admin authentication required
+		// =============================================================================
+		newRoute("admin_dashboard", "/admin", middleware.PolicyAdmin, "Admin dashboard", h.AdminHandler.AdminDashboardHandler, h.AdminHandler != nil, "GET").
+			withPermission(models.PermissionAdminAccess),
+		newRoute("admin_promotions", "/admin/promotions", middleware.PolicyAdmin, "Promotion codes and redemptions", h.AdminHandler.PromotionsHandler, h.AdminHandler != nil, "GET").
+			withPermission(models.PermissionBillingRead),
+		newRoute("admin_get_users", "/api/admin/users", middleware.PolicyAdmin, "Get users API", h.AdminHandler.GetUsersHandler, h.AdminHandler != nil, "GET").
+			withPermission(models.PermissionUsersRead),
+		newRoute("admin_get_analytics", "/api/admin/analytics", middleware.PolicyAdmin, "Get analytics API", h.AdminHandler.GetAnalyticsHandler, h.AdminHandler != nil, "GET").
+			withPermission(models.PermissionAnalyticsRead),
+		newRoute("admin_get_settings", "/api/admin/settings", middleware.PolicyAdmin, "Get settings API", h.AdminHandler.GetSettingsHandler, h.AdminHandler != nil, "GET").
+			withPermission(models.PermissionSettingsRead),
+		newRoute("admin_get_logs", "/api/admin/logs", middleware.PolicyAdmin, "Get logs API", h.AdminHandler.GetLogsHandler, h.AdminHandler != nil, "GET").
+			withPermission(models.PermissionLogsRead),
+		newRoute("admin_get_roles", "/api/admin/roles", middleware.PolicyAdmin, "List roles API", h.AdminHandler.GetRolesHandler, h.AdminHandler != nil, "GET").
+			withPermission(models.PermissionUsersRead),
+		newRoute("admin_assign_role", "/api/admin/users/{id}/roles", middleware.PolicyAdmin, "Assign role to user API", h.AdminHandler.AssignRoleHandler, h.AdminHandler != nil, "POST").
+			withPermission(models.PermissionUsersWrite),
+		newRoute("admin_remove_role", "/api/admin/users/{id}/roles/{role}", middleware.PolicyAdmin, "Remove role from user API", h.AdminHandler.RemoveRoleHandler, h.AdminHandler != nil, "DELETE").
+			withPermission(models.PermissionUsersWrite),
+
+		// =============================================================================
+		// SESSION MANAGEMENT API - Handles session tokens itself
+		// =============================================================================
+		newRoute("logout", "/api/auth/logout", middleware.PolicyAuthAPI, "User logout", h.SessionHandler.LogoutHandler, h.SessionHandler != nil, "POST"),
+		newRoute("logout_all", "/api/auth/logout-all", middleware.PolicyAuthenticated, "Log out of every session", h.SessionHandler.LogoutAllHandler, h.SessionHandler != nil, "POST"),
+		newRoute("set_session", "/api/auth/set-session", middleware.PolicyAuthAPI, "Set session", h.SessionHandler.SetSessionHandler, h.SessionHandler != nil, "POST").
+			withoutCSRF(),
+		newRoute("exchange_code", "/api/auth/exchange-code", middleware.PolicyAuthAPI, "Exchange auth code", h.SessionHandler.ExchangeCodeHandler, h.SessionHandler != nil, "POST").
+			withoutCSRF(),
+
+		// =============================================================================
+		// PAYMENT API - Payment processing endpoints
+		// =============================================================================
+		newRoute("payment_checkout", "/api/payment/checkout", middleware.PolicyAuthenticated, "Create payment checkout session", h.PaymentHandler.CheckoutHandler, h.PaymentHandler != nil, "POST"),
+
+		// =============================================================================
+		// WEBHOOKS - Server-to-server, authenticated by signature
+		// =============================================================================
+		newRoute("payment_webhook", "/webhooks/payment", middleware.PolicyPublic, "Payment service webhook receiver", h.WebhookHandler.PaymentWebhookHandler, h.WebhookHandler != nil, "POST").
+			withoutCSRF(),
 	}
+}
 
-	// =============================================================================
-	// ADMIN ROUTES - Admin authentication required
-	// =============================================================================
-
-	// Admin dashboard - Main admin interface for platform management
-	if handlerInstances.AdminHandler != nil {
-		router.HandleFunc("/admin", handlerInstances.AdminHandler.AdminDashboardHandler).Methods("GET")
-		router.HandleFunc("/api/admin/users", handlerInstances.AdminHandler.GetUsersHandler).Methods("GET")
-		router.HandleFunc("/api/admin/analytics", handlerInstances.AdminHandler.GetAnalyticsHandler).Methods("GET")
-		router.HandleFunc("/api/admin/settings", handlerInstances.AdminHandler.GetSettingsHandler).Methods("GET")
-		router.HandleFunc("/api/admin/logs", handlerInstances.AdminHandler.GetLogsHandler).Methods("GET")
+// newRoute builds a route table entry
+func newRoute(name, pattern string, policy middleware.AccessPolicy, description string, handler http.HandlerFunc, enabled bool, methods ...string) route {
+	return route{
+		RouteInfo: RouteInfo{
+			Name:        name,
+			Method:      strings.Join(methods, ","),
+			Pattern:     pattern,
+			Description: description,
+			Policy:      string(policy),
+		},
+		methods: methods,
+		handler: handler,
+		enabled: enabled,
 	}
+}
 
-	// =============================================================================
-	// SESSION MANAGEMENT API - Authentication required
-	// =============================================================================
-
-	if handlerInstances.SessionHandler != nil {
-		// Logout user - Destroy current session and clear cookies
-		router.HandleFunc("/api/auth/logout", handlerInstances.SessionHandler.LogoutHandler).Methods("POST")
-
-		// Set session - Create new server session with provided session ID
-		router.HandleFunc("/api/auth/set-session", handlerInstances.SessionHandler.SetSessionHandler).Methods("POST")
+// withPermission requires a permission on top of the route's access policy
+func (rt route) withPermission(permission string) route {
+	rt.Permission = permission
+	return rt
+}
 
-		// Exchange code - Exchange OAuth authorization code for session tokens
-		router.HandleFunc("/api/auth/exchange-code", handlerInstances.SessionHandler.ExchangeCodeHandler).Methods("POST")
-	}
+// withoutCSRF exempts the route from CSRFMiddleware
+func (rt route) withoutCSRF() route {
+	rt.csrfExempt = true
+	return rt
+}
 
-	// =============================================================================
-	// PAYMENT API - Payment processing endpoints
-	// =============================================================================
+// SetupRoutes configures and returns the router with all routes
+func SetupRoutes(handlerInstances *HandlerInstances) *mux.Router {
+	router := mux.NewRouter()
 
-	if handlerInstances.PaymentHandler != nil {
-		router.HandleFunc("/api/payment/checkout", handlerInstances.PaymentHandler.CheckoutHandler).Methods("POST")
+	for _, rt := range routeTable(handlerInstances) {
+		if !rt.enabled {
+			continue
+		}
+		var handler http.Handler = rt.handler
+		if rt.Permission != "" {
+			handler = middleware.RequirePermission(rt.Permission)(handler)
+		} else if rt.Policy == string(middleware.PolicyAdmin) {
+			// Admin routes without an explicit permission still need admin access
+			handler = middleware.RequireAdmin(handler)
+		}
+		router.Handle(rt.Pattern, handler).Methods(rt.methods...)
+		middleware.RegisterRoutePolicy(rt.Pattern, middleware.AccessPolicy(rt.Policy))
+		if rt.csrfExempt {
+			middleware.RegisterCSRFExempt(rt.Pattern)
+		}
 	}
 
 	// Static files (for CSS, JS, etc.)
 	router.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(http.Dir("static/"))))
+	middleware.RegisterRoutePolicy("/static/", middleware.PolicyPublic)
 
 	return router
 }
@@ -129,43 +196,18 @@ type RouteInfo struct {
 	Method      string `json:"method"`
 	Pattern     string `json:"pattern"`
 	Description string `json:"description"`
+	Policy      string `json:"policy"`
+	Permission  string `json:"permission,omitempty"`
 }
 
 // GetAllRoutes returns information about all application routes
 func GetAllRoutes() []RouteInfo {
-	return []RouteInfo{
-		// Public Routes
-		{Name: "home", Method: "GET", Pattern: "/", Description: "Main landing page"},
-		{Name: "health", Method: "GET", Pattern: "/health", Description: "Health check endpoint"},
-		{Name: "login", Method: "GET", Pattern: "/login", Description: "Login page"},
-		{Name: "pricing", Method: "GET", Pattern: "/pricing", Description: "Pricing page"},
-
-		// OAuth Routes
-		{Name: "oauth_login", Method: "GET", Pattern: "/auth/login", Description: "OAuth provider login"},
-		{Name: "oauth_callback", Method: "GET", Pattern: "/auth/callback", Description: "OAuth callback handler"},
-
-		// Protected Routes
-		{Name: "dashboard", Method: "GET", Pattern: "/dashboard", Description: "User dashboard"},
-		{Name: "profile", Method: "GET", Pattern: "/profile", Description: "User profile page"},
-		{Name: "payment", Method: "GET", Pattern: "/payment", Description: "Payment and subscription page"},
-		{Name: "payment_success", Method: "GET", Pattern: "/payment/success", Description: "Payment success page"},
-		{Name: "payment_cancel", Method: "GET", Pattern: "/payment/cancel", Description: "Payment cancelled page"},
-
-		// Admin Routes
-		{Name: "admin_dashboard", Method: "GET", Pattern: "/admin", Description: "Admin dashboard"},
-		{Name: "admin_get_users", Method: "GET", Pattern: "/api/admin/users", Description: "Get users API"},
-		{Name: "admin_get_analytics", Method: "GET", Pattern: "/api/admin/analytics", Description: "Get analytics API"},
-		{Name: "admin_get_settings", Method: "GET", Pattern: "/api/admin/settings", Description: "Get settings API"},
-		{Name: "admin_get_logs", Method: "GET", Pattern: "/api/admin/logs", Description: "Get logs API"},
-
-		// Auth API Routes
-		{Name: "logout", Method: "POST", Pattern: "/api/auth/logout", Description: "User logout"},
-		{Name: "set_session", Method: "POST", Pattern: "/api/auth/set-session", Description: "Set session"},
-		{Name: "exchange_code", Method: "POST", Pattern: "/api/auth/exchange-code", Description: "Exchange auth code"},
-
-		// Payment API Routes
-		{Name: "payment_checkout", Method: "POST", Pattern: "/api/payment/checkout", Description: "Create payment checkout session"},
+	table := routeTable(&HandlerInstances{})
+	infos := make([]RouteInfo, len(table))
+	for i, rt := range table {
+		infos[i] = rt.RouteInfo
 	}
+	return infos
 }
 
 // RouteSummary provides a summary of all registered routes
@@ -178,14 +220,25 @@ type RouteSummary struct {
 	PaymentAPIRoutes int `json:"payment_api_routes"`
 }
 
-// CountRoutes provides a count of all route types
+// CountRoutes provides a count of all route types.
+// PaymentAPIRoutes is a subset of ProtectedRoutes.
 func CountRoutes() RouteSummary {
-	return RouteSummary{
-		TotalRoutes:      17,
-		PublicRoutes:     3,
-		ProtectedRoutes:  4,
-		AdminRoutes:      5,
-		AuthAPIRoutes:    4,
-		PaymentAPIRoutes: 1,
+	var summary RouteSummary
+	for _, info := range GetAllRoutes() {
+		summary.TotalRoutes++
+		switch middleware.AccessPolicy(info.Policy) {
+		case middleware.PolicyPublic:
+			summary.PublicRoutes++
+		case middleware.PolicyAuthenticated:
+			summary.ProtectedRoutes++
+		case middleware.PolicyAdmin:
+			summary.AdminRoutes++
+		case middleware.PolicyAuthAPI:
+			summary.AuthAPIRoutes++
+		}
+		if strings.HasPrefix(info.Pattern, "/api/payment/") {
+			summary.PaymentAPIRoutes++
+		}
 	}
+	return summary
 }
@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"testing"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/admin"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/auth/login"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/auth/session"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/dashboard"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/payment"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/settings"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/handlers/shop"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/middleware"
+	"github.com/gorilla/mux"
+)
+
+func TestEveryRouteHasPolicy(t *testing.T) {
+	router := SetupRoutes(&HandlerInstances{
+		AdminHandler:     &admin.AdminHandler{},
+		LoginHandler:     &login.LoginHandler{},
+		SessionHandler:   &session.SessionHandler{},
+		PaymentHandler:   &payment.PaymentHandler{},
+		DashboardHandler: &dashboard.DashboardHandler{},
+		SettingsHandler:  &settings.SettingsHandler{},
+		WebhookHandler:   &payment.WebhookHandler{},
+		ShopHandler:      &shop.ShopHandler{},
+	})
+
+	routes := 0
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		routes++
+		if _, ok := middleware.LookupRoutePolicy(template); !ok {
+			t.Errorf("Route %s has no access policy", template)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if routes != len(routeTable(&HandlerInstances{}))+1 {
+		t.Errorf("Expected every route table entry and the static files to be registered, got %d routes", routes)
+	}
+}
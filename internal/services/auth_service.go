@@ -1,54 +1,38 @@
 package services
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
-	"time"
 
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/authms"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/config"
 )
 
 // AuthService handles session management with the auth microservice
 type AuthService struct {
-	config  *config.Config
-	client  *http.Client
-	timeout time.Duration
+	config *config.Config
+	client *authms.Client
 }
 
-// NewAuthService creates a new auth service instance
-func NewAuthService(cfg *config.Config) *AuthService {
+// NewAuthService creates a new auth service instance around a shared auth client
+func NewAuthService(cfg *config.Config, client *authms.Client) *AuthService {
 	return &AuthService{
-		config:  cfg,
-		client:  &http.Client{Timeout: 5 * time.Second}, // Reduced timeout
-		timeout: 5 * time.Second,
+		config: cfg,
+		client: client,
 	}
 }
 
-// CreateSession returns map with session_id and user_context for session establishment
-func (s *AuthService) CreateSession(auth_code string) (map[string]interface{}, error) {
-	return s.callAuthServiceGeneric("/auth/session/create", map[string]string{
-		"auth_code": auth_code,
-	})
+// CreateSession exchanges an auth code for a session_id and user_context
+func (s *AuthService) CreateSession(ctx context.Context, auth_code string) (*authms.SessionResponse, error) {
+	return s.client.CreateSession(ctx, authms.CreateSessionRequest{AuthCode: auth_code})
 }
 
 // ExchangeCodeForTokens exchanges OAuth authorization code for session tokens
-func (s *AuthService) ExchangeCodeForTokens(auth_code string) (*models.TokenExchangeResponse, error) {
+func (s *AuthService) ExchangeCodeForTokens(ctx context.Context, auth_code string) (*models.TokenExchangeResponse, error) {
 	fmt.Printf("🔐 AUTH-SERVICE: Exchanging code for tokens...\n")
 
-	// Call auth service and extract session_id like the working reference
-	return s.extractSessionFromResponse(auth_code)
-}
-
-// extractSessionFromResponse extracts session_id from auth service response
-func (s *AuthService) extractSessionFromResponse(auth_code string) (*models.TokenExchangeResponse, error) {
-	// Get raw response like working reference
-	response, err := s.makeRequest("/auth/session/create", map[string]string{
-		"auth_code": auth_code,
-	})
+	session, err := s.CreateSession(ctx, auth_code)
 	if err != nil {
 		return &models.TokenExchangeResponse{
 			Success: false,
@@ -56,152 +40,67 @@ func (s *AuthService) extractSessionFromResponse(auth_code string) (*models.Toke
 		}, err
 	}
 
-	// Parse response as map to extract session_id
-	var respData map[string]interface{}
-	if err := json.Unmarshal(response, &respData); err != nil {
-		return &models.TokenExchangeResponse{
-			Success: false,
-			Error:   "Failed to parse response: " + err.Error(),
-		}, err
-	}
-
-	// Extract session_id like working reference
-	var sessionID string
-	var hasSessionID bool
-
-	if sessionInterface, exists := respData["session_id"]; exists {
-		if sessionStr, ok := sessionInterface.(string); ok {
-			sessionID = sessionStr
-			hasSessionID = true
-		}
-	}
-
-	if !hasSessionID || sessionID == "" {
-		return &models.TokenExchangeResponse{
-			Success: false,
-			Error:   "Missing session_id in auth service response",
-		}, fmt.Errorf("missing session_id")
-	}
-
-	// Return session_id as IdToken like working reference
+	// Return session_id as IdToken
 	return &models.TokenExchangeResponse{
 		Success: true,
-		IdToken: sessionID,
+		IdToken: session.SessionID,
 	}, nil
 }
 
 // RefreshSession refreshes an existing session_id
-func (s *AuthService) RefreshSession(session_id string) (*models.AuthResponse, error) {
-	return s.callAuthService("/auth/session/refresh", map[string]string{
-		"session_id": session_id,
-	})
-}
-
-// GetUserInfo retrieves user information using session_id by refreshing the session
-// This calls /auth/session/refresh which returns SessionRefreshResponse with user_context
-func (s *AuthService) GetUserInfo(session_id string) (*models.UserSessionContext, error) {
-	bodyBytes, err := s.makeRequest("/auth/session/refresh", map[string]string{
-		"session_id": session_id,
-	})
+func (s *AuthService) RefreshSession(ctx context.Context, session_id string) (*models.AuthResponse, error) {
+	session, err := s.client.RefreshSession(ctx, authms.RefreshSessionRequest{SessionID: session_id})
 	if err != nil {
 		return nil, err
 	}
 
-	var refreshResp models.SessionRefreshResponse
-	if err := json.Unmarshal(bodyBytes, &refreshResp); err != nil {
-		return nil, err
-	}
-
-	return &refreshResp.UserContext, nil
-}
-
-// ValidateSession validates a session_id and returns user information for middleware
-func (s *AuthService) ValidateSession(session_id string) (*models.AuthResponse, error) {
-	return s.callAuthService("/auth/session/refresh", map[string]string{
-		"session_id": session_id,
-	})
-}
-
-// Logout logs out a user using session_id
-func (s *AuthService) Logout(session_id string) error {
-	fmt.Printf("User logged out with session_id: %s\n", session_id)
-	return nil
+	return &models.AuthResponse{
+		Success: session.Success || session.HasUser(),
+		UserID:  session.UserContext.UserID,
+		Email:   session.UserContext.Email,
+		Name:    session.UserContext.Name,
+		Picture: session.UserContext.Picture,
+	}, nil
 }
 
-// callAuthService makes a request to the auth microservice
-func (s *AuthService) callAuthService(endpoint string, params map[string]string) (*models.AuthResponse, error) {
-	bodyBytes, err := s.makeRequest(endpoint, params)
+// GetUserInfo retrieves user information using session_id by refreshing the session
+// This calls /auth/session/refresh which returns the user_context
+func (s *AuthService) GetUserInfo(ctx context.Context, session_id string) (*models.UserSessionContext, error) {
+	session, err := s.client.RefreshSession(ctx, authms.RefreshSessionRequest{SessionID: session_id})
 	if err != nil {
 		return nil, err
 	}
 
-	var authResp models.AuthResponse
-	if err := json.Unmarshal(bodyBytes, &authResp); err != nil {
-		return nil, err
-	}
-
-	return &authResp, nil
+	return toUserSessionContext(session.UserContext), nil
 }
 
-// callAuthServiceGeneric makes a request and returns generic response
-func (s *AuthService) callAuthServiceGeneric(endpoint string, params map[string]string) (map[string]interface{}, error) {
-	bodyBytes, err := s.makeRequest(endpoint, params)
-	if err != nil {
-		return nil, err
-	}
-
-	var response map[string]interface{}
-	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		return nil, err
-	}
-
-	return response, nil
+// ValidateSession validates a session_id and returns user information for middleware
+func (s *AuthService) ValidateSession(ctx context.Context, session_id string) (*models.AuthResponse, error) {
+	return s.RefreshSession(ctx, session_id)
 }
 
-// makeRequest handles the HTTP request to auth microservice
-func (s *AuthService) makeRequest(endpoint string, params map[string]string) ([]byte, error) {
-	jsonData, err := json.Marshal(params)
-	if err != nil {
-		return nil, err
+// Logout revokes a single session_id at the auth microservice
+func (s *AuthService) Logout(ctx context.Context, session_id string) error {
+	if err := s.client.RevokeSession(ctx, authms.RevokeSessionRequest{SessionID: session_id}); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
 	}
+	return nil
+}
 
-	url := s.config.AuthServiceURL + endpoint
-	fmt.Printf("🔍 AUTH-SERVICE: Making request to %s\n", url)
-	fmt.Printf("🔍 AUTH-SERVICE: Payload: %s\n", string(jsonData))
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	fmt.Printf("🔍 AUTH-SERVICE: Sending HTTP request...\n")
-	resp, err := s.client.Do(req)
-	if err != nil {
-		fmt.Printf("🔍 AUTH-SERVICE: ❌ Request failed: %v\n", err)
-		return nil, fmt.Errorf("failed to connect to auth service at %s: %v", s.config.AuthServiceURL, err)
-	}
-	defer resp.Body.Close()
-
-	// Debug logging
-	fmt.Printf("🔍 AUTH-SERVICE: ✅ Response received - Status: %s\n", resp.Status)
-	fmt.Printf("🔍 AUTH-SERVICE: Response headers: %v\n", resp.Header)
-
-	// Read response body
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Printf("🔍 AUTH-SERVICE: ❌ Failed to read response body: %v\n", err)
-		return nil, err
+// LogoutAll revokes every session belonging to the owner of session_id
+func (s *AuthService) LogoutAll(ctx context.Context, session_id string) error {
+	if err := s.client.RevokeAllSessions(ctx, authms.RevokeSessionRequest{SessionID: session_id}); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
 	}
+	return nil
+}
 
-	fmt.Printf("🔍 AUTH-SERVICE: Raw response body: %s\n", string(bodyBytes))
-	fmt.Printf("🔍 AUTH-SERVICE: Body length: %d\n", len(bodyBytes))
-
-	// Check if response is successful
-	if resp.StatusCode >= 400 {
-		fmt.Printf("🔍 AUTH-SERVICE: ❌ HTTP error %d: %s\n", resp.StatusCode, string(bodyBytes))
-		return nil, fmt.Errorf("auth service error: %s - %s", resp.Status, string(bodyBytes))
+// toUserSessionContext maps the auth client's user context to the app model
+func toUserSessionContext(userContext authms.UserContext) *models.UserSessionContext {
+	return &models.UserSessionContext{
+		UserID:  userContext.UserID,
+		Name:    userContext.Name,
+		Email:   userContext.Email,
+		Picture: userContext.Picture,
 	}
-
-	return bodyBytes, nil
 }
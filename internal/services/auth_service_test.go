@@ -1,9 +1,14 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/authms"
 	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/config"
 )
 
@@ -19,7 +24,7 @@ func TestNewAuthService(t *testing.T) {
 	fmt.Println("🧪 Testing NewAuthService")
 
 	cfg := testConfig()
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, authms.New(cfg.AuthServiceURL))
 
 	if authService == nil {
 		t.Error("NewAuthService should return a non-nil service")
@@ -35,11 +40,12 @@ func TestAuthServiceCreateSession(t *testing.T) {
 	fmt.Println("🧪 Testing CreateSession")
 
 	cfg := testConfig()
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, authms.New(cfg.AuthServiceURL))
+	ctx := context.Background()
 
 	// Test with empty auth code
 	t.Run("empty_auth_code", func(t *testing.T) {
-		_, err := authService.CreateSession("")
+		_, err := authService.CreateSession(ctx, "")
 
 		// Should handle empty auth code gracefully
 		if err == nil {
@@ -51,7 +57,7 @@ func TestAuthServiceCreateSession(t *testing.T) {
 
 	// Test with valid auth code (will fail due to no HTTP mock, but tests the structure)
 	t.Run("valid_auth_code", func(t *testing.T) {
-		_, err := authService.CreateSession("test-github-code")
+		_, err := authService.CreateSession(ctx, "test-github-code")
 
 		// This will likely fail due to no HTTP server, but that's expected in unit tests
 		if err != nil {
@@ -66,11 +72,12 @@ func TestAuthServiceExchangeCodeForTokens(t *testing.T) {
 	fmt.Println("🧪 Testing ExchangeCodeForTokens")
 
 	cfg := testConfig()
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, authms.New(cfg.AuthServiceURL))
+	ctx := context.Background()
 
 	// Test empty authorization code
 	t.Run("empty_auth_code", func(t *testing.T) {
-		result, err := authService.ExchangeCodeForTokens("")
+		result, err := authService.ExchangeCodeForTokens(ctx, "")
 
 		// Should handle empty code appropriately
 		if err == nil {
@@ -84,7 +91,7 @@ func TestAuthServiceExchangeCodeForTokens(t *testing.T) {
 
 	// Test valid authorization code
 	t.Run("valid_auth_code", func(t *testing.T) {
-		result, err := authService.ExchangeCodeForTokens("test-github-code")
+		result, err := authService.ExchangeCodeForTokens(ctx, "test-github-code")
 
 		if err != nil {
 			t.Logf("Expected HTTP connection error (no mock server): %v", err)
@@ -98,10 +105,11 @@ func TestAuthServiceRefreshSession(t *testing.T) {
 	fmt.Println("🧪 Testing RefreshSession")
 
 	cfg := testConfig()
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, authms.New(cfg.AuthServiceURL))
+	ctx := context.Background()
 
 	t.Run("refresh_session", func(t *testing.T) {
-		_, err := authService.RefreshSession("test-session-123")
+		_, err := authService.RefreshSession(ctx, "test-session-123")
 
 		if err != nil {
 			t.Logf("Expected HTTP connection error (no mock server): %v", err)
@@ -115,10 +123,11 @@ func TestAuthServiceGetUserInfo(t *testing.T) {
 	fmt.Println("🧪 Testing GetUserInfo")
 
 	cfg := testConfig()
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, authms.New(cfg.AuthServiceURL))
+	ctx := context.Background()
 
 	t.Run("get_user_info", func(t *testing.T) {
-		_, err := authService.GetUserInfo("test-session-123")
+		_, err := authService.GetUserInfo(ctx, "test-session-123")
 
 		if err != nil {
 			t.Logf("Expected HTTP connection error (no mock server): %v", err)
@@ -131,17 +140,55 @@ func TestAuthServiceGetUserInfo(t *testing.T) {
 func TestAuthServiceLogout(t *testing.T) {
 	fmt.Println("🧪 Testing Logout")
 
+	var gotPath, gotSessionID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotPath = r.URL.Path
+		gotSessionID = body["session_id"]
+
+		if body["session_id"] == "unknown-session" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"session not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
 	cfg := testConfig()
-	authService := NewAuthService(cfg)
+	cfg.AuthServiceURL = server.URL
+	authService := NewAuthService(cfg, authms.New(cfg.AuthServiceURL))
+	ctx := context.Background()
 
 	t.Run("logout", func(t *testing.T) {
-		err := authService.Logout("test-session-123")
+		err := authService.Logout(ctx, "test-session-123")
 
-		// Logout should not return error in this implementation
 		if err != nil {
 			t.Errorf("Logout should not return error, got: %v", err)
-		} else {
-			t.Log("Logout executed successfully")
+		}
+		if gotPath != "/auth/session/revoke" {
+			t.Errorf("Expected /auth/session/revoke, got %s", gotPath)
+		}
+		if gotSessionID != "test-session-123" {
+			t.Errorf("Expected session_id to be forwarded, got %q", gotSessionID)
+		}
+	})
+
+	t.Run("logout_all", func(t *testing.T) {
+		err := authService.LogoutAll(ctx, "test-session-123")
+
+		if err != nil {
+			t.Errorf("LogoutAll should not return error, got: %v", err)
+		}
+		if gotPath != "/auth/session/revoke-all" {
+			t.Errorf("Expected /auth/session/revoke-all, got %s", gotPath)
+		}
+	})
+
+	t.Run("revoke_failure", func(t *testing.T) {
+		if err := authService.Logout(ctx, "unknown-session"); err == nil {
+			t.Error("Expected error when auth service rejects revocation")
 		}
 	})
 }
@@ -150,10 +197,11 @@ func TestAuthServiceValidateSession(t *testing.T) {
 	fmt.Println("🧪 Testing ValidateSession")
 
 	cfg := testConfig()
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, authms.New(cfg.AuthServiceURL))
+	ctx := context.Background()
 
 	t.Run("validate_session", func(t *testing.T) {
-		_, err := authService.ValidateSession("test-session-123")
+		_, err := authService.ValidateSession(ctx, "test-session-123")
 
 		if err != nil {
 			t.Logf("Expected HTTP connection error (no mock server): %v", err)
@@ -168,14 +216,15 @@ func TestAuthServiceIntegration(t *testing.T) {
 	fmt.Println("🧪 Testing AuthService Integration Flow")
 
 	cfg := testConfig()
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, authms.New(cfg.AuthServiceURL))
+	ctx := context.Background()
 
 	t.Run("complete_auth_flow", func(t *testing.T) {
 		// Simulate the OAuth callback flow
 		authCode := "github_12345_cb67890"
 
 		// Step 1: Exchange code for tokens
-		tokenResp, err := authService.ExchangeCodeForTokens(authCode)
+		tokenResp, err := authService.ExchangeCodeForTokens(ctx, authCode)
 		if err != nil {
 			t.Logf("Expected HTTP error (no mock): %v", err)
 		} else {
@@ -183,23 +232,21 @@ func TestAuthServiceIntegration(t *testing.T) {
 		}
 
 		// Step 2: Get user info
-		_, err = authService.GetUserInfo("test-session-id")
+		_, err = authService.GetUserInfo(ctx, "test-session-id")
 		if err != nil {
 			t.Logf("Expected HTTP error (no mock): %v", err)
 		}
 
 		// Step 3: Validate session
-		_, err = authService.ValidateSession("test-session-id")
+		_, err = authService.ValidateSession(ctx, "test-session-id")
 		if err != nil {
 			t.Logf("Expected HTTP error (no mock): %v", err)
 		}
 
 		// Step 4: Logout
-		err = authService.Logout("test-session-id")
+		err = authService.Logout(ctx, "test-session-id")
 		if err != nil {
-			t.Errorf("Logout should not return error: %v", err)
-		} else {
-			t.Log("Logout successful")
+			t.Logf("Expected HTTP error (no mock): %v", err)
 		}
 	})
 }
@@ -209,7 +256,7 @@ func TestAuthServiceHTTPConfiguration(t *testing.T) {
 	fmt.Println("🧪 Testing AuthService HTTP Configuration")
 
 	cfg := testConfig()
-	authService := NewAuthService(cfg)
+	authService := NewAuthService(cfg, authms.New(cfg.AuthServiceURL))
 
 	t.Run("service_initialization", func(t *testing.T) {
 		// Test that service is properly initialized
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/repositories"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
+)
+
+var (
+	// ErrUnknownItem is returned for an item or price that is not sold in the shop
+	ErrUnknownItem = errors.New("item is not in the catalog")
+	// ErrInvalidQuantity is returned for a quantity outside 1..models.MaxCartQuantity
+	ErrInvalidQuantity = errors.New("invalid quantity")
+	// ErrMixedCurrency is returned when an item is priced in another currency than the cart
+	ErrMixedCurrency = errors.New("cart items must share a currency")
+	// ErrCartEmpty is returned when checking out a cart without available items
+	ErrCartEmpty = errors.New("cart is empty")
+)
+
+// CartService manages the session-backed shopping cart and its checkout
+type CartService struct {
+	cartRepo      *repositories.CartRepository
+	purchaseRepo  *repositories.PurchaseRepository
+	paymentClient *paymentms.Client
+	catalog       *catalog.Catalog
+}
+
+// NewCartService creates a new cart service
+func NewCartService(cartRepo *repositories.CartRepository, purchaseRepo *repositories.PurchaseRepository, paymentClient *paymentms.Client, catalog *catalog.Catalog) *CartService {
+	return &CartService{
+		cartRepo:      cartRepo,
+		purchaseRepo:  purchaseRepo,
+		paymentClient: paymentClient,
+		catalog:       catalog,
+	}
+}
+
+// Cart returns the session's cart priced from the catalog
+func (s *CartService) Cart(ctx context.Context, sessionID string) (*models.Cart, error) {
+	items, err := s.cartRepo.GetItems(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return buildCart(items, s.catalog), nil
+}
+
+// Add puts quantity of a shop item in the cart
+func (s *CartService) Add(ctx context.Context, sessionID, itemName string, quantity int) error {
+	item, ok := s.catalog.Item(itemName)
+	if !ok {
+		return ErrUnknownItem
+	}
+	if quantity < 1 || quantity > models.MaxCartQuantity {
+		return ErrInvalidQuantity
+	}
+
+	cart, err := s.Cart(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if cart.Currency != "" && cart.Currency != item.Currency {
+		return ErrMixedCurrency
+	}
+
+	return s.cartRepo.AddItem(ctx, sessionID, item.PriceID, quantity)
+}
+
+// SetQuantity changes the quantity of a cart line; 0 removes it
+func (s *CartService) SetQuantity(ctx context.Context, sessionID, priceID string, quantity int) error {
+	if quantity == 0 {
+		return s.Remove(ctx, sessionID, priceID)
+	}
+	if _, ok := s.catalog.LookupItemPrice(priceID); !ok {
+		return ErrUnknownItem
+	}
+	if quantity < 0 || quantity > models.MaxCartQuantity {
+		return ErrInvalidQuantity
+	}
+	return s.cartRepo.SetQuantity(ctx, sessionID, priceID, quantity)
+}
+
+// Remove takes a line out of the cart
+func (s *CartService) Remove(ctx context.Context, sessionID, priceID string) error {
+	return s.cartRepo.RemoveItem(ctx, sessionID, priceID)
+}
+
+// Clear empties the cart
+func (s *CartService) Clear(ctx context.Context, sessionID string) error {
+	return s.cartRepo.Clear(ctx, sessionID)
+}
+
+// Checkout creates a payment service checkout for the available cart lines and
+// records them as pending purchases until the checkout.completed webhook arrives
+func (s *CartService) Checkout(ctx context.Context, sessionID string, user *models.User, successURL, cancelURL string) (*paymentms.CheckoutResponse, error) {
+	cart, err := s.Cart(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []models.CartLine
+	var items []paymentms.CartItem
+	for _, line := range cart.Lines {
+		if !line.Available {
+			continue
+		}
+		lines = append(lines, line)
+		items = append(items, paymentms.CartItem{PriceID: line.PriceID, Quantity: line.Quantity})
+	}
+	if len(items) == 0 {
+		return nil, ErrCartEmpty
+	}
+
+	checkout, err := s.paymentClient.CreateCartCheckout(ctx, paymentms.CartCheckoutRequest{
+		UserID:     user.ID,
+		Email:      user.Email,
+		Items:      items,
+		SuccessURL: successURL,
+		CancelURL:  cancelURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Without the pending lines the webhook could not record the purchase, so
+	// the checkout URL is only handed out once they are stored
+	purchases := make([]models.Purchase, len(lines))
+	for i, line := range lines {
+		purchases[i] = models.Purchase{
+			PriceID:    line.PriceID,
+			ItemName:   line.Label,
+			Quantity:   line.Quantity,
+			UnitAmount: line.Amount,
+			Currency:   line.Currency,
+		}
+	}
+	if err := s.purchaseRepo.CreatePending(ctx, user.ID, checkout.CheckoutSessionID, purchases); err != nil {
+		return nil, fmt.Errorf("failed to record purchase: %w", err)
+	}
+
+	return checkout, nil
+}
+
+// CompleteCheckout marks a checkout's purchases completed
+func (s *CartService) CompleteCheckout(ctx context.Context, checkoutSessionID string) ([]models.Purchase, error) {
+	return s.purchaseRepo.CompleteCheckout(ctx, checkoutSessionID)
+}
+
+// buildCart prices cart items from the catalog; items no longer sold stay
+// listed as unavailable so they can be removed
+func buildCart(items []models.CartItem, cat *catalog.Catalog) *models.Cart {
+	cart := &models.Cart{}
+	for _, stored := range items {
+		line := models.CartLine{PriceID: stored.PriceID, Quantity: stored.Quantity, Label: stored.PriceID}
+
+		if item, ok := cat.LookupItemPrice(stored.PriceID); ok {
+			line.ItemName = item.Name
+			line.Label = item.Label
+			line.Amount = item.Amount
+			line.Currency = item.Currency
+			line.Subtotal = item.Amount * int64(stored.Quantity)
+			line.Available = cart.Currency == "" || cart.Currency == item.Currency
+		}
+
+		if line.Available {
+			cart.Currency = line.Currency
+			cart.Total += line.Subtotal
+			cart.Count += line.Quantity
+		}
+		cart.Lines = append(cart.Lines, line)
+	}
+	return cart
+}
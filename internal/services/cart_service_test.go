@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
+)
+
+func TestBuildCart(t *testing.T) {
+	cat, err := catalog.New(nil, []catalog.Item{
+		{Name: "stickers", Label: "Sticker pack", PriceID: "price_stickers", Amount: 500, Currency: "usd"},
+		{Name: "mug", Label: "Mug", PriceID: "price_mug", Amount: 1500, Currency: "usd"},
+		{Name: "poster", Label: "Poster", PriceID: "price_poster", Amount: 2000, Currency: "eur"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cart := buildCart([]models.CartItem{
+		{PriceID: "price_stickers", Quantity: 3},
+		{PriceID: "price_retired", Quantity: 1},
+		{PriceID: "price_mug", Quantity: 1},
+		{PriceID: "price_poster", Quantity: 1},
+	}, cat)
+
+	if len(cart.Lines) != 4 {
+		t.Fatalf("Expected every stored line to be listed, got %d", len(cart.Lines))
+	}
+	if cart.Total != 3*500+1500 || cart.Currency != "usd" || cart.Count != 4 {
+		t.Errorf("Expected 4 usd items totalling 3000, got %d %s items totalling %d", cart.Count, cart.Currency, cart.Total)
+	}
+	if cart.Lines[0].Label != "Sticker pack" || cart.Lines[0].Subtotal != 1500 || !cart.Lines[0].Available {
+		t.Errorf("Unexpected sticker line: %+v", cart.Lines[0])
+	}
+	if cart.Lines[1].Available {
+		t.Error("Expected an item that left the catalog to be unavailable")
+	}
+	if cart.Lines[3].Available {
+		t.Error("Expected an item in another currency to be unavailable")
+	}
+}
+
+func TestBuildCartEmpty(t *testing.T) {
+	cart := buildCart(nil, nil)
+	if len(cart.Lines) != 0 || cart.Total != 0 || cart.Currency != "" {
+		t.Errorf("Expected an empty cart, got %+v", cart)
+	}
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/repositories"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
+	"github.com/dracondev/go-templ-htmx-ex/libs/cachex"
+	"github.com/google/uuid"
+)
+
+const (
+	// entitlementCacheTTL bounds how stale a plan check can be on replicas that
+	// did not apply the change themselves
+	entitlementCacheTTL = time.Minute
+	// subscriptionSyncInterval is how often EnsureSynced asks the payment service
+	subscriptionSyncInterval = 10 * time.Minute
+	// maxCachedUsers bounds the per-user caches
+	maxCachedUsers = 10000
+)
+
+// Entitlements is what a user's subscriptions currently grant
+type Entitlements struct {
+	Plan     string   `json:"plan"`
+	Features []string `json:"features"`
+	// Subscription is the one granting the plan; nil on the free plan
+	Subscription *models.Subscription `json:"subscription,omitempty"`
+	// Latest is the most recently updated subscription to a known product, whether
+	// or not it grants access (e.g. past_due or canceled); nil if there never was one
+	Latest *models.Subscription `json:"latest,omitempty"`
+	// LatestPlan is the catalog plan of Latest
+	LatestPlan string `json:"latest_plan,omitempty"`
+
+	catalog *catalog.Catalog // ranks plans for HasPlan
+}
+
+// Has reports whether a feature is granted
+func (e *Entitlements) Has(feature string) bool {
+	for _, f := range e.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPlan reports whether the plan is the given one or ranks higher in the catalog
+func (e *Entitlements) HasPlan(plan string) bool {
+	return e.catalog.Grants(e.Plan, plan)
+}
+
+// EntitlementService answers plan and feature checks from the local subscriptions
+// table, which it keeps in sync with the payment service
+type EntitlementService struct {
+	subRepo       *repositories.SubscriptionRepository
+	userRepo      *repositories.UserRepository
+	paymentClient *paymentms.Client
+	catalog       *catalog.Catalog  // plan ranks and the features each plan grants
+	productPlans  map[string]string // payment service product ID -> plan
+
+	cache  *cachex.Cache[*Entitlements]
+	synced *cachex.Cache[time.Time]
+}
+
+// NewEntitlementService creates a new entitlement service.
+// productPlans maps payment service product IDs to the catalog plan they grant.
+func NewEntitlementService(subRepo *repositories.SubscriptionRepository, userRepo *repositories.UserRepository, paymentClient *paymentms.Client, cat *catalog.Catalog, productPlans map[string]string) *EntitlementService {
+	return &EntitlementService{
+		subRepo:       subRepo,
+		userRepo:      userRepo,
+		paymentClient: paymentClient,
+		catalog:       cat,
+		productPlans:  productPlans,
+		cache:         cachex.NewLRU[*Entitlements](entitlementCacheTTL, maxCachedUsers),
+		synced:        cachex.NewLRU[time.Time](subscriptionSyncInterval, maxCachedUsers),
+	}
+}
+
+// Entitlements returns the plan and features of a local user
+func (s *EntitlementService) Entitlements(ctx context.Context, userID string) (*Entitlements, error) {
+	return s.cache.GetOrLoad(userID, func() (*Entitlements, error) {
+		subs, err := s.subRepo.GetUserSubscriptions(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return entitlementsFor(subs, s.productPlans, s.catalog), nil
+	})
+}
+
+// HasPlan reports whether the user is on the plan or a higher one
+func (s *EntitlementService) HasPlan(ctx context.Context, userID, plan string) (bool, error) {
+	entitlements, err := s.Entitlements(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return entitlements.HasPlan(plan), nil
+}
+
+// HasEntitlement reports whether the user's plan grants a feature
+func (s *EntitlementService) HasEntitlement(ctx context.Context, userID, feature string) (bool, error) {
+	entitlements, err := s.Entitlements(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return entitlements.Has(feature), nil
+}
+
+// ApplySubscription stores a subscription change for a local user
+func (s *EntitlementService) ApplySubscription(ctx context.Context, sub models.Subscription) error {
+	if _, err := s.subRepo.UpsertSubscription(ctx, sub); err != nil {
+		if !errors.Is(err, models.ErrStaleSubscription) {
+			return err
+		}
+		// A newer state is already stored, e.g. a delayed event after a cancellation
+		fmt.Printf("⚠️ ENTITLEMENTS: Skipped out-of-date state for subscription %s\n", sub.SubscriptionID)
+	}
+	s.cache.Delete(sub.UserID)
+	return nil
+}
+
+// ApplyWebhookEvent projects a subscription event onto the local table.
+// Events for users this app doesn't know are ignored.
+func (s *EntitlementService) ApplyWebhookEvent(ctx context.Context, event *paymentms.WebhookEvent) error {
+	if event.Data.SubscriptionID == "" {
+		return fmt.Errorf("subscription event %s has no subscription_id", event.ID)
+	}
+
+	user, err := s.ResolveUser(ctx, event.Data.UserID, event.Data.Email)
+	if err != nil {
+		if errors.Is(err, models.ErrDatabaseNotConnected) {
+			return err
+		}
+		fmt.Printf("⚠️ ENTITLEMENTS: No local user for subscription %s (%s): %v\n", event.Data.SubscriptionID, event.Data.UserID, err)
+		return nil
+	}
+
+	status := event.Data.Status
+	if event.Type == paymentms.EventSubscriptionCancelled && status == "" {
+		status = models.SubscriptionStatusCanceled
+	}
+
+	return s.ApplySubscription(ctx, models.Subscription{
+		UserID:            user.ID,
+		SubscriptionID:    event.Data.SubscriptionID,
+		ProductID:         event.Data.ProductID,
+		PriceID:           event.Data.PriceID,
+		Status:            status,
+		CurrentPeriodEnd:  event.Data.CurrentPeriodEnd,
+		CancelAtPeriodEnd: event.Data.CancelAtPeriodEnd,
+		TrialEnd:          event.Data.TrialEnd,
+		StateAt:           eventTime(event),
+	})
+}
+
+// eventTime is when the payment service created the event; zero when it did not say
+func eventTime(event *paymentms.WebhookEvent) time.Time {
+	if event.Created <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(event.Created, 0)
+}
+
+// ResolveUser finds the local user a payment service user ID refers to.
+// Checkouts send the local user ID; older ones sent the email.
+func (s *EntitlementService) ResolveUser(ctx context.Context, paymentUserID, email string) (*models.User, error) {
+	if _, err := uuid.Parse(paymentUserID); err == nil {
+		return s.userRepo.GetUserByID(ctx, paymentUserID)
+	}
+	if paymentUserID != "" {
+		email = paymentUserID
+	}
+	if email == "" {
+		return nil, errors.New("event has no user_id or email")
+	}
+	return s.userRepo.GetUserByEmail(ctx, email)
+}
+
+// SyncUser fetches the user's subscriptions from the payment service and stores them
+func (s *EntitlementService) SyncUser(ctx context.Context, user *models.User) error {
+	for productID := range s.productPlans {
+		status, err := s.fetchStatus(ctx, user, productID)
+		if errors.Is(err, paymentms.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if status.SubscriptionID == "" {
+			continue
+		}
+
+		if status.ProductID == "" {
+			status.ProductID = productID
+		}
+		if err := s.ApplySubscription(ctx, models.Subscription{
+			UserID:            user.ID,
+			SubscriptionID:    status.SubscriptionID,
+			ProductID:         status.ProductID,
+			PriceID:           status.PriceID,
+			Status:            status.Status,
+			CurrentPeriodEnd:  status.CurrentPeriodEnd,
+			CancelAtPeriodEnd: status.CancelAtPeriodEnd,
+			TrialEnd:          status.TrialEnd,
+		}); err != nil {
+			return err
+		}
+	}
+
+	s.synced.Set(user.ID, time.Now())
+	return nil
+}
+
+// EnsureSynced syncs the user unless this process did so recently, so
+// subscriptions made before webhooks were delivered still show up.
+// Failures are logged; the local table is used as-is.
+func (s *EntitlementService) EnsureSynced(ctx context.Context, user *models.User) {
+	if _, ok := s.synced.Get(user.ID); ok {
+		return
+	}
+	if err := s.SyncUser(ctx, user); err != nil {
+		fmt.Printf("⚠️ ENTITLEMENTS: Failed to sync subscriptions for %s: %v\n", user.ID, err)
+	}
+}
+
+// ProjectSubscriptions lists the user's subscriptions in every project sharing the
+// payment service, this app's project first
+func (s *EntitlementService) ProjectSubscriptions(ctx context.Context, user *models.User) ([]models.ProjectSubscription, error) {
+	subs, err := s.paymentClient.ListSubscriptions(ctx, user.ID)
+	if (errors.Is(err, paymentms.ErrNotFound) || (err == nil && len(subs) == 0)) && user.Email != "" {
+		// Older checkouts were keyed by email
+		subs, err = s.paymentClient.ListSubscriptions(ctx, user.Email)
+	}
+	if errors.Is(err, paymentms.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return projectSubscriptions(subs, s.paymentClient.ProjectID()), nil
+}
+
+// TrialEligible reports whether the user may start a free trial: only users who
+// never had a subscription to one of this app's products may
+func (s *EntitlementService) TrialEligible(ctx context.Context, user *models.User) (bool, error) {
+	// Subscriptions from before webhooks were delivered count too
+	s.EnsureSynced(ctx, user)
+
+	subs, err := s.subRepo.GetUserSubscriptions(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	return !hadSubscription(subs, s.productPlans), nil
+}
+
+// Invalidate drops the cached entitlements of a user
+func (s *EntitlementService) Invalidate(userID string) {
+	s.cache.Delete(userID)
+}
+
+// fetchStatus looks the subscription up by local user ID, then by email for older checkouts
+func (s *EntitlementService) fetchStatus(ctx context.Context, user *models.User, productID string) (*paymentms.SubscriptionStatusResponse, error) {
+	status, err := s.paymentClient.GetSubscriptionStatus(ctx, user.ID, productID)
+	if errors.Is(err, paymentms.ErrNotFound) && user.Email != "" {
+		return s.paymentClient.GetSubscriptionStatus(ctx, user.Email, productID)
+	}
+	return status, err
+}
+
+// entitlementsFor picks the highest ranked plan granted by the subscriptions,
+// which are ordered most recently updated first, with the catalog's features for it
+func entitlementsFor(subs []models.Subscription, productPlans map[string]string, cat *catalog.Catalog) *Entitlements {
+	best := &Entitlements{Plan: models.PlanFree, catalog: cat}
+	for i := range subs {
+		plan, known := productPlans[subs[i].ProductID]
+		if known && best.Latest == nil {
+			best.Latest = &subs[i]
+			best.LatestPlan = plan
+		}
+		if !known || !subs[i].GrantsAccess() {
+			continue
+		}
+		if best.Subscription == nil || !cat.Grants(best.Plan, plan) {
+			best.Plan = plan
+			best.Subscription = &subs[i]
+		}
+	}
+	if plan, ok := cat.Plan(best.Plan); ok {
+		best.Features = plan.Entitlements
+	}
+	return best
+}
+
+// hadSubscription reports whether any of the subscriptions, past or present, is to a known product
+func hadSubscription(subs []models.Subscription, productPlans map[string]string) bool {
+	for _, sub := range subs {
+		if _, known := productPlans[sub.ProductID]; known {
+			return true
+		}
+	}
+	return false
+}
+
+// projectSubscriptions converts and orders subscriptions: the current project
+// first, then by project. Subscriptions without a project predate multi-project
+// support and belong to the current one.
+func projectSubscriptions(subs []paymentms.UserSubscription, currentProject string) []models.ProjectSubscription {
+	result := make([]models.ProjectSubscription, len(subs))
+	for i, sub := range subs {
+		project := sub.ProjectID
+		if project == "" {
+			project = currentProject
+		}
+		result[i] = models.ProjectSubscription{
+			ProjectID:        project,
+			SubscriptionID:   sub.SubscriptionID,
+			ProductID:        sub.ProductID,
+			Status:           sub.Status,
+			CurrentPeriodEnd: sub.CurrentPeriodEnd,
+			Current:          project == currentProject,
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Current != result[j].Current {
+			return result[i].Current
+		}
+		return result[i].ProjectID < result[j].ProjectID
+	})
+	return result
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/repositories"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
+)
+
+// testCatalog is the built-in catalog with a pro product
+func testCatalog(t *testing.T) *catalog.Catalog {
+	t.Helper()
+	cat, err := catalog.New(catalog.Defaults("prod_pro", "price_month", "price_year"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cat
+}
+
+func TestEntitlementsFor(t *testing.T) {
+	plans := map[string]string{"prod_pro": models.PlanPro}
+	cat := testCatalog(t)
+
+	free := entitlementsFor(nil, plans, cat)
+	if free.Plan != models.PlanFree || free.Subscription != nil || free.Has(models.FeatureAPIAccess) {
+		t.Errorf("Expected the free plan without features, got %+v", free)
+	}
+
+	subs := []models.Subscription{
+		{SubscriptionID: "sub_old", ProductID: "prod_pro", Status: models.SubscriptionStatusCanceled},
+		{SubscriptionID: "sub_other", ProductID: "prod_unknown", Status: models.SubscriptionStatusActive},
+		{SubscriptionID: "sub_new", ProductID: "prod_pro", Status: models.SubscriptionStatusTrialing},
+	}
+	pro := entitlementsFor(subs, plans, cat)
+	if pro.Plan != models.PlanPro {
+		t.Fatalf("Expected the pro plan, got %s", pro.Plan)
+	}
+	if pro.Subscription == nil || pro.Subscription.SubscriptionID != "sub_new" {
+		t.Errorf("Expected sub_new to grant the plan, got %+v", pro.Subscription)
+	}
+	if !pro.Has(models.FeatureAPIAccess) {
+		t.Error("Expected the pro plan to grant API access")
+	}
+	if !pro.HasPlan(models.PlanFree) || pro.HasPlan("enterprise") {
+		t.Error("Expected pro to satisfy free checks but not enterprise ones")
+	}
+}
+
+func TestEntitlementsForCatalogPlans(t *testing.T) {
+	cat, err := catalog.New([]catalog.Plan{
+		{Name: "free"},
+		{Name: "pro", ProductID: "prod_pro", Entitlements: []string{models.FeatureAPIAccess}},
+		{Name: "team", ProductID: "prod_team", Entitlements: []string{models.FeatureAPIAccess, "seats"}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subs := []models.Subscription{
+		{SubscriptionID: "sub_pro", ProductID: "prod_pro", Status: models.SubscriptionStatusActive},
+		{SubscriptionID: "sub_team", ProductID: "prod_team", Status: models.SubscriptionStatusActive},
+	}
+
+	got := entitlementsFor(subs, cat.ProductPlans(), cat)
+	if got.Plan != "team" || got.Subscription.SubscriptionID != "sub_team" {
+		t.Fatalf("Expected the higher ranked team plan, got %s from %+v", got.Plan, got.Subscription)
+	}
+	if !got.Has("seats") || !got.HasPlan(models.PlanPro) {
+		t.Errorf("Expected team's features and to satisfy pro checks, got %+v", got.Features)
+	}
+}
+
+func TestEntitlementsCanceledSubscriptionIsFree(t *testing.T) {
+	plans := map[string]string{"prod_pro": models.PlanPro}
+	subs := []models.Subscription{
+		{SubscriptionID: "sub_1", ProductID: "prod_pro", Status: models.SubscriptionStatusPastDue},
+	}
+
+	got := entitlementsFor(subs, plans, testCatalog(t))
+	if got.Plan != models.PlanFree {
+		t.Errorf("Expected past_due to grant no plan, got %s", got.Plan)
+	}
+	if got.Latest == nil || got.Latest.Status != models.SubscriptionStatusPastDue || got.LatestPlan != models.PlanPro {
+		t.Errorf("Expected the past_due pro subscription as the latest, got %s %+v", got.LatestPlan, got.Latest)
+	}
+}
+
+func TestHadSubscription(t *testing.T) {
+	plans := map[string]string{"prod_pro": models.PlanPro}
+
+	if hadSubscription(nil, plans) {
+		t.Error("Expected a user without subscriptions to be eligible for a trial")
+	}
+	if hadSubscription([]models.Subscription{{ProductID: "prod_other_app"}}, plans) {
+		t.Error("Expected subscriptions to unknown products not to count")
+	}
+	if !hadSubscription([]models.Subscription{{ProductID: "prod_pro", Status: models.SubscriptionStatusCanceled}}, plans) {
+		t.Error("Expected a canceled subscription to count")
+	}
+}
+
+func TestEventTime(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	if got := eventTime(&paymentms.WebhookEvent{Created: created.Unix()}); !got.Equal(created) {
+		t.Errorf("Expected %v, got %v", created, got)
+	}
+	// Events without a creation time apply like a sync
+	if got := eventTime(&paymentms.WebhookEvent{}); !got.IsZero() {
+		t.Errorf("Expected zero time without created, got %v", got)
+	}
+}
+
+func TestEntitlementServiceWithoutDatabase(t *testing.T) {
+	svc := NewEntitlementService(repositories.NewSubscriptionRepository(nil), repositories.NewUserRepository(nil), nil, nil, nil)
+
+	if _, err := svc.HasPlan(context.Background(), "6f1c2b9e-2c1a-4b8e-9a43-1f0e2d3c4b5a", models.PlanPro); !errors.Is(err, models.ErrDatabaseNotConnected) {
+		t.Errorf("Expected ErrDatabaseNotConnected, got %v", err)
+	}
+}
+
+func TestSyncUserFallsBackToEmail(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path != "/api/v1/subscriptions/user@example.com/prod_pro" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(paymentms.SubscriptionStatusResponse{
+			SubscriptionID:   "sub_1",
+			Status:           "active",
+			CurrentPeriodEnd: time.Now().Add(24 * time.Hour),
+		})
+	}))
+	defer server.Close()
+
+	svc := NewEntitlementService(
+		repositories.NewSubscriptionRepository(nil),
+		repositories.NewUserRepository(nil),
+		paymentms.New(server.URL, "test-key"),
+		testCatalog(t),
+		map[string]string{"prod_pro": models.PlanPro},
+	)
+
+	user := &models.User{ID: "6f1c2b9e-2c1a-4b8e-9a43-1f0e2d3c4b5a", Email: "user@example.com"}
+	err := svc.SyncUser(context.Background(), user)
+
+	// The subscription was found by email; storing it fails without a database
+	if !errors.Is(err, models.ErrDatabaseNotConnected) {
+		t.Errorf("Expected ErrDatabaseNotConnected when storing, got %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "/api/v1/subscriptions/"+user.ID+"/prod_pro" {
+		t.Errorf("Expected a lookup by user ID then by email, got %v", paths)
+	}
+}
+
+func TestProjectSubscriptions(t *testing.T) {
+	subs := projectSubscriptions([]paymentms.UserSubscription{
+		{ProjectID: "analytics_dashboard", SubscriptionID: "sub_b", Status: models.SubscriptionStatusActive},
+		{ProjectID: "", SubscriptionID: "sub_legacy", Status: models.SubscriptionStatusCanceled},
+		{ProjectID: "billing_tool", SubscriptionID: "sub_c", Status: models.SubscriptionStatusTrialing},
+		{ProjectID: "startup_platform", SubscriptionID: "sub_a", Status: models.SubscriptionStatusActive},
+	}, "startup_platform")
+
+	order := []string{"sub_legacy", "sub_a", "sub_b", "sub_c"}
+	for i, id := range order {
+		if subs[i].SubscriptionID != id {
+			t.Fatalf("Expected order %v, got %+v", order, subs)
+		}
+	}
+	if !subs[0].Current || subs[0].ProjectID != "startup_platform" {
+		t.Errorf("Expected a subscription without project to belong to the current one, got %+v", subs[0])
+	}
+	if subs[2].Current {
+		t.Errorf("Expected other projects not to be current, got %+v", subs[2])
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/repositories"
+)
+
+// DeviceInfo describes the client a session was established from
+type DeviceInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// DeviceFromRequest extracts the user agent and originating IP. X-Forwarded-For
+// is only believed when the request comes from one of the trusted proxies; the
+// client is then the rightmost forwarded address that is not itself a proxy.
+func DeviceFromRequest(r *http.Request, trustedProxies []*net.IPNet) DeviceInfo {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+
+	if isTrustedProxy(ip, trustedProxies) {
+		hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			ip = hop
+			if !isTrustedProxy(hop, trustedProxies) {
+				break
+			}
+		}
+	}
+
+	return DeviceInfo{
+		UserAgent: r.UserAgent(),
+		IPAddress: ip,
+	}
+}
+
+// isTrustedProxy reports whether ip is inside one of the trusted proxy networks
+func isTrustedProxy(ip string, trustedProxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoginService completes logins: auth code exchange, local user sync and device recording
+type LoginService struct {
+	authService *AuthService
+	userRepo    *repositories.UserRepository
+	sessionRepo *repositories.SessionRepository
+	roles       *RoleService
+}
+
+// NewLoginService creates a new login service
+func NewLoginService(authService *AuthService, userRepo *repositories.UserRepository, sessionRepo *repositories.SessionRepository, roles *RoleService) *LoginService {
+	return &LoginService{
+		authService: authService,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		roles:       roles,
+	}
+}
+
+// CompleteLogin exchanges an authorization code for a session and syncs the user locally.
+// Sync failures are logged but never block the login.
+func (s *LoginService) CompleteLogin(ctx context.Context, authCode string, device DeviceInfo) (string, error) {
+	session, err := s.authService.CreateSession(ctx, authCode)
+	if err != nil {
+		return "", err
+	}
+
+	// The create response normally carries the user; fall back to a refresh if not
+	userContext := toUserSessionContext(session.UserContext)
+	if !session.HasUser() {
+		if userContext, err = s.authService.GetUserInfo(ctx, session.SessionID); err != nil {
+			fmt.Printf("🔐 LOGIN-SERVICE: ⚠️ Could not fetch user info to record session: %v\n", err)
+			return session.SessionID, nil
+		}
+	}
+
+	s.SyncSession(ctx, session.SessionID, userContext, device)
+	return session.SessionID, nil
+}
+
+// SyncSession upserts the user into the local DB and records the device the
+// session was established from. Failures are logged but never block login.
+func (s *LoginService) SyncSession(ctx context.Context, sessionID string, userContext *models.UserSessionContext, device DeviceInfo) {
+	if s.userRepo == nil {
+		return
+	}
+
+	user, err := s.userRepo.UpsertUser(ctx, &models.User{
+		AuthID:  userContext.UserID,
+		Email:   userContext.Email,
+		Name:    userContext.Name,
+		Picture: userContext.Picture,
+		IsAdmin: false, // Default to false, admin can update later
+	})
+	if err != nil {
+		fmt.Printf("⚠️ SESSION: Failed to sync user to local DB: %v\n", err)
+		return
+	}
+	fmt.Printf("✅ SESSION: Synced user %s to local DB\n", user.Email)
+
+	// Users promoted through is_admin (ADMIN_EMAIL or by hand) get the admin role
+	// without waiting for the next restart
+	if s.roles != nil {
+		if err := s.roles.SyncAdminRole(ctx, user); err != nil {
+			fmt.Printf("⚠️ SESSION: Failed to sync admin role for %s: %v\n", user.Email, err)
+		}
+	}
+
+	if s.sessionRepo == nil {
+		return
+	}
+	if _, err := s.sessionRepo.RecordSession(ctx, user.ID, sessionID, device.UserAgent, device.IPAddress); err != nil {
+		fmt.Printf("⚠️ SESSION: Failed to record session: %v\n", err)
+	}
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeviceFromRequest(t *testing.T) {
+	_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trusted := []*net.IPNet{proxies}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		trusted    []*net.IPNet
+		want       string
+	}{
+		{"direct", "203.0.113.7:51234", "", trusted, "203.0.113.7"},
+		{"spoofed_without_proxy", "203.0.113.7:51234", "198.51.100.1", trusted, "203.0.113.7"},
+		{"no_trusted_proxies", "10.0.0.2:443", "198.51.100.1", nil, "10.0.0.2"},
+		{"through_proxy", "10.0.0.2:443", "198.51.100.1", trusted, "198.51.100.1"},
+		{"client_prepended_hop", "10.0.0.2:443", "192.0.2.99, 198.51.100.1, 10.0.0.5", trusted, "198.51.100.1"},
+		{"garbage_hop", "10.0.0.2:443", "not-an-ip", trusted, "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/auth/callback", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if got := DeviceFromRequest(req, tt.trusted).IPAddress; got != tt.want {
+				t.Errorf("Expected IP %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
+)
+
+// maxPromotionCodeLength bounds codes before they are sent to the payment service
+const maxPromotionCodeLength = 64
+
+// ErrInvalidPromotionCode is returned for codes that are malformed, unknown or do not apply to the price
+var ErrInvalidPromotionCode = errors.New("promotion code is not valid")
+
+// PromotionQuote is a plan price with a promotion code applied
+type PromotionQuote struct {
+	PlanOption
+	Promotion paymentms.PromotionCode
+	// Applies is false when the code is valid but not for this plan; Reason says why
+	Applies    bool
+	Reason     string
+	Discounted int64 // Price amount after the discount, in minor units
+}
+
+// PromotionService validates promotion codes against catalog prices at the payment service
+type PromotionService struct {
+	paymentClient *paymentms.Client
+	catalog       *catalog.Catalog
+}
+
+// NewPromotionService creates a new promotion service
+func NewPromotionService(paymentClient *paymentms.Client, catalog *catalog.Catalog) *PromotionService {
+	return &PromotionService{
+		paymentClient: paymentClient,
+		catalog:       catalog,
+	}
+}
+
+// Quote applies the code to every plan purchasable for the interval. It returns
+// ErrInvalidPromotionCode when the payment service does not know the code.
+// user is nil for visitors who are not signed in; restrictions tied to the
+// customer are then left to Check at checkout.
+func (s *PromotionService) Quote(ctx context.Context, user *models.User, code, interval string) ([]PromotionQuote, error) {
+	code, ok := NormalizePromotionCode(code)
+	if !ok {
+		return nil, ErrInvalidPromotionCode
+	}
+
+	var quotes []PromotionQuote
+	for _, plan := range s.catalog.Plans() {
+		if !plan.Purchasable(interval) {
+			continue
+		}
+		price := *plan.Price(interval)
+
+		result, err := s.validate(ctx, user, code, plan, price)
+		if err != nil {
+			return nil, err
+		}
+		quote := PromotionQuote{
+			PlanOption: PlanOption{Plan: plan, Price: price},
+			Promotion:  result.PromotionCode,
+			Applies:    result.Valid,
+			Reason:     result.Reason,
+			Discounted: price.Amount,
+		}
+		if result.Valid {
+			quote.Discounted = result.Apply(price.Amount, price.Currency)
+		}
+		quotes = append(quotes, quote)
+	}
+	return quotes, nil
+}
+
+// Check returns the code's promotion when it applies to the plan's price, or
+// an error matching ErrInvalidPromotionCode when it does not
+func (s *PromotionService) Check(ctx context.Context, user *models.User, code string, plan catalog.Plan, price catalog.Price) (*paymentms.PromotionCode, error) {
+	code, ok := NormalizePromotionCode(code)
+	if !ok {
+		return nil, ErrInvalidPromotionCode
+	}
+
+	result, err := s.validate(ctx, user, code, plan, price)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Valid {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPromotionCode, result.Reason)
+	}
+	return &result.PromotionCode, nil
+}
+
+// validate asks the payment service about the code, mapping unknown codes to ErrInvalidPromotionCode
+func (s *PromotionService) validate(ctx context.Context, user *models.User, code string, plan catalog.Plan, price catalog.Price) (*paymentms.PromotionCodeValidation, error) {
+	req := paymentms.PromotionCodeRequest{
+		Code:      code,
+		PriceID:   price.ID,
+		ProductID: plan.ProductID,
+	}
+	if user != nil {
+		req.UserID = user.ID
+	}
+	result, err := s.paymentClient.ValidatePromotionCode(ctx, req)
+	if errors.Is(err, paymentms.ErrNotFound) || errors.Is(err, paymentms.ErrValidation) {
+		return nil, ErrInvalidPromotionCode
+	}
+	return result, err
+}
+
+// PromotionTerms describes a discount, e.g. "20% off for 3 months" or "$5 off the first payment"
+func PromotionTerms(promo paymentms.PromotionCode) string {
+	discount := catalog.FormatAmount(promo.AmountOff, promo.Currency) + " off"
+	if promo.PercentOff > 0 {
+		discount = strconv.FormatFloat(promo.PercentOff, 'f', -1, 64) + "% off"
+	}
+
+	switch promo.Duration {
+	case "forever":
+		return discount + " forever"
+	case "repeating":
+		if promo.DurationInMonths == 1 {
+			return discount + " for 1 month"
+		}
+		return fmt.Sprintf("%s for %d months", discount, promo.DurationInMonths)
+	default:
+		return discount + " the first payment"
+	}
+}
+
+// NormalizePromotionCode trims and upper-cases a code as typed by the user;
+// false when it is empty or could not be a promotion code
+func NormalizePromotionCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" || len(code) > maxPromotionCodeLength {
+		return "", false
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return "", false
+		}
+	}
+	return code, true
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
+)
+
+func TestNormalizePromotionCode(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{" launch20 ", "LAUNCH20", true},
+		{"spring-sale_2026", "SPRING-SALE_2026", true},
+		{"", "", false},
+		{"   ", "", false},
+		{"HALF OFF", "", false},
+		{"<script>", "", false},
+		{strings.Repeat("A", maxPromotionCodeLength+1), "", false},
+	}
+	for _, tt := range tests {
+		got, ok := NormalizePromotionCode(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("NormalizePromotionCode(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPromotionTerms(t *testing.T) {
+	tests := []struct {
+		promo paymentms.PromotionCode
+		want  string
+	}{
+		{paymentms.PromotionCode{PercentOff: 20, Duration: "repeating", DurationInMonths: 3}, "20% off for 3 months"},
+		{paymentms.PromotionCode{PercentOff: 12.5, Duration: "forever"}, "12.5% off forever"},
+		{paymentms.PromotionCode{AmountOff: 500, Currency: "usd", Duration: "once"}, "$5 off the first payment"},
+		{paymentms.PromotionCode{AmountOff: 250, Currency: "eur", Duration: "repeating", DurationInMonths: 1}, "€2.50 off for 1 month"},
+	}
+	for _, tt := range tests {
+		if got := PromotionTerms(tt.promo); got != tt.want {
+			t.Errorf("PromotionTerms(%+v) = %q, want %q", tt.promo, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteWithoutUser(t *testing.T) {
+	var requests []paymentms.PromotionCodeRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req paymentms.PromotionCodeRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+		_ = json.NewEncoder(w).Encode(paymentms.PromotionCodeValidation{
+			PromotionCode: paymentms.PromotionCode{Code: req.Code, PercentOff: 50, Duration: "once"},
+			Valid:         true,
+		})
+	}))
+	defer server.Close()
+
+	cat, err := catalog.New(catalog.Defaults("prod_pro", "price_month", "price_year"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewPromotionService(paymentms.New(server.URL, "test-key"), cat)
+
+	quotes, err := svc.Quote(context.Background(), nil, "launch50", catalog.IntervalMonth)
+	if err != nil {
+		t.Fatalf("Quote returned error: %v", err)
+	}
+	if len(quotes) != 1 || !quotes[0].Applies || quotes[0].Discounted != 1450 {
+		t.Errorf("Expected pro at half price, got %+v", quotes)
+	}
+	if len(requests) != 1 || requests[0].UserID != "" || requests[0].Code != "LAUNCH50" {
+		t.Errorf("Expected one validation without a user, got %+v", requests)
+	}
+}
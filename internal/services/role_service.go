@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	dbSqlc "github.com/DraconDev/go-templ-htmx-ex/database/sqlc"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/repositories"
+	"github.com/dracondev/go-templ-htmx-ex/libs/cachex"
+	"github.com/google/uuid"
+)
+
+const (
+	// permissionCacheTTL bounds how long a role change takes to reach replicas
+	// that did not make it themselves, unless they share role changes through
+	// ShareRoleChanges
+	permissionCacheTTL = time.Minute
+	// rolesChangedKey holds when roles last changed in the shared backend
+	rolesChangedKey = "roles-changed"
+)
+
+// cachedPermissions is a user's permissions and when they were loaded
+type cachedPermissions struct {
+	Permissions []string
+	LoadedAt    time.Time
+}
+
+// ErrAdminRoleFromFlag is returned when removing the admin role from a user
+// flagged is_admin, who gets it back at the next login
+var ErrAdminRoleFromFlag = errors.New("admin role is granted by the user's is_admin flag")
+
+// RoleService provides role and permission business logic
+type RoleService struct {
+	roleRepo    *repositories.RoleRepository
+	userRepo    *repositories.UserRepository
+	permissions *cachex.Cache[cachedPermissions] // by email
+
+	// rolesChanged records when any role assignment last changed. Permissions
+	// loaded before then are reloaded, which reaches other replicas sharing the backend.
+	rolesChanged cachex.Backend[time.Time]
+}
+
+// NewRoleService creates a new role service
+func NewRoleService(queries *dbSqlc.Queries) *RoleService {
+	return &RoleService{
+		roleRepo:    repositories.NewRoleRepository(queries),
+		userRepo:    repositories.NewUserRepository(queries),
+		permissions: cachex.NewLRU[cachedPermissions](permissionCacheTTL, maxCachedUsers),
+
+		rolesChanged: cachex.NewLRU[time.Time](permissionCacheTTL, 1),
+	}
+}
+
+// ShareRoleChanges records role changes in a backend shared between replicas,
+// so a revoked permission stops working everywhere on the next check
+func (s *RoleService) ShareRoleChanges(backend cachex.Backend[time.Time]) {
+	s.rolesChanged = backend
+}
+
+// UserPermissions returns the permissions a user's roles grant, cached per user
+func (s *RoleService) UserPermissions(ctx context.Context, email string) ([]string, error) {
+	if cached, ok := s.permissions.Get(email); ok {
+		if changedAt, changed := s.rolesChanged.Get(rolesChangedKey); changed && !cached.LoadedAt.After(changedAt) {
+			s.permissions.Delete(email)
+		}
+	}
+
+	cached, err := s.permissions.GetOrLoad(email, func() (cachedPermissions, error) {
+		loadedAt := time.Now()
+		permissions, err := s.roleRepo.GetUserPermissionsByEmail(ctx, email)
+		return cachedPermissions{Permissions: permissions, LoadedAt: loadedAt}, err
+	})
+	return cached.Permissions, err
+}
+
+// roleChanged drops cached permissions here and on replicas sharing role changes
+func (s *RoleService) roleChanged() {
+	s.rolesChanged.SetWithTTL(rolesChangedKey, time.Now(), permissionCacheTTL)
+	// Cached permissions are keyed by email; role changes are rare enough to drop them all
+	s.permissions.Clear()
+}
+
+// SyncAdminRole gives a user flagged is_admin the admin role used for permission checks
+func (s *RoleService) SyncAdminRole(ctx context.Context, user *models.User) error {
+	if !user.IsAdmin {
+		return nil
+	}
+	id, err := uuid.Parse(user.ID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+	if err := s.roleRepo.AssignRole(ctx, id, models.RoleAdmin); err != nil {
+		return err
+	}
+	s.permissions.Delete(user.Email)
+	return nil
+}
+
+// GetAllRoles retrieves every defined role
+func (s *RoleService) GetAllRoles(ctx context.Context) ([]models.Role, error) {
+	return s.roleRepo.GetAllRoles(ctx)
+}
+
+// GetUserRoles retrieves the roles assigned to a user
+func (s *RoleService) GetUserRoles(ctx context.Context, userID string) ([]models.Role, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	return s.roleRepo.GetUserRoles(ctx, id)
+}
+
+// AssignRole grants a role to a user
+func (s *RoleService) AssignRole(ctx context.Context, userID, roleName string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+	if err := s.roleRepo.AssignRole(ctx, id, roleName); err != nil {
+		return err
+	}
+	s.roleChanged()
+	return nil
+}
+
+// RemoveRole revokes a role from a user. The admin role of a user flagged
+// is_admin cannot be removed: is_admin is its source, so clear the flag instead.
+func (s *RoleService) RemoveRole(ctx context.Context, userID, roleName string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+	if roleName == models.RoleAdmin {
+		user, err := s.userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user.IsAdmin {
+			return ErrAdminRoleFromFlag
+		}
+	}
+	if err := s.roleRepo.RemoveRole(ctx, id, roleName); err != nil {
+		return err
+	}
+	s.roleChanged()
+	return nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/dracondev/go-templ-htmx-ex/libs/cachex"
+)
+
+func TestUserPermissionsCache(t *testing.T) {
+	svc := NewRoleService(nil)
+	ctx := context.Background()
+
+	// Load failures are not cached
+	if _, err := svc.UserPermissions(ctx, "admin@example.com"); !errors.Is(err, models.ErrDatabaseNotConnected) {
+		t.Errorf("Expected ErrDatabaseNotConnected, got %v", err)
+	}
+
+	svc.permissions.Set("admin@example.com", cachedPermissions{Permissions: []string{models.PermissionAdminAccess}, LoadedAt: time.Now()})
+	permissions, err := svc.UserPermissions(ctx, "admin@example.com")
+	if err != nil || len(permissions) != 1 || permissions[0] != models.PermissionAdminAccess {
+		t.Errorf("Expected the cached permissions, got %v (%v)", permissions, err)
+	}
+
+	// A failed role change leaves the cache alone; a successful one clears it
+	if err := svc.AssignRole(ctx, "6f1c2b9e-2c1a-4b8e-9a43-1f0e2d3c4b5a", models.RoleSupport); !errors.Is(err, models.ErrDatabaseNotConnected) {
+		t.Errorf("Expected ErrDatabaseNotConnected, got %v", err)
+	}
+	if svc.permissions.Size() != 1 {
+		t.Error("Expected the cache to survive a failed role change")
+	}
+}
+
+func TestSyncAdminRole(t *testing.T) {
+	svc := NewRoleService(nil)
+	ctx := context.Background()
+
+	if err := svc.SyncAdminRole(ctx, &models.User{ID: "6f1c2b9e-2c1a-4b8e-9a43-1f0e2d3c4b5a"}); err != nil {
+		t.Errorf("Expected nothing to do for a regular user, got %v", err)
+	}
+	admin := &models.User{ID: "6f1c2b9e-2c1a-4b8e-9a43-1f0e2d3c4b5a", Email: "admin@example.com", IsAdmin: true}
+	if err := svc.SyncAdminRole(ctx, admin); !errors.Is(err, models.ErrDatabaseNotConnected) {
+		t.Errorf("Expected the admin role to be assigned, got %v", err)
+	}
+}
+
+func TestSharedRoleChanges(t *testing.T) {
+	shared := cachex.NewLRU[time.Time](permissionCacheTTL, 1)
+	replica, other := NewRoleService(nil), NewRoleService(nil)
+	replica.ShareRoleChanges(shared)
+	other.ShareRoleChanges(shared)
+
+	replica.permissions.Set("admin@example.com", cachedPermissions{Permissions: []string{models.PermissionAdminAccess}, LoadedAt: time.Now().Add(-time.Second)})
+	other.roleChanged()
+
+	// Permissions cached before another replica changed roles are reloaded
+	if _, err := replica.UserPermissions(context.Background(), "admin@example.com"); !errors.Is(err, models.ErrDatabaseNotConnected) {
+		t.Errorf("Expected a reload after the shared role change, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
+)
+
+var (
+	// ErrNoSubscription is returned when the user has no paid subscription to manage
+	ErrNoSubscription = errors.New("no subscription to manage")
+	// ErrSamePrice is returned when changing a subscription to the price it is already on
+	ErrSamePrice = errors.New("subscription is already on this price")
+	// ErrCancellationPending is returned when changing a subscription that is set to cancel
+	ErrCancellationPending = errors.New("subscription is set to cancel")
+)
+
+// ManagedSubscription is the subscription granting the user's plan, described from the catalog
+type ManagedSubscription struct {
+	models.Subscription
+	Plan catalog.Plan
+	// Price is nil when the subscription's price is not in the catalog
+	Price *catalog.Price
+	// Options are the plans and intervals the subscription can be changed to
+	Options []PlanOption
+}
+
+// PlanOption is a catalog plan and price a subscription can move to
+type PlanOption struct {
+	Plan  catalog.Plan
+	Price catalog.Price
+}
+
+// PlanChangePreview is what moving a subscription to an option would cost now
+type PlanChangePreview struct {
+	PlanOption
+	paymentms.ProrationPreview
+}
+
+// SubscriptionService cancels, resumes and changes the plan of a user's
+// subscription at the payment service, storing the result locally
+type SubscriptionService struct {
+	entitlements  *EntitlementService
+	paymentClient *paymentms.Client
+	catalog       *catalog.Catalog
+}
+
+// NewSubscriptionService creates a new subscription service
+func NewSubscriptionService(entitlements *EntitlementService, paymentClient *paymentms.Client, catalog *catalog.Catalog) *SubscriptionService {
+	return &SubscriptionService{
+		entitlements:  entitlements,
+		paymentClient: paymentClient,
+		catalog:       catalog,
+	}
+}
+
+// Current returns the subscription granting the user's plan, or ErrNoSubscription
+func (s *SubscriptionService) Current(ctx context.Context, user *models.User) (*ManagedSubscription, error) {
+	entitlements, err := s.entitlements.Entitlements(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if entitlements.Subscription == nil {
+		return nil, ErrNoSubscription
+	}
+	return describeSubscription(*entitlements.Subscription, s.catalog), nil
+}
+
+// Cancel cancels the subscription at the end of the current period
+func (s *SubscriptionService) Cancel(ctx context.Context, user *models.User) (*ManagedSubscription, error) {
+	current, err := s.Current(ctx, user)
+	if err != nil || current.CancelAtPeriodEnd {
+		return current, err
+	}
+
+	status, err := s.paymentClient.CancelSubscription(ctx, current.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.apply(ctx, user, current.Subscription, status)
+}
+
+// Resume undoes a pending cancellation
+func (s *SubscriptionService) Resume(ctx context.Context, user *models.User) (*ManagedSubscription, error) {
+	current, err := s.Current(ctx, user)
+	if err != nil || !current.CancelAtPeriodEnd {
+		return current, err
+	}
+
+	status, err := s.paymentClient.ResumeSubscription(ctx, current.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.apply(ctx, user, current.Subscription, status)
+}
+
+// PreviewChange returns the prorated cost of moving the subscription to a plan and interval
+func (s *SubscriptionService) PreviewChange(ctx context.Context, user *models.User, planName, interval string) (*PlanChangePreview, error) {
+	current, err := s.Current(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	option, err := changeOption(current, s.catalog, planName, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, err := s.paymentClient.PreviewSubscriptionChange(ctx, current.SubscriptionID, option.Price.ID)
+	if err != nil {
+		return nil, err
+	}
+	if preview.Currency == "" {
+		preview.Currency = option.Price.Currency
+	}
+	return &PlanChangePreview{PlanOption: option, ProrationPreview: *preview}, nil
+}
+
+// Change moves the subscription to a plan and interval, prorated as of
+// prorationDate from the preview the user confirmed
+func (s *SubscriptionService) Change(ctx context.Context, user *models.User, planName, interval string, prorationDate time.Time) (*ManagedSubscription, error) {
+	current, err := s.Current(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	option, err := changeOption(current, s.catalog, planName, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := s.paymentClient.ChangeSubscriptionPrice(ctx, current.SubscriptionID, option.Price.ID, prorationDate)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := current.Subscription
+	changed.ProductID = option.Plan.ProductID
+	changed.PriceID = option.Price.ID
+	return s.apply(ctx, user, changed, status)
+}
+
+// apply stores the subscription as the payment service returned it and describes the result
+func (s *SubscriptionService) apply(ctx context.Context, user *models.User, sub models.Subscription, status *paymentms.SubscriptionStatusResponse) (*ManagedSubscription, error) {
+	updated := mergeSubscriptionStatus(sub, status)
+	updated.UserID = user.ID
+	// The response carries no event time; the webhook for this change orders it against other events
+	updated.StateAt = time.Time{}
+	if err := s.entitlements.ApplySubscription(ctx, updated); err != nil {
+		return nil, err
+	}
+	return describeSubscription(updated, s.catalog), nil
+}
+
+// describeSubscription finds the subscription's plan and price in the catalog
+// and lists the prices it can be changed to
+func describeSubscription(sub models.Subscription, cat *catalog.Catalog) *ManagedSubscription {
+	managed := &ManagedSubscription{Subscription: sub}
+	if plan, price, ok := cat.LookupPrice(sub.PriceID); ok {
+		managed.Plan = plan
+		managed.Price = &price
+	} else if name, ok := cat.ProductPlans()[sub.ProductID]; ok {
+		managed.Plan, _ = cat.Plan(name)
+	}
+
+	for _, plan := range cat.Plans() {
+		for _, interval := range []string{catalog.IntervalMonth, catalog.IntervalYear} {
+			if !plan.Purchasable(interval) {
+				continue
+			}
+			price := *plan.Price(interval)
+			if price.ID != sub.PriceID {
+				managed.Options = append(managed.Options, PlanOption{Plan: plan, Price: price})
+			}
+		}
+	}
+	return managed
+}
+
+// changeOption validates a requested plan change against the catalog and the current price
+func changeOption(current *ManagedSubscription, cat *catalog.Catalog, planName, interval string) (PlanOption, error) {
+	if current.CancelAtPeriodEnd {
+		return PlanOption{}, ErrCancellationPending
+	}
+	plan, price, err := cat.CheckoutPrice(planName, catalog.ParseInterval(interval))
+	if err != nil {
+		return PlanOption{}, err
+	}
+	if price.ID == current.PriceID {
+		return PlanOption{}, ErrSamePrice
+	}
+	return PlanOption{Plan: plan, Price: price}, nil
+}
+
+// mergeSubscriptionStatus applies the fields a payment service response carries onto sub
+func mergeSubscriptionStatus(sub models.Subscription, status *paymentms.SubscriptionStatusResponse) models.Subscription {
+	sub.CancelAtPeriodEnd = status.CancelAtPeriodEnd
+	if status.Status != "" {
+		sub.Status = status.Status
+	}
+	if status.ProductID != "" {
+		sub.ProductID = status.ProductID
+	}
+	if status.PriceID != "" {
+		sub.PriceID = status.PriceID
+	}
+	if !status.CurrentPeriodEnd.IsZero() {
+		sub.CurrentPeriodEnd = status.CurrentPeriodEnd
+	}
+	if !status.TrialEnd.IsZero() {
+		sub.TrialEnd = status.TrialEnd
+	}
+	return sub
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/paymentms"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
+)
+
+func testPlanCatalog(t *testing.T) *catalog.Catalog {
+	t.Helper()
+	cat, err := catalog.New(catalog.Defaults("prod_pro", "price_month", "price_year"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cat
+}
+
+func TestDescribeSubscription(t *testing.T) {
+	cat := testPlanCatalog(t)
+
+	managed := describeSubscription(models.Subscription{ProductID: "prod_pro", PriceID: "price_month"}, cat)
+	if managed.Plan.Name != models.PlanPro || managed.Price == nil || managed.Price.Interval != catalog.IntervalMonth {
+		t.Fatalf("Expected the monthly Pro price, got plan %q price %+v", managed.Plan.Name, managed.Price)
+	}
+	if len(managed.Options) != 1 || managed.Options[0].Price.ID != "price_year" {
+		t.Errorf("Expected the yearly price as the only option, got %+v", managed.Options)
+	}
+
+	// Subscriptions synced before price IDs were stored still find their plan
+	legacy := describeSubscription(models.Subscription{ProductID: "prod_pro"}, cat)
+	if legacy.Plan.Name != models.PlanPro || legacy.Price != nil {
+		t.Errorf("Expected the Pro plan without a known price, got plan %q price %+v", legacy.Plan.Name, legacy.Price)
+	}
+	if len(legacy.Options) != 2 {
+		t.Errorf("Expected both Pro prices as options, got %d", len(legacy.Options))
+	}
+}
+
+func TestChangeOption(t *testing.T) {
+	cat := testPlanCatalog(t)
+	current := describeSubscription(models.Subscription{ProductID: "prod_pro", PriceID: "price_month"}, cat)
+
+	option, err := changeOption(current, cat, models.PlanPro, catalog.IntervalYear)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if option.Price.ID != "price_year" {
+		t.Errorf("Expected price_year, got %s", option.Price.ID)
+	}
+
+	if _, err := changeOption(current, cat, models.PlanPro, catalog.IntervalMonth); !errors.Is(err, ErrSamePrice) {
+		t.Errorf("Expected ErrSamePrice, got %v", err)
+	}
+	if _, err := changeOption(current, cat, models.PlanFree, catalog.IntervalMonth); !errors.Is(err, catalog.ErrNotPurchasable) {
+		t.Errorf("Expected ErrNotPurchasable for the free plan, got %v", err)
+	}
+
+	current.CancelAtPeriodEnd = true
+	if _, err := changeOption(current, cat, models.PlanPro, catalog.IntervalYear); !errors.Is(err, ErrCancellationPending) {
+		t.Errorf("Expected ErrCancellationPending, got %v", err)
+	}
+}
+
+func TestMergeSubscriptionStatus(t *testing.T) {
+	periodEnd := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	sub := models.Subscription{
+		SubscriptionID:   "sub_1",
+		ProductID:        "prod_pro",
+		PriceID:          "price_month",
+		Status:           models.SubscriptionStatusActive,
+		CurrentPeriodEnd: periodEnd,
+	}
+
+	cancelled := mergeSubscriptionStatus(sub, &paymentms.SubscriptionStatusResponse{CancelAtPeriodEnd: true})
+	if !cancelled.CancelAtPeriodEnd || cancelled.PriceID != "price_month" || !cancelled.CurrentPeriodEnd.Equal(periodEnd) {
+		t.Errorf("Expected a pending cancellation keeping the other fields, got %+v", cancelled)
+	}
+
+	resumed := mergeSubscriptionStatus(cancelled, &paymentms.SubscriptionStatusResponse{Status: models.SubscriptionStatusActive})
+	if resumed.CancelAtPeriodEnd {
+		t.Error("Expected the response to clear the pending cancellation")
+	}
+}
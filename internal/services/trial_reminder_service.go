@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/clients/mailer"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/repositories"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
+)
+
+// TrialReminderService emails users whose free trial is about to end
+type TrialReminderService struct {
+	subRepo      *repositories.SubscriptionRepository
+	mailer       mailer.Mailer
+	catalog      *catalog.Catalog
+	baseURL      string
+	reminderDays int
+}
+
+// NewTrialReminderService creates a new trial reminder service. Reminders go
+// out reminderDays before the trial ends and link back to baseURL.
+func NewTrialReminderService(subRepo *repositories.SubscriptionRepository, m mailer.Mailer, cat *catalog.Catalog, baseURL string, reminderDays int) *TrialReminderService {
+	return &TrialReminderService{
+		subRepo:      subRepo,
+		mailer:       m,
+		catalog:      cat,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		reminderDays: reminderDays,
+	}
+}
+
+// Run sends due reminders every interval until ctx is cancelled
+func (s *TrialReminderService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if sent, err := s.SendDue(ctx, time.Now()); err != nil {
+			fmt.Printf("⚠️ TRIALS: Reminder run failed after %d emails: %v\n", sent, err)
+		} else if sent > 0 {
+			fmt.Printf("✅ TRIALS: Sent %d trial reminder(s)\n", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue emails every user whose trial ends within the reminder window and
+// has not been reminded yet. Each reminder is claimed before sending so
+// replicas running the job never send it twice, and released if sending fails
+// so the next run retries it.
+func (s *TrialReminderService) SendDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.subRepo.GetTrialsEndingBefore(ctx, now.AddDate(0, 0, s.reminderDays))
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, trial := range due {
+		claimed, err := s.subRepo.ClaimTrialReminder(ctx, trial.SubscriptionID)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.mailer.Send(ctx, s.reminderMessage(trial, now)); err != nil {
+			fmt.Printf("❌ TRIALS: Failed to email %s about subscription %s: %v\n", trial.Email, trial.SubscriptionID, err)
+			if err := s.subRepo.ReleaseTrialReminder(ctx, trial.SubscriptionID); err != nil {
+				fmt.Printf("⚠️ TRIALS: Could not release reminder for %s: %v\n", trial.SubscriptionID, err)
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// reminderMessage writes the email telling the user when their trial ends
+func (s *TrialReminderService) reminderMessage(trial models.TrialReminder, now time.Time) mailer.Message {
+	planLabel := "your plan"
+	if name, ok := s.catalog.ProductPlans()[trial.ProductID]; ok {
+		if plan, ok := s.catalog.Plan(name); ok {
+			planLabel = "the " + plan.Label + " plan"
+		}
+	}
+
+	days := models.TrialDaysLeft(trial.TrialEnd, now)
+	countdown := "within 24 hours"
+	if days > 1 {
+		countdown = fmt.Sprintf("in %d days", days)
+	}
+
+	greeting := "Hi,"
+	if trial.Name != "" {
+		greeting = fmt.Sprintf("Hi %s,", trial.Name)
+	}
+
+	body := strings.Join([]string{
+		greeting,
+		"",
+		fmt.Sprintf("Your free trial of %s ends %s, on %s.", planLabel, countdown, trial.TrialEnd.UTC().Format("January 2, 2006 at 15:04 UTC")),
+		"After that your subscription continues and your payment method is charged automatically.",
+		"",
+		"To review or cancel your subscription, visit your dashboard:",
+		s.baseURL + "/dashboard#subscription",
+		"",
+		"You are receiving this email because billing emails are enabled in your settings.",
+	}, "\n")
+
+	return mailer.Message{
+		To:      trial.Email,
+		Subject: fmt.Sprintf("Your free trial ends %s", countdown),
+		Body:    body,
+	}
+}
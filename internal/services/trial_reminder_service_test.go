@@ -0,0 +1,43 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+)
+
+func TestTrialReminderMessage(t *testing.T) {
+	svc := NewTrialReminderService(nil, nil, testPlanCatalog(t), "https://app.example.com/", 3)
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	trial := models.TrialReminder{
+		SubscriptionID: "sub_123",
+		ProductID:      "prod_pro",
+		Email:          "user@example.com",
+		Name:           "Sam",
+		TrialEnd:       now.Add(60 * time.Hour),
+	}
+
+	msg := svc.reminderMessage(trial, now)
+	if msg.To != "user@example.com" || msg.Subject != "Your free trial ends in 3 days" {
+		t.Errorf("Unexpected recipient or subject: %q %q", msg.To, msg.Subject)
+	}
+	for _, want := range []string{
+		"Hi Sam,",
+		"the Pro plan ends in 3 days, on March 3, 2026 at 21:00 UTC",
+		"https://app.example.com/dashboard#subscription",
+	} {
+		if !strings.Contains(msg.Body, want) {
+			t.Errorf("Expected %q in body:\n%s", want, msg.Body)
+		}
+	}
+
+	trial.ProductID = "prod_unknown"
+	trial.Name = ""
+	trial.TrialEnd = now.Add(5 * time.Hour)
+	msg = svc.reminderMessage(trial, now)
+	if msg.Subject != "Your free trial ends within 24 hours" || !strings.HasPrefix(msg.Body, "Hi,\n") || !strings.Contains(msg.Body, "trial of your plan") {
+		t.Errorf("Unexpected fallback message: %q\n%s", msg.Subject, msg.Body)
+	}
+}
@@ -0,0 +1,399 @@
+// Package catalog holds the plans offered on the pricing and payment pages.
+//
+// The built-in defaults describe the Starter, Pro and Enterprise plans with the
+// Pro price IDs taken from the STRIPE_* settings. CATALOG_FILE replaces them with
+// either a JSON list of plans or an object with "plans" and shop "items".
+// Checkout names a plan and interval, or cart items; the price ID is always
+// taken from the catalog.
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Billing intervals
+const (
+	IntervalMonth = "month"
+	IntervalYear  = "year"
+)
+
+// MaxTrialDays is the longest free trial the payment provider accepts
+const MaxTrialDays = 730
+
+var (
+	// ErrUnknownPlan is returned for a plan name that is not in the catalog
+	ErrUnknownPlan = errors.New("plan is not in the catalog")
+	// ErrNotPurchasable is returned when a plan has no checkout price for the interval
+	ErrNotPurchasable = errors.New("plan cannot be bought at this interval")
+)
+
+// ParseInterval returns the interval named by s, defaulting to monthly
+func ParseInterval(s string) string {
+	if strings.ToLower(strings.TrimSpace(s)) == IntervalYear {
+		return IntervalYear
+	}
+	return IntervalMonth
+}
+
+// Price is one way to pay for a plan
+type Price struct {
+	ID       string `json:"id"`       // Payment service price ID; empty when not purchasable yet
+	Amount   int64  `json:"amount"`   // In minor units, e.g. cents
+	Currency string `json:"currency"` // ISO code, e.g. "usd"
+	Interval string `json:"interval"` // "month" or "year"
+}
+
+// Plan describes one plan on the pricing page
+type Plan struct {
+	Name        string   `json:"name"`        // Plan key, matching the entitlement plans ("free", "pro")
+	Label       string   `json:"label"`       // Display name, e.g. "Pro"
+	Description string   `json:"description"` // One-line pitch
+	ProductID   string   `json:"product_id"`  // Payment service product ID; empty for free plans
+	Features    []string `json:"features"`    // Display features
+	Monthly     *Price   `json:"monthly,omitempty"`
+	Yearly      *Price   `json:"yearly,omitempty"`
+	Highlight   bool     `json:"highlight"`   // Shown as the popular choice
+	ContactURL  string   `json:"contact_url"` // For custom-priced plans sold by the sales team
+	TrialDays   int      `json:"trial_days"`  // Free trial for first-time subscribers; 0 for none
+	// Entitlements are the feature keys plan checks look for, e.g. "api_access"
+	Entitlements []string `json:"entitlements"`
+	// Rank orders plans for plan checks: a higher rank satisfies checks for a
+	// lower one. Defaults to the plan's position in the catalog.
+	Rank int `json:"rank"`
+}
+
+// Free reports whether the plan costs nothing
+func (p Plan) Free() bool {
+	return p.ProductID == "" && p.ContactURL == ""
+}
+
+// Price returns the plan's price for an interval
+func (p Plan) Price(interval string) *Price {
+	switch interval {
+	case IntervalYear:
+		return p.Yearly
+	case IntervalMonth:
+		return p.Monthly
+	}
+	return nil
+}
+
+// Purchasable reports whether the plan can be bought at the interval through checkout
+func (p Plan) Purchasable(interval string) bool {
+	price := p.Price(interval)
+	return p.ProductID != "" && price != nil && price.ID != ""
+}
+
+// YearlySavings is the rounded percentage saved by paying yearly instead of
+// twelve monthly payments; 0 when there is nothing to compare or save
+func (p Plan) YearlySavings() int {
+	if p.Monthly == nil || p.Yearly == nil || p.Monthly.Currency != p.Yearly.Currency {
+		return 0
+	}
+	total := p.Monthly.Amount * 12
+	saved := total - p.Yearly.Amount
+	if total <= 0 || saved <= 0 {
+		return 0
+	}
+	return int((saved*100 + total/2) / total)
+}
+
+// Item is a one-off product sold through the shop and cart
+type Item struct {
+	Name        string `json:"name"`        // Item key, used in cart URLs
+	Label       string `json:"label"`       // Display name
+	Description string `json:"description"` // One-line pitch
+	PriceID     string `json:"price_id"`    // Payment service price ID
+	Amount      int64  `json:"amount"`      // In minor units, e.g. cents
+	Currency    string `json:"currency"`    // ISO code, e.g. "usd"
+}
+
+// Display renders the item's price, e.g. "$19"
+func (i Item) Display() string {
+	return FormatAmount(i.Amount, i.Currency)
+}
+
+// Catalog is an ordered set of plans and shop items
+type Catalog struct {
+	plans       []Plan
+	byName      map[string]int
+	byPrice     map[string]int
+	items       []Item
+	itemByName  map[string]int
+	itemByPrice map[string]int
+}
+
+// catalogFile is the object form of CATALOG_FILE
+type catalogFile struct {
+	Plans []Plan `json:"plans"`
+	Items []Item `json:"items"`
+}
+
+// Defaults returns the built-in plans; empty IDs leave Pro visible but not purchasable
+func Defaults(proProductID, monthlyPriceID, yearlyPriceID string) []Plan {
+	return []Plan{
+		{
+			Name:        "free",
+			Label:       "Starter",
+			Description: "Perfect for trying out the platform and personal projects.",
+			Features:    []string{"3 Projects", "Basic Analytics", "Community Support"},
+			Monthly:     &Price{Amount: 0, Currency: "usd", Interval: IntervalMonth},
+		},
+		{
+			Name:         "pro",
+			Label:        "Pro",
+			Description:  "For growing businesses and serious developers.",
+			ProductID:    proProductID,
+			Features:     []string{"Unlimited Projects", "Advanced Analytics", "Priority Support", "API Access"},
+			Monthly:      &Price{ID: monthlyPriceID, Amount: 2900, Currency: "usd", Interval: IntervalMonth},
+			Yearly:       &Price{ID: yearlyPriceID, Amount: 29000, Currency: "usd", Interval: IntervalYear},
+			Highlight:    true,
+			Entitlements: []string{"unlimited_projects", "advanced_analytics", "priority_support", "api_access"},
+		},
+		{
+			Name:         "enterprise",
+			Label:        "Enterprise",
+			Description:  "For large organizations with specific requirements.",
+			Features:     []string{"Everything in Pro", "Dedicated Support", "SLA", "Custom Integrations"},
+			ContactURL:   "mailto:sales@example.com",
+			Entitlements: []string{"unlimited_projects", "advanced_analytics", "priority_support", "api_access"},
+		},
+	}
+}
+
+// New builds a catalog; it fails on duplicate names or price IDs
+func New(plans []Plan, items []Item) (*Catalog, error) {
+	c := &Catalog{
+		byName:      map[string]int{},
+		byPrice:     map[string]int{},
+		itemByName:  map[string]int{},
+		itemByPrice: map[string]int{},
+	}
+	for _, p := range plans {
+		p.Name = strings.ToLower(strings.TrimSpace(p.Name))
+		if p.Name == "" {
+			return nil, errors.New("catalog plan without a name")
+		}
+		if _, ok := c.byName[p.Name]; ok {
+			return nil, fmt.Errorf("duplicate catalog plan %q", p.Name)
+		}
+		if p.Label == "" {
+			p.Label = p.Name
+		}
+		if p.TrialDays < 0 || p.TrialDays > MaxTrialDays {
+			return nil, fmt.Errorf("catalog plan %q: trial_days must be between 0 and %d", p.Name, MaxTrialDays)
+		}
+
+		i := len(c.plans)
+		if p.Rank == 0 {
+			p.Rank = i
+		}
+		for _, price := range []*Price{p.Monthly, p.Yearly} {
+			if price == nil || price.ID == "" {
+				continue
+			}
+			if _, ok := c.byPrice[price.ID]; ok {
+				return nil, fmt.Errorf("price %q is listed twice in the catalog", price.ID)
+			}
+			c.byPrice[price.ID] = i
+		}
+		c.byName[p.Name] = i
+		c.plans = append(c.plans, p)
+	}
+
+	for _, item := range items {
+		item.Name = strings.ToLower(strings.TrimSpace(item.Name))
+		if item.Name == "" || item.PriceID == "" {
+			return nil, errors.New("catalog item without a name or price_id")
+		}
+		if _, ok := c.itemByName[item.Name]; ok {
+			return nil, fmt.Errorf("duplicate catalog item %q", item.Name)
+		}
+		_, planPrice := c.byPrice[item.PriceID]
+		if _, ok := c.itemByPrice[item.PriceID]; ok || planPrice {
+			return nil, fmt.Errorf("price %q is listed twice in the catalog", item.PriceID)
+		}
+		if item.Label == "" {
+			item.Label = item.Name
+		}
+
+		c.itemByName[item.Name] = len(c.items)
+		c.itemByPrice[item.PriceID] = len(c.items)
+		c.items = append(c.items, item)
+	}
+	return c, nil
+}
+
+// Load builds the catalog from a JSON file, or from defaults when file is empty.
+// The file holds either a list of plans or an object with "plans" and "items".
+func Load(file string, defaults []Plan) (*Catalog, error) {
+	if file == "" {
+		return New(defaults, nil)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog file: %w", err)
+	}
+
+	var parsed catalogFile
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(data, &parsed.Plans)
+	} else {
+		err = json.Unmarshal(data, &parsed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse catalog file: %w", err)
+	}
+	return New(parsed.Plans, parsed.Items)
+}
+
+// Plans returns the plans in display order
+func (c *Catalog) Plans() []Plan {
+	if c == nil {
+		return nil
+	}
+	return c.plans
+}
+
+// Plan returns a plan by name
+func (c *Catalog) Plan(name string) (Plan, bool) {
+	if c == nil {
+		return Plan{}, false
+	}
+	i, ok := c.byName[strings.ToLower(name)]
+	if !ok {
+		return Plan{}, false
+	}
+	return c.plans[i], true
+}
+
+// LookupPrice finds the plan and price a price ID belongs to
+func (c *Catalog) LookupPrice(priceID string) (Plan, Price, bool) {
+	if c == nil || priceID == "" {
+		return Plan{}, Price{}, false
+	}
+	i, ok := c.byPrice[priceID]
+	if !ok {
+		return Plan{}, Price{}, false
+	}
+	plan := c.plans[i]
+	for _, price := range []*Price{plan.Monthly, plan.Yearly} {
+		if price != nil && price.ID == priceID {
+			return plan, *price, true
+		}
+	}
+	return Plan{}, Price{}, false
+}
+
+// CheckoutPrice returns the plan and the price checkout should charge for it
+// at the interval. Free and contact-only plans are not purchasable.
+func (c *Catalog) CheckoutPrice(planName, interval string) (Plan, Price, error) {
+	plan, ok := c.Plan(planName)
+	if !ok {
+		return Plan{}, Price{}, ErrUnknownPlan
+	}
+	if !plan.Purchasable(interval) {
+		return Plan{}, Price{}, ErrNotPurchasable
+	}
+	return plan, *plan.Price(interval), nil
+}
+
+// HasYearly reports whether any plan can be bought yearly
+func (c *Catalog) HasYearly() bool {
+	for _, p := range c.Plans() {
+		if p.Purchasable(IntervalYear) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxYearlySavings is the best YearlySavings across the plans
+func (c *Catalog) MaxYearlySavings() int {
+	best := 0
+	for _, p := range c.Plans() {
+		if savings := p.YearlySavings(); savings > best {
+			best = savings
+		}
+	}
+	return best
+}
+
+// Items returns the shop items in display order
+func (c *Catalog) Items() []Item {
+	if c == nil {
+		return nil
+	}
+	return c.items
+}
+
+// Item returns a shop item by name
+func (c *Catalog) Item(name string) (Item, bool) {
+	if c == nil {
+		return Item{}, false
+	}
+	i, ok := c.itemByName[strings.ToLower(name)]
+	if !ok {
+		return Item{}, false
+	}
+	return c.items[i], true
+}
+
+// LookupItemPrice finds the shop item a price ID belongs to
+func (c *Catalog) LookupItemPrice(priceID string) (Item, bool) {
+	if c == nil {
+		return Item{}, false
+	}
+	i, ok := c.itemByPrice[priceID]
+	if !ok {
+		return Item{}, false
+	}
+	return c.items[i], true
+}
+
+// Grants reports whether holding the plan named have satisfies a check for the
+// plan named want: the same plan, or one ranked higher in the catalog
+func (c *Catalog) Grants(have, want string) bool {
+	if strings.EqualFold(have, want) {
+		return true
+	}
+	held, ok := c.Plan(have)
+	required, known := c.Plan(want)
+	return ok && known && held.Rank > required.Rank
+}
+
+// ProductPlans maps the catalog's product IDs to their plan names
+func (c *Catalog) ProductPlans() map[string]string {
+	products := map[string]string{}
+	for _, p := range c.Plans() {
+		if p.ProductID != "" {
+			products[p.ProductID] = p.Name
+		}
+	}
+	return products
+}
+
+// FormatAmount renders minor units for display, e.g. 2900 usd -> "$29", 950 eur -> "€9.50"
+func FormatAmount(amount int64, currency string) string {
+	symbols := map[string]string{"usd": "$", "eur": "€", "gbp": "£"}
+
+	value := fmt.Sprintf("%d.%02d", amount/100, amount%100)
+	if amount%100 == 0 {
+		value = fmt.Sprintf("%d", amount/100)
+	}
+
+	if symbol, ok := symbols[strings.ToLower(currency)]; ok {
+		return symbol + value
+	}
+	return value + " " + strings.ToUpper(currency)
+}
+
+// Display renders the price amount, e.g. "$29"
+func (p Price) Display() string {
+	return FormatAmount(p.Amount, p.Currency)
+}
@@ -0,0 +1,239 @@
+package catalog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultsUseConfiguredPriceIDs(t *testing.T) {
+	c, err := Load("", Defaults("prod_pro", "price_month", "price_year"))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if len(c.Plans()) != 3 {
+		t.Fatalf("expected 3 plans, got %d", len(c.Plans()))
+	}
+
+	plan, price, ok := c.LookupPrice("price_year")
+	if !ok || plan.Name != "pro" || price.Interval != IntervalYear {
+		t.Errorf("expected the yearly pro price, got %+v %+v (found=%v)", plan, price, ok)
+	}
+	if !plan.Purchasable(IntervalMonth) {
+		t.Error("expected pro to be purchasable monthly")
+	}
+
+	if products := c.ProductPlans(); products["prod_pro"] != "pro" || len(products) != 1 {
+		t.Errorf("expected only prod_pro -> pro, got %v", products)
+	}
+}
+
+func TestDefaultsWithoutPriceIDsAreNotPurchasable(t *testing.T) {
+	c, err := Load("", Defaults("", "", ""))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	pro, ok := c.Plan("pro")
+	if !ok {
+		t.Fatal("expected the pro plan to be listed")
+	}
+	if pro.Purchasable(IntervalMonth) {
+		t.Error("expected pro without price IDs not to be purchasable")
+	}
+	if _, _, err := c.CheckoutPrice("pro", IntervalMonth); !errors.Is(err, ErrNotPurchasable) {
+		t.Errorf("expected ErrNotPurchasable, got %v", err)
+	}
+	if c.HasYearly() {
+		t.Error("expected no yearly checkout without price IDs")
+	}
+}
+
+func TestCheckoutPrice(t *testing.T) {
+	c, _ := New(Defaults("prod_pro", "price_month", "price_year"), nil)
+
+	plan, price, err := c.CheckoutPrice("pro", IntervalYear)
+	if err != nil || plan.ProductID != "prod_pro" || price.ID != "price_year" {
+		t.Errorf("expected the yearly pro price, got %+v %+v (%v)", plan, price, err)
+	}
+	if _, price, _ := c.CheckoutPrice("Pro", IntervalMonth); price.ID != "price_month" {
+		t.Errorf("expected the monthly pro price, got %q", price.ID)
+	}
+	if _, _, err := c.CheckoutPrice("gold", IntervalMonth); !errors.Is(err, ErrUnknownPlan) {
+		t.Errorf("expected ErrUnknownPlan, got %v", err)
+	}
+	for _, name := range []string{"free", "enterprise"} {
+		if _, _, err := c.CheckoutPrice(name, IntervalMonth); !errors.Is(err, ErrNotPurchasable) {
+			t.Errorf("%s: expected ErrNotPurchasable, got %v", name, err)
+		}
+	}
+	if _, _, err := c.CheckoutPrice("pro", "week"); !errors.Is(err, ErrNotPurchasable) {
+		t.Errorf("expected ErrNotPurchasable for an unknown interval, got %v", err)
+	}
+}
+
+func TestYearlySavings(t *testing.T) {
+	c, _ := New(Defaults("prod_pro", "price_month", "price_year"), nil)
+
+	pro, _ := c.Plan("pro")
+	if got := pro.YearlySavings(); got != 17 {
+		t.Errorf("expected $290/year against $29/month to save 17%%, got %d", got)
+	}
+	free, _ := c.Plan("free")
+	if got := free.YearlySavings(); got != 0 {
+		t.Errorf("expected no savings without a yearly price, got %d", got)
+	}
+	if got := c.MaxYearlySavings(); got != 17 {
+		t.Errorf("expected the best savings to be 17%%, got %d", got)
+	}
+
+	pricier := Plan{
+		Monthly: &Price{Amount: 1000, Currency: "usd"},
+		Yearly:  &Price{Amount: 15000, Currency: "usd"},
+	}
+	if got := pricier.YearlySavings(); got != 0 {
+		t.Errorf("expected no savings when yearly costs more, got %d", got)
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	for in, want := range map[string]string{"year": IntervalYear, " YEAR ": IntervalYear, "month": IntervalMonth, "": IntervalMonth, "week": IntervalMonth} {
+		if got := ParseInterval(in); got != want {
+			t.Errorf("ParseInterval(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCatalogFileReplacesDefaults(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "catalog.json")
+	data := `[
+		{"name": "team", "label": "Team", "product_id": "prod_team",
+		 "monthly": {"id": "price_team_m", "amount": 4900, "currency": "eur", "interval": "month"}}
+	]`
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load(file, Defaults("prod_pro", "price_month", "price_year"))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(c.Plans()) != 1 {
+		t.Fatalf("expected the file to replace the defaults, got %d plans", len(c.Plans()))
+	}
+	if _, _, ok := c.LookupPrice("price_month"); ok {
+		t.Error("expected default prices to be gone")
+	}
+	if _, _, ok := c.LookupPrice("price_team_m"); !ok {
+		t.Error("expected the file's price to be listed")
+	}
+}
+
+func TestCatalogFileWithItems(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "catalog.json")
+	data := `{
+		"plans": [{"name": "free", "label": "Starter"}],
+		"items": [
+			{"name": "Stickers", "label": "Sticker pack", "price_id": "price_stickers", "amount": 500, "currency": "usd"},
+			{"name": "mug", "price_id": "price_mug", "amount": 1500, "currency": "usd"}
+		]
+	}`
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load(file, Defaults("prod_pro", "price_month", "price_year"))
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(c.Plans()) != 1 || len(c.Items()) != 2 {
+		t.Fatalf("expected 1 plan and 2 items, got %d and %d", len(c.Plans()), len(c.Items()))
+	}
+	if item, ok := c.Item("stickers"); !ok || item.PriceID != "price_stickers" {
+		t.Errorf("expected the sticker item by name, got %+v (found=%v)", item, ok)
+	}
+	if item, ok := c.LookupItemPrice("price_mug"); !ok || item.Label != "mug" || item.Display() != "$15" {
+		t.Errorf("expected the mug item by price, got %+v (found=%v)", item, ok)
+	}
+}
+
+func TestNewRejectsDuplicates(t *testing.T) {
+	plans := []Plan{
+		{Name: "a", ProductID: "prod_a", Monthly: &Price{ID: "price_1"}},
+		{Name: "b", ProductID: "prod_b", Monthly: &Price{ID: "price_1"}},
+	}
+	if _, err := New(plans, nil); err == nil {
+		t.Error("expected an error for a duplicate price ID")
+	}
+	if _, err := New([]Plan{{Name: "a"}, {Name: "A"}}, nil); err == nil {
+		t.Error("expected an error for a duplicate plan name")
+	}
+	if _, err := New(plans[:1], []Item{{Name: "sticker", PriceID: "price_1"}}); err == nil {
+		t.Error("expected an error for an item reusing a plan price")
+	}
+	if _, err := New(nil, []Item{{Name: "sticker"}}); err == nil {
+		t.Error("expected an error for an item without a price_id")
+	}
+}
+
+func TestNewValidatesTrialDays(t *testing.T) {
+	if _, err := New([]Plan{{Name: "pro", TrialDays: 14}}, nil); err != nil {
+		t.Errorf("expected a 14 day trial to be accepted, got %v", err)
+	}
+	for _, days := range []int{-1, MaxTrialDays + 1} {
+		if _, err := New([]Plan{{Name: "pro", TrialDays: days}}, nil); err == nil {
+			t.Errorf("expected an error for trial_days %d", days)
+		}
+	}
+}
+
+func TestGrantsFollowsRank(t *testing.T) {
+	c, err := New([]Plan{
+		{Name: "free"},
+		{Name: "pro", ProductID: "prod_pro"},
+		{Name: "team", ProductID: "prod_team", Entitlements: []string{"api_access"}},
+		{Name: "legacy", ProductID: "prod_legacy", Rank: 1},
+	}, nil)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	if team, _ := c.Plan("team"); team.Rank != 2 || len(team.Entitlements) != 1 {
+		t.Errorf("expected team at rank 2 with its entitlements, got %+v", team)
+	}
+	if !c.Grants("team", "pro") || !c.Grants("pro", "free") || !c.Grants("pro", "pro") {
+		t.Error("expected a plan to satisfy itself and lower ranked plans")
+	}
+	if c.Grants("pro", "team") || c.Grants("legacy", "pro") {
+		t.Error("expected a plan not to satisfy an equal or higher ranked one")
+	}
+	if c.Grants("unknown", "free") {
+		t.Error("expected an unknown plan to satisfy nothing")
+	}
+}
+
+func TestNilCatalog(t *testing.T) {
+	var c *Catalog
+	if c.Plans() != nil {
+		t.Error("expected no plans")
+	}
+	if _, _, err := c.CheckoutPrice("pro", IntervalMonth); !errors.Is(err, ErrUnknownPlan) {
+		t.Errorf("expected ErrUnknownPlan, got %v", err)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := map[string]string{
+		FormatAmount(2900, "usd"):  "$29",
+		FormatAmount(950, "EUR"):   "€9.50",
+		FormatAmount(0, "gbp"):     "£0",
+		FormatAmount(12345, "chf"): "123.45 CHF",
+	}
+	for got, want := range tests {
+		if got != want {
+			t.Errorf("expected %s, got %s", want, got)
+		}
+	}
+}
@@ -3,8 +3,12 @@ package config
 import (
 	"fmt"
 	"log"
+	"net"
 	"strconv"
+	"strings"
 
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/providers"
 	"github.com/dracondev/go-templ-htmx-ex/libs/configx"
 )
 
@@ -17,14 +21,45 @@ type Config struct {
 	AdminEmail           string
 	PaymentServiceURL    string
 	PaymentServiceAPIKey string
-	StripeProductID      string
+	// PaymentWebhookSecret verifies the HMAC signature of payment service webhooks
+	PaymentWebhookSecret string
+	// PaymentProjectID identifies this app to a payment service shared by several projects
+	PaymentProjectID string
+	StripeProductID  string
 	// Stripe Product/Price Configuration
 	StripeProductPro   string
 	StripePriceMonthly string
 	StripePriceYearly  string
+	// Plans offered on the pricing and payment pages; checkout only accepts their prices
+	Catalog *catalog.Catalog
+	// TrialReminderDays is how many days before a trial ends its reminder email is sent
+	TrialReminderDays int
+	// TrialReminderInterval is how often (minutes) the trial reminder job looks for trials ending
+	TrialReminderInterval int
+	// Outgoing email; without an SMTP host emails are logged instead of sent
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	EmailFrom    string
 	// Session Configuration
 	SessionSecret  string
 	SessionTimeout int
+	// SessionGracePeriod is how long (seconds) a validated session keeps working while the auth service is down
+	SessionGracePeriod int
+	// Auth service circuit breaker: consecutive failures before opening, and seconds before a retry probe
+	AuthBreakerThreshold int
+	AuthBreakerCooldown  int
+	// Session cache backend: "memory" (per process) or "redis" (shared between replicas)
+	SessionCacheBackend    string
+	SessionCacheMaxEntries int
+	RedisURL               string
+	// TrustedProxies are the reverse proxies whose X-Forwarded-For is believed
+	TrustedProxies []*net.IPNet
+	// AuthCallbackMode is "client" (JS exchange, the default) or "server" (exchange the code in /auth/callback)
+	AuthCallbackMode string
+	// OAuth providers offered on the login page
+	Providers *providers.Registry
 }
 
 var (
@@ -71,6 +106,18 @@ func LoadConfig() *Config {
 			Required:     false,
 			Description:  "Payment service API Key",
 		},
+		{
+			Key:          "PAYMENT_WEBHOOK_SECRET",
+			DefaultValue: "",
+			Required:     false,
+			Description:  "Shared secret for verifying payment service webhook signatures",
+		},
+		{
+			Key:          "PAYMENT_PROJECT_ID",
+			DefaultValue: "startup_platform",
+			Required:     false,
+			Description:  "Project identifier sent on every payment service call",
+		},
 		{
 			Key:          "STRIPE_PRODUCT_ID",
 			DefaultValue: "",
@@ -95,11 +142,83 @@ func LoadConfig() *Config {
 			Required:     false,
 			Description:  "Stripe Price ID for yearly billing",
 		},
+		{
+			Key:          "CATALOG_FILE",
+			DefaultValue: "",
+			Required:     false,
+			Description:  "Optional JSON file listing the plans and prices (replaces the built-in catalog)",
+		},
+		{
+			Key:          "TRIAL_DAYS",
+			DefaultValue: "0",
+			Required:     false,
+			Description:  "Free trial days for first-time Pro subscribers of the built-in catalog (0 disables)",
+		},
+		{
+			Key:          "TRIAL_REMINDER_DAYS",
+			DefaultValue: "3",
+			Required:     false,
+			Description:  "Days before a trial ends that the reminder email is sent",
+		},
+		{
+			Key:          "TRIAL_REMINDER_INTERVAL",
+			DefaultValue: "60",
+			Required:     false,
+			Description:  "Minutes between runs of the trial reminder job (0 disables it)",
+		},
+		{
+			Key:          "SMTP_HOST",
+			DefaultValue: "",
+			Required:     false,
+			Description:  "SMTP server for outgoing email (emails are logged when empty)",
+		},
+		{
+			Key:          "SMTP_PORT",
+			DefaultValue: "587",
+			Required:     false,
+			Description:  "SMTP server port",
+		},
+		{
+			Key:          "SMTP_USERNAME",
+			DefaultValue: "",
+			Required:     false,
+			Description:  "SMTP username",
+		},
+		{
+			Key:          "SMTP_PASSWORD",
+			DefaultValue: "",
+			Required:     false,
+			Description:  "SMTP password",
+		},
+		{
+			Key:          "EMAIL_FROM",
+			DefaultValue: "Startup Platform <no-reply@startup-platform.local>",
+			Required:     false,
+			Description:  "Sender address of outgoing email",
+		},
 		{
 			Key:          "SESSION_SECRET",
 			DefaultValue: "change-me-in-production",
 			Required:     false,
-			Description:  "Session encryption secret",
+			Description:  "Secret used to sign CSRF tokens and OAuth state; rotating it invalidates logins in progress",
+		},
+		{
+			Key:          "AUTH_CALLBACK_MODE",
+			DefaultValue: "client",
+			Required:     false,
+			Description:  "OAuth callback completion: client (JavaScript page) or server (no JavaScript)",
+		},
+		{
+			Key:          "OAUTH_PROVIDERS",
+			DefaultValue: "",
+			Required:     false,
+			Description:  "Comma-separated enabled OAuth providers (empty keeps the built-in defaults)",
+		},
+		{
+			Key:          "OAUTH_PROVIDERS_FILE",
+			DefaultValue: "",
+			Required:     false,
+			Description:  "Optional JSON file with OAuth provider definitions",
 		},
 		{
 			Key:          "SESSION_TIMEOUT",
@@ -107,6 +226,48 @@ func LoadConfig() *Config {
 			Required:     false,
 			Description:  "Session timeout in seconds",
 		},
+		{
+			Key:          "SESSION_GRACE_PERIOD",
+			DefaultValue: "300",
+			Required:     false,
+			Description:  "Seconds a validated session stays usable while the auth service is unreachable (0 disables)",
+		},
+		{
+			Key:          "SESSION_CACHE_BACKEND",
+			DefaultValue: "memory",
+			Required:     false,
+			Description:  "Session cache backend: memory or redis (also shares role changes between replicas)",
+		},
+		{
+			Key:          "SESSION_CACHE_MAX_ENTRIES",
+			DefaultValue: "10000",
+			Required:     false,
+			Description:  "Maximum sessions held by the in-memory session cache (least recently used are evicted)",
+		},
+		{
+			Key:          "REDIS_URL",
+			DefaultValue: "redis://localhost:6379/0",
+			Required:     false,
+			Description:  "Redis URL used when SESSION_CACHE_BACKEND=redis",
+		},
+		{
+			Key:          "TRUSTED_PROXIES",
+			DefaultValue: "",
+			Required:     false,
+			Description:  "Comma-separated IPs or CIDRs of reverse proxies allowed to set X-Forwarded-For",
+		},
+		{
+			Key:          "AUTH_BREAKER_THRESHOLD",
+			DefaultValue: "5",
+			Required:     false,
+			Description:  "Consecutive auth service failures before the circuit breaker opens (0 disables)",
+		},
+		{
+			Key:          "AUTH_BREAKER_COOLDOWN",
+			DefaultValue: "30",
+			Required:     false,
+			Description:  "Seconds the auth service circuit breaker stays open before probing again",
+		},
 	}
 
 	baseConfig, err := configx.Load(fields, configx.DefaultOptions())
@@ -115,38 +276,122 @@ func LoadConfig() *Config {
 	}
 
 	// Parse session timeout with default
-	sessionTimeout := 3600
-	if timeoutStr := baseConfig.Get("SESSION_TIMEOUT"); timeoutStr != "" {
-		if parsed, err := strconv.Atoi(timeoutStr); err == nil {
-			sessionTimeout = parsed
+	sessionTimeout := intSetting(baseConfig, "SESSION_TIMEOUT", 3600)
+
+	oauthProviders, err := providers.Load(baseConfig.Get("OAUTH_PROVIDERS"), baseConfig.Get("OAUTH_PROVIDERS_FILE"))
+	if err != nil {
+		log.Fatalf("Failed to load OAuth providers: %v", err)
+	}
+
+	proProductID := baseConfig.Get("STRIPE_PRODUCT_PRO")
+	if proProductID == "" {
+		proProductID = baseConfig.Get("STRIPE_PRODUCT_ID")
+	}
+	defaultPlans := catalog.Defaults(proProductID, baseConfig.Get("STRIPE_PRICE_MONTHLY"), baseConfig.Get("STRIPE_PRICE_YEARLY"))
+	for i := range defaultPlans {
+		if defaultPlans[i].Name == "pro" {
+			defaultPlans[i].TrialDays = intSetting(baseConfig, "TRIAL_DAYS", 0)
 		}
 	}
+	planCatalog, err := catalog.Load(baseConfig.Get("CATALOG_FILE"), defaultPlans)
+	if err != nil {
+		log.Fatalf("Failed to load product catalog: %v", err)
+	}
+
+	trustedProxies, err := parseTrustedProxies(baseConfig.Get("TRUSTED_PROXIES"))
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	config := &Config{
-		Config:               baseConfig,
-		ServerPort:           baseConfig.Get("PORT"),
-		AuthServiceURL:       baseConfig.Get("AUTH_SERVICE_URL"),
-		RedirectURL:          baseConfig.Get("REDIRECT_URL"),
-		AdminEmail:           baseConfig.Get("ADMIN_EMAIL"),
-		PaymentServiceURL:    baseConfig.Get("PAYMENT_MS_URL"),
-		PaymentServiceAPIKey: baseConfig.Get("PAYMENT_MS_API_KEY"),
-		StripeProductID:      baseConfig.Get("STRIPE_PRODUCT_ID"),
-		StripeProductPro:     baseConfig.Get("STRIPE_PRODUCT_PRO"),
-		StripePriceMonthly:   baseConfig.Get("STRIPE_PRICE_MONTHLY"),
-		StripePriceYearly:    baseConfig.Get("STRIPE_PRICE_YEARLY"),
-		SessionSecret:        baseConfig.Get("SESSION_SECRET"),
-		SessionTimeout:       sessionTimeout,
+		Config:                 baseConfig,
+		ServerPort:             baseConfig.Get("PORT"),
+		AuthServiceURL:         baseConfig.Get("AUTH_SERVICE_URL"),
+		RedirectURL:            baseConfig.Get("REDIRECT_URL"),
+		AdminEmail:             baseConfig.Get("ADMIN_EMAIL"),
+		PaymentServiceURL:      baseConfig.Get("PAYMENT_MS_URL"),
+		PaymentServiceAPIKey:   baseConfig.Get("PAYMENT_MS_API_KEY"),
+		PaymentWebhookSecret:   baseConfig.Get("PAYMENT_WEBHOOK_SECRET"),
+		PaymentProjectID:       baseConfig.Get("PAYMENT_PROJECT_ID"),
+		StripeProductID:        baseConfig.Get("STRIPE_PRODUCT_ID"),
+		StripeProductPro:       baseConfig.Get("STRIPE_PRODUCT_PRO"),
+		StripePriceMonthly:     baseConfig.Get("STRIPE_PRICE_MONTHLY"),
+		StripePriceYearly:      baseConfig.Get("STRIPE_PRICE_YEARLY"),
+		SessionSecret:          baseConfig.Get("SESSION_SECRET"),
+		SessionTimeout:         sessionTimeout,
+		SessionGracePeriod:     intSetting(baseConfig, "SESSION_GRACE_PERIOD", 300),
+		SessionCacheBackend:    baseConfig.Get("SESSION_CACHE_BACKEND"),
+		SessionCacheMaxEntries: intSetting(baseConfig, "SESSION_CACHE_MAX_ENTRIES", 10000),
+		RedisURL:               baseConfig.Get("REDIS_URL"),
+		AuthBreakerThreshold:   intSetting(baseConfig, "AUTH_BREAKER_THRESHOLD", 5),
+		AuthBreakerCooldown:    intSetting(baseConfig, "AUTH_BREAKER_COOLDOWN", 30),
+		TrustedProxies:         trustedProxies,
+		AuthCallbackMode:       baseConfig.Get("AUTH_CALLBACK_MODE"),
+		Providers:              oauthProviders,
+		Catalog:                planCatalog,
+		TrialReminderDays:      intSetting(baseConfig, "TRIAL_REMINDER_DAYS", 3),
+		TrialReminderInterval:  intSetting(baseConfig, "TRIAL_REMINDER_INTERVAL", 60),
+		SMTPHost:               baseConfig.Get("SMTP_HOST"),
+		SMTPPort:               intSetting(baseConfig, "SMTP_PORT", 587),
+		SMTPUsername:           baseConfig.Get("SMTP_USERNAME"),
+		SMTPPassword:           baseConfig.Get("SMTP_PASSWORD"),
+		EmailFrom:              baseConfig.Get("EMAIL_FROM"),
 	}
 
 	Current = config
 	return config
 }
 
+// intSetting parses an integer setting, falling back to the default when unset or invalid
+func intSetting(baseConfig *configx.Config, key string, defaultValue int) int {
+	if value := baseConfig.Get(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// parseTrustedProxies parses a comma-separated list of IPs and CIDRs
+func parseTrustedProxies(value string) ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("%q is not an IP address", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
 // IsAdmin checks if the given email matches the admin email
 func (c *Config) IsAdmin(email string) bool {
 	return c.AdminEmail != "" && email == c.AdminEmail
 }
 
+// ServerSideCallback reports whether /auth/callback completes the login without
+// client JavaScript. It is opt-in; the JavaScript page stays the default.
+func (c *Config) ServerSideCallback() bool {
+	return c.AuthCallbackMode == "server"
+}
+
 // GetServerAddress returns the full server address
 func (c *Config) GetServerAddress() string {
 	return fmt.Sprintf(":%s", c.ServerPort)
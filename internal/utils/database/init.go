@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
 
 	_ "github.com/lib/pq"
 )
@@ -25,15 +27,23 @@ func RunMigrations(dbURL string) error {
 
 	log.Println("✅ Connected to database for initialization")
 
-	// Read and execute migration script
-	migrationSQL, err := os.ReadFile("database/migrations/001_initial_schema.sql")
+	// Apply every migration in filename order (all scripts are idempotent)
+	migrations, err := filepath.Glob("database/migrations/*.sql")
 	if err != nil {
-		return fmt.Errorf("failed to read migration script: %w", err)
+		return fmt.Errorf("failed to list migration scripts: %w", err)
 	}
+	sort.Strings(migrations)
 
-	// Execute migration
-	if _, err := db.Exec(string(migrationSQL)); err != nil {
-		return fmt.Errorf("failed to execute migration: %w", err)
+	for _, migration := range migrations {
+		migrationSQL, err := os.ReadFile(migration)
+		if err != nil {
+			return fmt.Errorf("failed to read migration script %s: %w", migration, err)
+		}
+
+		if _, err := db.Exec(string(migrationSQL)); err != nil {
+			return fmt.Errorf("failed to execute migration %s: %w", migration, err)
+		}
+		log.Printf("✅ Applied migration %s", filepath.Base(migration))
 	}
 
 	log.Println("✅ Database schema created successfully")
@@ -81,6 +91,16 @@ func RunMigrations(dbURL string) error {
 		log.Println("ℹ️  No ADMIN_EMAIL configured - using first-user-as-admin system")
 	}
 
+	// Make sure every is_admin user also holds the admin role used for permission checks
+	if _, err := db.Exec(`
+		INSERT INTO user_roles (user_id, role_id)
+		SELECT u.id, r.id FROM users u CROSS JOIN roles r
+		WHERE u.is_admin = TRUE AND r.name = 'admin'
+		ON CONFLICT DO NOTHING
+	`); err != nil {
+		log.Printf("⚠️  Could not sync admin role assignments: %v", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,90 @@
+package oauthstate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testSecret = "test-session-secret"
+
+func TestStateRoundTrip(t *testing.T) {
+	state, err := New(testSecret, "github", "/settings?tab=billing", time.Minute)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	payload, err := Verify(testSecret, state)
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if payload.ReturnTo != "/settings?tab=billing" {
+		t.Errorf("Expected return path to survive, got %q", payload.ReturnTo)
+	}
+	if payload.Provider != "github" {
+		t.Errorf("Expected provider github, got %q", payload.Provider)
+	}
+}
+
+func TestStateRejected(t *testing.T) {
+	state, _ := New(testSecret, "google", "/dashboard", time.Minute)
+
+	t.Run("wrong_secret", func(t *testing.T) {
+		if _, err := Verify("other-secret", state); err != ErrInvalidState {
+			t.Errorf("Expected ErrInvalidState, got %v", err)
+		}
+	})
+
+	t.Run("tampered_payload", func(t *testing.T) {
+		if _, err := Verify(testSecret, "x"+state); err != ErrInvalidState {
+			t.Errorf("Expected ErrInvalidState, got %v", err)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired, _ := New(testSecret, "google", "/dashboard", -time.Second)
+		if _, err := Verify(testSecret, expired); err != ErrExpiredState {
+			t.Errorf("Expected ErrExpiredState, got %v", err)
+		}
+	})
+
+	t.Run("cookie_mismatch", func(t *testing.T) {
+		other, _ := New(testSecret, "google", "/dashboard", time.Minute)
+		req := httptest.NewRequest("POST", "/api/auth/exchange-code", nil)
+		req.AddCookie(&http.Cookie{Name: CookieName, Value: other})
+
+		if _, err := VerifyRequest(req, testSecret, state); err != ErrStateMismatch {
+			t.Errorf("Expected ErrStateMismatch, got %v", err)
+		}
+	})
+
+	t.Run("cookie_match", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/auth/exchange-code", nil)
+		req.AddCookie(&http.Cookie{Name: CookieName, Value: state})
+
+		if _, err := VerifyRequest(req, testSecret, state); err != nil {
+			t.Errorf("Expected matching cookie to verify, got %v", err)
+		}
+	})
+}
+
+func TestSafeReturnTo(t *testing.T) {
+	cases := map[string]string{
+		"":                          "/",
+		"/settings?tab=billing":     "/settings?tab=billing",
+		"/dashboard":                "/dashboard",
+		"https://evil.example":      "/",
+		"//evil.example/path":       "/",
+		"/\\evil.example":           "/",
+		"javascript:alert(1)":       "/",
+		"/auth/callback?auth_code=": "/",
+		"/login":                    "/",
+	}
+
+	for input, expected := range cases {
+		if got := SafeReturnTo(input); got != expected {
+			t.Errorf("SafeReturnTo(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Package providers holds the registry of OAuth providers offered on the login page.
+//
+// The built-in defaults cover the providers the auth service ships with. The
+// OAUTH_PROVIDERS_FILE JSON file can override or add entries, and OAUTH_PROVIDERS
+// selects which of them are enabled.
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Provider describes one OAuth provider
+type Provider struct {
+	Name     string `json:"name"`      // Identifier used in ?provider= and the OAuth state
+	Label    string `json:"label"`     // Display name, e.g. "GitHub"
+	Icon     string `json:"icon"`      // Built-in icon key or an image URL
+	Enabled  bool   `json:"enabled"`   // Whether the provider is offered and accepted
+	AuthPath string `json:"auth_path"` // Path on the auth service that starts the flow
+}
+
+// Registry is an ordered set of providers
+type Registry struct {
+	providers []Provider
+	byName    map[string]int
+}
+
+// Defaults returns the built-in provider definitions
+func Defaults() []Provider {
+	return []Provider{
+		{Name: "google", Label: "Google", Icon: "google", Enabled: true, AuthPath: "/auth/google"},
+		{Name: "github", Label: "GitHub", Icon: "github", Enabled: true, AuthPath: "/auth/github"},
+		{Name: "discord", Label: "Discord", Icon: "discord", Enabled: true, AuthPath: "/auth/discord"},
+		{Name: "microsoft", Label: "Microsoft", Icon: "microsoft", Enabled: true, AuthPath: "/auth/microsoft"},
+		{Name: "gitlab", Label: "GitLab", Icon: "gitlab", Enabled: false, AuthPath: "/auth/gitlab"},
+		{Name: "apple", Label: "Apple", Icon: "apple", Enabled: false, AuthPath: "/auth/apple"},
+	}
+}
+
+// NewRegistry builds a registry; later entries with the same name replace earlier ones
+func NewRegistry(list []Provider) *Registry {
+	reg := &Registry{byName: map[string]int{}}
+	for _, p := range list {
+		p.Name = strings.ToLower(strings.TrimSpace(p.Name))
+		if p.Name == "" {
+			continue
+		}
+		if p.Label == "" {
+			p.Label = p.Name
+		}
+		if p.Icon == "" {
+			p.Icon = p.Name
+		}
+		if p.AuthPath == "" {
+			p.AuthPath = "/auth/" + p.Name
+		}
+
+		if i, ok := reg.byName[p.Name]; ok {
+			reg.providers[i] = p
+			continue
+		}
+		reg.byName[p.Name] = len(reg.providers)
+		reg.providers = append(reg.providers, p)
+	}
+	return reg
+}
+
+// Load builds the registry from the defaults, an optional JSON file of provider
+// definitions, and an optional comma-separated list of enabled provider names
+func Load(enabled, file string) (*Registry, error) {
+	list := Defaults()
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read providers file: %w", err)
+		}
+		var custom []Provider
+		if err := json.Unmarshal(data, &custom); err != nil {
+			return nil, fmt.Errorf("failed to parse providers file: %w", err)
+		}
+		list = append(list, custom...)
+	}
+
+	reg := NewRegistry(list)
+
+	if strings.TrimSpace(enabled) != "" {
+		wanted := map[string]bool{}
+		for _, name := range strings.Split(enabled, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if _, ok := reg.byName[name]; !ok {
+				return nil, fmt.Errorf("unknown OAuth provider %q", name)
+			}
+			wanted[name] = true
+		}
+		for i := range reg.providers {
+			reg.providers[i].Enabled = wanted[reg.providers[i].Name]
+		}
+	}
+
+	return reg, nil
+}
+
+// Get returns an enabled provider by name
+func (r *Registry) Get(name string) (Provider, bool) {
+	if r == nil {
+		return Provider{}, false
+	}
+	i, ok := r.byName[strings.ToLower(name)]
+	if !ok || !r.providers[i].Enabled {
+		return Provider{}, false
+	}
+	return r.providers[i], true
+}
+
+// Enabled returns the enabled providers in display order
+func (r *Registry) Enabled() []Provider {
+	if r == nil {
+		return nil
+	}
+	var list []Provider
+	for _, p := range r.providers {
+		if p.Enabled {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// Label returns the display name for a provider, or "your provider" if unknown
+func (r *Registry) Label(name string) string {
+	if p, ok := r.Get(name); ok {
+		return p.Label
+	}
+	return "your provider"
+}
@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultsEnableBuiltInProviders(t *testing.T) {
+	reg, err := Load("", "")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	var names []string
+	for _, p := range reg.Enabled() {
+		names = append(names, p.Name)
+	}
+	want := []string{"google", "github", "discord", "microsoft"}
+	if len(names) != len(want) {
+		t.Fatalf("expected %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, names)
+		}
+	}
+
+	if _, ok := reg.Get("gitlab"); ok {
+		t.Error("expected gitlab to be disabled by default")
+	}
+}
+
+func TestEnabledListSelectsProviders(t *testing.T) {
+	reg, err := Load("gitlab, Google", "")
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if _, ok := reg.Get("gitlab"); !ok {
+		t.Error("expected gitlab to be enabled")
+	}
+	if _, ok := reg.Get("github"); ok {
+		t.Error("expected github to be disabled")
+	}
+	if got := reg.Label("google"); got != "Google" {
+		t.Errorf("expected label Google, got %q", got)
+	}
+
+	if _, err := Load("myspace", ""); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
+
+func TestProvidersFileOverridesAndAdds(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "providers.json")
+	data := `[
+		{"name": "github", "label": "GitHub Enterprise", "enabled": true, "auth_path": "/auth/ghe"},
+		{"name": "okta", "label": "Okta", "icon": "https://example.com/okta.svg", "enabled": true}
+	]`
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err := Load("", file)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	github, _ := reg.Get("github")
+	if github.Label != "GitHub Enterprise" || github.AuthPath != "/auth/ghe" {
+		t.Errorf("expected github override, got %+v", github)
+	}
+
+	okta, ok := reg.Get("okta")
+	if !ok {
+		t.Fatal("expected okta to be registered")
+	}
+	if okta.AuthPath != "/auth/okta" {
+		t.Errorf("expected default auth path, got %q", okta.AuthPath)
+	}
+}
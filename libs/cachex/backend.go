@@ -0,0 +1,22 @@
+package cachex
+
+import "time"
+
+// Backend is the storage behind a cache. *Cache[T] is the in-process
+// implementation; *RedisCache[T] shares entries between processes.
+//
+// Backends treat storage failures as cache misses: a cache must never be the
+// reason a request fails.
+type Backend[T any] interface {
+	// Get returns the value if it exists and has not expired
+	Get(key string) (T, bool)
+	// SetWithTTL stores a value that expires after ttl
+	SetWithTTL(key string, value T, ttl time.Duration)
+	// Delete removes a value
+	Delete(key string)
+}
+
+var (
+	_ Backend[string] = (*Cache[string])(nil)
+	_ Backend[string] = (*RedisCache[string])(nil)
+)
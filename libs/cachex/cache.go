@@ -1,61 +1,100 @@
 package cachex
 
 import (
+	"container/list"
+	"fmt"
 	"sync"
 	"time"
 )
 
-// Cache is a generic TTL-based cache with thread-safe operations
+// Cache is a generic TTL-based cache with thread-safe operations.
+// When created with NewLRU it holds at most maxEntries values and evicts the
+// least recently used one to make room.
 type Cache[T any] struct {
 	sync.RWMutex
-	entries map[string]*entry[T]
-	ttl     time.Duration
-	
+	entries    map[string]*list.Element
+	order      *list.List // front = most recently used
+	ttl        time.Duration
+	maxEntries int // 0 = unbounded
+
+	hits        uint64
+	misses      uint64
+	evictions   uint64
+	expirations uint64
+
+	loadMu  sync.Mutex
+	loading map[string]*load[T]
 }
 
 // entry represents a cached value with expiration
 type entry[T any] struct {
+	key       string
 	value     T
 	expiresAt time.Time
 }
 
+// load is an in-flight GetOrLoad call shared by concurrent callers
+type load[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Stats is a snapshot of cache counters
+type Stats struct {
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`   // removed to respect maxEntries
+	Expirations uint64 `json:"expirations"` // removed because their TTL passed
+	Size        int    `json:"size"`
+	MaxEntries  int    `json:"max_entries"`
+}
+
 // New creates a new cache with the specified TTL
 func New[T any](ttl time.Duration) *Cache[T] {
+	return NewLRU[T](ttl, 0)
+}
+
+// NewLRU creates a cache holding at most maxEntries values (0 = unbounded)
+func NewLRU[T any](ttl time.Duration, maxEntries int) *Cache[T] {
 	return &Cache[T]{
-		entries: make(map[string]*entry[T]),
-		ttl:     ttl,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		loading:    make(map[string]*load[T]),
 	}
 }
 
-// Get retrieves a cached value if it exists and hasn't expired
+// Get retrieves a cached value if it exists and hasn't expired.
+// Expired entries are removed on access.
 func (c *Cache[T]) Get(key string) (T, bool) {
-	c.RLock()
-	defer c.RUnlock()
+	c.Lock()
+	defer c.Unlock()
 
-	e, exists := c.entries[key]
+	var zero T
+	el, exists := c.entries[key]
 	if !exists {
-		var zero T
+		c.misses++
 		return zero, false
 	}
 
+	e := el.Value.(*entry[T])
 	if time.Now().After(e.expiresAt) {
-		// Expired entry - will be cleaned up on next cleanup cycle
-		var zero T
+		c.removeElement(el)
+		c.expirations++
+		c.misses++
 		return zero, false
 	}
 
+	c.order.MoveToFront(el)
+	c.hits++
 	return e.value, true
 }
 
 // Set stores a value in the cache with the configured TTL
 func (c *Cache[T]) Set(key string, value T) {
-	c.Lock()
-	defer c.Unlock()
-
-	c.entries[key] = &entry[T]{
-		value:     value,
-		expiresAt: time.Now().Add(c.ttl),
-	}
+	c.SetWithTTL(key, value, c.ttl)
 }
 
 // SetWithTTL stores a value with a custom TTL
@@ -63,18 +102,80 @@ func (c *Cache[T]) SetWithTTL(key string, value T, ttl time.Duration) {
 	c.Lock()
 	defer c.Unlock()
 
-	c.entries[key] = &entry[T]{
+	expiresAt := time.Now().Add(ttl)
+	if el, exists := c.entries[key]; exists {
+		e := el.Value.(*entry[T])
+		e.value = value
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry[T]{
+		key:       key,
 		value:     value,
-		expiresAt: time.Now().Add(ttl),
+		expiresAt: expiresAt,
+	})
+
+	if c.maxEntries > 0 {
+		for len(c.entries) > c.maxEntries {
+			c.removeElement(c.order.Back())
+			c.evictions++
+		}
 	}
 }
 
+// GetOrLoad returns the cached value, or calls loader on a miss and caches its
+// result. Concurrent callers for the same key share one loader call. Errors are
+// returned to every waiting caller and not cached. If loader panics, waiting
+// callers get an error and the panic continues in the loading caller.
+func (c *Cache[T]) GetOrLoad(key string, loader func() (T, error)) (T, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	c.loadMu.Lock()
+	if l, ok := c.loading[key]; ok {
+		c.loadMu.Unlock()
+		<-l.done
+		return l.value, l.err
+	}
+	l := &load[T]{done: make(chan struct{})}
+	c.loading[key] = l
+	c.loadMu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			l.err = fmt.Errorf("cachex: loader for %q panicked: %v", key, r)
+			c.finishLoad(key, l)
+			panic(r)
+		}
+		c.finishLoad(key, l)
+	}()
+
+	l.value, l.err = loader()
+	if l.err == nil {
+		c.Set(key, l.value)
+	}
+	return l.value, l.err
+}
+
+// finishLoad releases the callers waiting on a load
+func (c *Cache[T]) finishLoad(key string, l *load[T]) {
+	c.loadMu.Lock()
+	delete(c.loading, key)
+	c.loadMu.Unlock()
+	close(l.done)
+}
+
 // Delete removes a value from the cache
 func (c *Cache[T]) Delete(key string) {
 	c.Lock()
 	defer c.Unlock()
 
-	delete(c.entries, key)
+	if el, exists := c.entries[key]; exists {
+		c.removeElement(el)
+	}
 }
 
 // Clear removes all entries from the cache
@@ -82,7 +183,8 @@ func (c *Cache[T]) Clear() {
 	c.Lock()
 	defer c.Unlock()
 
-	c.entries = make(map[string]*entry[T])
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
 }
 
 // Cleanup removes expired entries from the cache
@@ -93,12 +195,13 @@ func (c *Cache[T]) Cleanup() int {
 	now := time.Now()
 	removed := 0
 
-	for key, e := range c.entries {
-		if now.After(e.expiresAt) {
-			delete(c.entries, key)
+	for _, el := range c.entries {
+		if now.After(el.Value.(*entry[T]).expiresAt) {
+			c.removeElement(el)
 			removed++
 		}
 	}
+	c.expirations += uint64(removed)
 
 	return removed
 }
@@ -111,6 +214,27 @@ func (c *Cache[T]) Size() int {
 	return len(c.entries)
 }
 
+// Stats returns hit/miss/eviction counters and the current size
+func (c *Cache[T]) Stats() Stats {
+	c.RLock()
+	defer c.RUnlock()
+
+	return Stats{
+		Hits:        c.hits,
+		Misses:      c.misses,
+		Evictions:   c.evictions,
+		Expirations: c.expirations,
+		Size:        len(c.entries),
+		MaxEntries:  c.maxEntries,
+	}
+}
+
+// removeElement unlinks an entry; the caller holds the write lock
+func (c *Cache[T]) removeElement(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*entry[T]).key)
+}
+
 // StartCleanupRoutine starts a background goroutine that periodically cleans up expired entries
 func (c *Cache[T]) StartCleanupRoutine(interval time.Duration) chan struct{} {
 	stop := make(chan struct{})
@@ -1,6 +1,9 @@
 package cachex
 
 import (
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -244,3 +247,160 @@ func TestCacheConcurrency(t *testing.T) {
 		t.Error("Concurrent operations failed")
 	}
 }
+
+func TestCacheGetRemovesExpired(t *testing.T) {
+	cache := New[string](50 * time.Millisecond)
+
+	cache.Set("key1", "value1")
+	time.Sleep(100 * time.Millisecond)
+
+	if _, ok := cache.Get("key1"); ok {
+		t.Error("Get returned true after expiration, want false")
+	}
+	if size := cache.Size(); size != 0 {
+		t.Errorf("Size after expired Get = %d, want 0", size)
+	}
+}
+
+func TestLRUEviction(t *testing.T) {
+	cache := NewLRU[string](5*time.Second, 2)
+
+	cache.Set("key1", "value1")
+	cache.Set("key2", "value2")
+
+	// Touch key1 so key2 becomes the least recently used
+	cache.Get("key1")
+	cache.Set("key3", "value3")
+
+	if _, ok := cache.Get("key2"); ok {
+		t.Error("Get(key2) returned true, want evicted")
+	}
+	if _, ok := cache.Get("key1"); !ok {
+		t.Error("Get(key1) returned false, want kept as recently used")
+	}
+	if _, ok := cache.Get("key3"); !ok {
+		t.Error("Get(key3) returned false, want kept")
+	}
+	if size := cache.Size(); size != 2 {
+		t.Errorf("Size = %d, want 2", size)
+	}
+
+	// Updating an existing key never evicts
+	cache.Set("key1", "updated")
+	if stats := cache.Stats(); stats.Evictions != 1 {
+		t.Errorf("Evictions = %d, want 1", stats.Evictions)
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	cache := NewLRU[int](50*time.Millisecond, 10)
+
+	cache.Set("a", 1)
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("missing")
+
+	time.Sleep(100 * time.Millisecond)
+	cache.Get("a")
+
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 2 {
+		t.Errorf("Hits/Misses = %d/%d, want 2/2", stats.Hits, stats.Misses)
+	}
+	if stats.Expirations != 1 {
+		t.Errorf("Expirations = %d, want 1", stats.Expirations)
+	}
+	if stats.MaxEntries != 10 || stats.Size != 0 {
+		t.Errorf("MaxEntries/Size = %d/%d, want 10/0", stats.MaxEntries, stats.Size)
+	}
+}
+
+func TestCacheGetOrLoad(t *testing.T) {
+	cache := New[string](5 * time.Second)
+	var calls int32
+
+	loader := func() (string, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return "loaded", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if value, err := cache.GetOrLoad("key", loader); err != nil || value != "loaded" {
+				t.Errorf("GetOrLoad = %q, %v; want loaded, nil", value, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Loader called %d times, want 1", calls)
+	}
+
+	// Cached now: the loader is not called again
+	cache.GetOrLoad("key", loader)
+	if calls != 1 {
+		t.Errorf("Loader called %d times after caching, want 1", calls)
+	}
+}
+
+func TestCacheGetOrLoadError(t *testing.T) {
+	cache := New[string](5 * time.Second)
+	errLoad := errors.New("load failed")
+
+	if _, err := cache.GetOrLoad("key", func() (string, error) { return "", errLoad }); err != errLoad {
+		t.Errorf("GetOrLoad error = %v, want %v", err, errLoad)
+	}
+	if _, ok := cache.Get("key"); ok {
+		t.Error("Get returned true after failed load, want errors not cached")
+	}
+}
+
+func TestCacheGetOrLoadPanic(t *testing.T) {
+	cache := New[string](5 * time.Second)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	panicked := make(chan struct{})
+
+	go func() {
+		defer close(panicked)
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected the loader panic to reach the loading caller")
+			}
+		}()
+		cache.GetOrLoad("key", func() (string, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+
+	<-started
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := cache.GetOrLoad("key", func() (string, error) { return "unused", nil })
+		waiter <- err
+	}()
+	time.Sleep(10 * time.Millisecond) // let the waiter join the load
+	close(release)
+
+	select {
+	case err := <-waiter:
+		if err == nil {
+			t.Error("Expected the waiting caller to get an error from the panicked load")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Waiting caller blocked after the loader panicked")
+	}
+	<-panicked
+
+	// Later callers load again instead of blocking
+	if value, err := cache.GetOrLoad("key", func() (string, error) { return "loaded", nil }); err != nil || value != "loaded" {
+		t.Errorf("GetOrLoad after panic = %q, %v; want loaded, nil", value, err)
+	}
+}
@@ -0,0 +1,304 @@
+package cachex
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedisOptions configures a RedisClient
+type RedisOptions struct {
+	Addr        string        // host:port
+	Password    string        // optional AUTH password
+	DB          int           // database selected with SELECT
+	PoolSize    int           // maximum idle connections kept
+	DialTimeout time.Duration // connect timeout
+	IOTimeout   time.Duration // per-command read/write timeout
+}
+
+// ParseRedisURL parses redis://[:password@]host:port[/db] into options
+func ParseRedisURL(rawURL string) (RedisOptions, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return RedisOptions{}, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	if u.Scheme != "redis" {
+		return RedisOptions{}, fmt.Errorf("unsupported redis URL scheme %q", u.Scheme)
+	}
+
+	opts := RedisOptions{Addr: u.Host}
+	if !strings.Contains(opts.Addr, ":") {
+		opts.Addr += ":6379"
+	}
+	if u.User != nil {
+		opts.Password, _ = u.User.Password()
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if opts.DB, err = strconv.Atoi(db); err != nil {
+			return RedisOptions{}, fmt.Errorf("invalid redis database %q", db)
+		}
+	}
+	return opts, nil
+}
+
+// RedisClient is a minimal client for the Redis protocol (RESP2) with a small
+// connection pool. It supports the commands RedisCache needs.
+type RedisClient struct {
+	opts RedisOptions
+	mu   sync.Mutex
+	idle []*redisConn
+}
+
+type redisConn struct {
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// NewRedisClient creates a client; connections are opened lazily
+func NewRedisClient(opts RedisOptions) *RedisClient {
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = 10
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 2 * time.Second
+	}
+	if opts.IOTimeout <= 0 {
+		opts.IOTimeout = time.Second
+	}
+	return &RedisClient{opts: opts}
+}
+
+// Ping checks that the server is reachable
+func (c *RedisClient) Ping() error {
+	_, err := c.Do("PING")
+	return err
+}
+
+// Close closes all idle connections
+func (c *RedisClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, rc := range c.idle {
+		rc.conn.Close()
+	}
+	c.idle = nil
+	return nil
+}
+
+// Do sends a command and returns the reply: string, int64, nil, []interface{} or an error
+func (c *RedisClient) Do(args ...string) (interface{}, error) {
+	rc, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := rc.do(c.opts.IOTimeout, args...)
+	var serverErr redisError
+	if err != nil && !errors.As(err, &serverErr) {
+		// Connection state is unknown after an I/O error
+		rc.conn.Close()
+		return nil, err
+	}
+
+	c.put(rc)
+	return reply, err
+}
+
+func (c *RedisClient) get() (*redisConn, error) {
+	c.mu.Lock()
+	if n := len(c.idle); n > 0 {
+		rc := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return rc, nil
+	}
+	c.mu.Unlock()
+
+	conn, err := net.DialTimeout("tcp", c.opts.Addr, c.opts.DialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	rc := &redisConn{conn: conn, rd: bufio.NewReader(conn)}
+
+	if c.opts.Password != "" {
+		if _, err := rc.do(c.opts.IOTimeout, "AUTH", c.opts.Password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if c.opts.DB != 0 {
+		if _, err := rc.do(c.opts.IOTimeout, "SELECT", strconv.Itoa(c.opts.DB)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return rc, nil
+}
+
+func (c *RedisClient) put(rc *redisConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.idle) >= c.opts.PoolSize {
+		rc.conn.Close()
+		return
+	}
+	c.idle = append(c.idle, rc)
+}
+
+func (rc *redisConn) do(timeout time.Duration, args ...string) (interface{}, error) {
+	if err := rc.conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(rc.conn, b.String()); err != nil {
+		return nil, err
+	}
+
+	return readReply(rc.rd)
+}
+
+// redisError is an error reply sent by the server
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// readReply parses one RESP2 reply
+func readReply(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readReply(rd); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
+
+// RedisCache is a Backend storing JSON-encoded values in Redis under a key prefix
+type RedisCache[T any] struct {
+	client *RedisClient
+	prefix string
+	ttl    time.Duration
+
+	// OnError, when set, is called with storage errors that were treated as misses
+	OnError func(error)
+}
+
+// NewRedis creates a Redis-backed cache with the specified default TTL
+func NewRedis[T any](client *RedisClient, prefix string, ttl time.Duration) *RedisCache[T] {
+	return &RedisCache[T]{
+		client: client,
+		prefix: prefix,
+		ttl:    ttl,
+	}
+}
+
+// Get retrieves a cached value if it exists and hasn't expired
+func (c *RedisCache[T]) Get(key string) (T, bool) {
+	var zero T
+
+	reply, err := c.client.Do("GET", c.prefix+key)
+	if err != nil {
+		c.report(err)
+		return zero, false
+	}
+	raw, ok := reply.(string)
+	if !ok {
+		return zero, false
+	}
+
+	var value T
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		c.report(err)
+		return zero, false
+	}
+	return value, true
+}
+
+// Set stores a value in the cache with the configured TTL
+func (c *RedisCache[T]) Set(key string, value T) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL stores a value with a custom TTL
+func (c *RedisCache[T]) SetWithTTL(key string, value T, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.report(err)
+		return
+	}
+
+	ms := ttl.Milliseconds()
+	if ms <= 0 {
+		ms = 1
+	}
+	if _, err := c.client.Do("SET", c.prefix+key, string(data), "PX", strconv.FormatInt(ms, 10)); err != nil {
+		c.report(err)
+	}
+}
+
+// Delete removes a value from the cache
+func (c *RedisCache[T]) Delete(key string) {
+	if _, err := c.client.Do("DEL", c.prefix+key); err != nil {
+		c.report(err)
+	}
+}
+
+func (c *RedisCache[T]) report(err error) {
+	if c.OnError != nil {
+		c.OnError(err)
+	}
+}
@@ -0,0 +1,266 @@
+package cachex
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is an in-process server speaking enough RESP for RedisCache
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu      sync.Mutex
+	data    map[string]string
+	expires map[string]time.Time
+}
+
+func startFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	f := &fakeRedis{
+		listener: l,
+		password: password,
+		data:     make(map[string]string),
+		expires:  make(map[string]time.Time),
+	}
+	go f.serve()
+	t.Cleanup(func() { l.Close() })
+	return f
+}
+
+func (f *fakeRedis) addr() string { return f.listener.Addr().String() }
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	rd := bufio.NewReader(conn)
+	authed := f.password == ""
+
+	for {
+		reply, err := readReply(rd)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i], _ = item.(string)
+		}
+		if len(args) == 0 {
+			return
+		}
+
+		cmd := strings.ToUpper(args[0])
+		if !authed && cmd != "AUTH" {
+			fmt.Fprint(conn, "-NOAUTH Authentication required.\r\n")
+			continue
+		}
+		switch cmd {
+		case "AUTH":
+			if len(args) == 2 && args[1] == f.password {
+				authed = true
+				fmt.Fprint(conn, "+OK\r\n")
+			} else {
+				fmt.Fprint(conn, "-WRONGPASS invalid password\r\n")
+			}
+		case "PING":
+			fmt.Fprint(conn, "+PONG\r\n")
+		case "SELECT":
+			fmt.Fprint(conn, "+OK\r\n")
+		case "GET":
+			if value, ok := f.get(args[1]); ok {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+			} else {
+				fmt.Fprint(conn, "$-1\r\n")
+			}
+		case "SET":
+			f.set(args[1], args[2], args[3:])
+			fmt.Fprint(conn, "+OK\r\n")
+		case "DEL":
+			fmt.Fprintf(conn, ":%d\r\n", f.del(args[1:]))
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+		}
+	}
+}
+
+func (f *fakeRedis) get(key string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if exp, ok := f.expires[key]; ok && time.Now().After(exp) {
+		delete(f.data, key)
+		delete(f.expires, key)
+	}
+	value, ok := f.data[key]
+	return value, ok
+}
+
+func (f *fakeRedis) set(key, value string, opts []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.data[key] = value
+	delete(f.expires, key)
+	if len(opts) == 2 && strings.ToUpper(opts[0]) == "PX" {
+		ms, _ := strconv.Atoi(opts[1])
+		f.expires[key] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+	}
+}
+
+func (f *fakeRedis) del(keys []string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	removed := 0
+	for _, key := range keys {
+		if _, ok := f.data[key]; ok {
+			delete(f.data, key)
+			delete(f.expires, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+func TestParseRedisURL(t *testing.T) {
+	opts, err := ParseRedisURL("redis://:secret@cache.internal:6380/2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if opts.Addr != "cache.internal:6380" || opts.Password != "secret" || opts.DB != 2 {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+
+	opts, _ = ParseRedisURL("redis://localhost")
+	if opts.Addr != "localhost:6379" {
+		t.Errorf("Expected default port, got %s", opts.Addr)
+	}
+
+	if _, err := ParseRedisURL("http://localhost"); err == nil {
+		t.Error("Expected error for non-redis scheme")
+	}
+}
+
+func TestRedisCacheSetGetDelete(t *testing.T) {
+	server := startFakeRedis(t, "secret")
+	client := NewRedisClient(RedisOptions{Addr: server.addr(), Password: "secret", DB: 1})
+	defer client.Close()
+
+	if err := client.Ping(); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+
+	type user struct {
+		Name  string
+		Email string
+	}
+	cache := NewRedis[user](client, "users:", time.Minute)
+
+	cache.Set("1", user{Name: "Alice", Email: "alice@example.com"})
+
+	got, ok := cache.Get("1")
+	if !ok || got.Name != "Alice" || got.Email != "alice@example.com" {
+		t.Errorf("Expected Alice, got %+v (found=%v)", got, ok)
+	}
+	if _, ok := server.get("users:1"); !ok {
+		t.Error("Expected the key prefix to be applied")
+	}
+
+	cache.Delete("1")
+	if _, ok := cache.Get("1"); ok {
+		t.Error("Expected deleted key to be missing")
+	}
+}
+
+func TestRedisCacheExpiration(t *testing.T) {
+	server := startFakeRedis(t, "")
+	client := NewRedisClient(RedisOptions{Addr: server.addr()})
+	defer client.Close()
+
+	cache := NewRedis[string](client, "", time.Minute)
+	cache.SetWithTTL("short", "value", 20*time.Millisecond)
+
+	if _, ok := cache.Get("short"); !ok {
+		t.Fatal("Expected key before expiry")
+	}
+	time.Sleep(40 * time.Millisecond)
+	if _, ok := cache.Get("short"); ok {
+		t.Error("Expected key to expire")
+	}
+}
+
+func TestRedisCacheSharedBetweenClients(t *testing.T) {
+	server := startFakeRedis(t, "")
+	replicaA := NewRedis[int](NewRedisClient(RedisOptions{Addr: server.addr()}), "n:", time.Minute)
+	replicaB := NewRedis[int](NewRedisClient(RedisOptions{Addr: server.addr()}), "n:", time.Minute)
+
+	replicaA.Set("counter", 42)
+	if got, ok := replicaB.Get("counter"); !ok || got != 42 {
+		t.Errorf("Expected replica B to see 42, got %d (found=%v)", got, ok)
+	}
+
+	replicaB.Delete("counter")
+	if _, ok := replicaA.Get("counter"); ok {
+		t.Error("Expected a delete on one replica to reach the other")
+	}
+}
+
+func TestRedisCacheErrorsAreMisses(t *testing.T) {
+	server := startFakeRedis(t, "")
+	addr := server.addr()
+	server.listener.Close()
+
+	var reported error
+	cache := NewRedis[string](NewRedisClient(RedisOptions{Addr: addr, DialTimeout: 100 * time.Millisecond}), "", time.Minute)
+	cache.OnError = func(err error) { reported = err }
+
+	cache.Set("key", "value")
+	if _, ok := cache.Get("key"); ok {
+		t.Error("Expected a miss when the server is unreachable")
+	}
+	if reported == nil {
+		t.Error("Expected the storage error to be reported")
+	}
+}
+
+func TestRedisClientConcurrency(t *testing.T) {
+	server := startFakeRedis(t, "")
+	client := NewRedisClient(RedisOptions{Addr: server.addr(), PoolSize: 2})
+	defer client.Close()
+	cache := NewRedis[int](client, "", time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := strconv.Itoa(i)
+			cache.Set(key, i)
+			if got, ok := cache.Get(key); !ok || got != i {
+				t.Errorf("Key %s: expected %d, got %d (found=%v)", key, i, got, ok)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
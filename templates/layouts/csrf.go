@@ -0,0 +1,16 @@
+package layouts
+
+import "context"
+
+type csrfContextKey struct{}
+
+// WithCSRFToken stores the request's CSRF token for rendering by Layout and CSRFField
+func WithCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, csrfContextKey{}, token)
+}
+
+// CSRFToken returns the CSRF token for the current request, or "" when there is no session
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfContextKey{}).(string)
+	return token
+}
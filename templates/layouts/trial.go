@@ -0,0 +1,34 @@
+package layouts
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type trialContextKey struct{}
+
+// WithTrialEnd stores when the signed-in user's free trial ends, for the countdown banner rendered by Layout
+func WithTrialEnd(ctx context.Context, trialEnd time.Time) context.Context {
+	return context.WithValue(ctx, trialContextKey{}, trialEnd)
+}
+
+// TrialEnd returns when the signed-in user's free trial ends, or false when they are not on a trial
+func TrialEnd(ctx context.Context) (time.Time, bool) {
+	trialEnd, ok := ctx.Value(trialContextKey{}).(time.Time)
+	return trialEnd, ok && !trialEnd.IsZero()
+}
+
+// trialCountdown describes the time left on a trial, e.g. "3 days" or "5 hours"
+func trialCountdown(trialEnd, now time.Time) string {
+	left := trialEnd.Sub(now)
+	if left < 24*time.Hour {
+		hours := int((left + time.Hour - 1) / time.Hour)
+		if hours <= 1 {
+			return "less than an hour"
+		}
+		return fmt.Sprintf("%d hours", hours)
+	}
+	days := int((left + 24*time.Hour - 1) / (24 * time.Hour))
+	return fmt.Sprintf("%d days", days)
+}
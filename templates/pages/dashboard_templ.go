@@ -8,9 +8,38 @@ package pages
 import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
-import "github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
+import (
+	"strconv"
 
-func Dashboard(name, email, picture, planStatus string, isPro bool, periodEnd string) templ.Component {
+	"github.com/DraconDev/go-templ-htmx-ex/internal/models"
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
+	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
+)
+
+// UserDashboard is the view model of the user dashboard
+type UserDashboard struct {
+	Name       string
+	Email      string
+	Picture    string
+	PlanStatus string
+	// IsPaid is set while a subscription grants a paid plan
+	IsPaid    bool
+	PeriodEnd string
+	// CancelAtPeriodEnd is set when the subscription ends at PeriodEnd instead of renewing
+	CancelAtPeriodEnd bool
+	// SubscriptionStatus is the latest subscription's status (active, trialing,
+	// past_due or canceled); empty for users who never subscribed
+	SubscriptionStatus string
+	// TrialEnd and TrialDaysLeft are set while the subscription is in a free trial
+	TrialEnd      string
+	TrialDaysLeft int
+	// BillingNotice is shown instead of the upgrade prompt when the plan could not be loaded
+	BillingNotice string
+	// Purchases are the user's most recent completed shop orders
+	Purchases []models.Purchase
+}
+
+func Dashboard(data UserDashboard) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -31,7 +60,7 @@ func Dashboard(name, email, picture, planStatus string, isPro bool, periodEnd st
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = layouts.Layout("Dashboard | Startup Platform", "Manage your account and subscription", layouts.NavigationLoggedIn(layouts.UserInfo{Name: name, Email: email, Picture: picture, LoggedIn: true}), DashboardContent(name, email, picture, planStatus, isPro, periodEnd)).Render(ctx, templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = layouts.Layout("Dashboard | Startup Platform", "Manage your account and subscription", layouts.NavigationLoggedIn(layouts.UserInfo{Name: data.Name, Email: data.Email, Picture: data.Picture, LoggedIn: true}), DashboardContent(data)).Render(ctx, templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -39,7 +68,7 @@ func Dashboard(name, email, picture, planStatus string, isPro bool, periodEnd st
 	})
 }
 
-func DashboardContent(name, email, picture, planStatus string, isPro bool, periodEnd string) templ.Component {
+func DashboardContent(data UserDashboard) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -65,9 +94,9 @@ func DashboardContent(name, email, picture, planStatus string, isPro bool, perio
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var3 string
-		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(name)
+		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(data.Name)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 14, Col: 66}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 43, Col: 71}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 		if templ_7745c5c3_Err != nil {
@@ -78,9 +107,9 @@ func DashboardContent(name, email, picture, planStatus string, isPro bool, perio
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var4 string
-		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(planStatus)
+		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(data.PlanStatus)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 33, Col: 61}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 62, Col: 66}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 		if templ_7745c5c3_Err != nil {
@@ -90,61 +119,271 @@ func DashboardContent(name, email, picture, planStatus string, isPro bool, perio
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if isPro {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "<span class=\"ml-2 px-2 py-0.5 rounded text-xs font-medium bg-green-500/20 text-green-400\">Active</span>")
+		switch {
+		case data.SubscriptionStatus == models.SubscriptionStatusTrialing:
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "<span class=\"ml-2 px-2 py-0.5 rounded text-xs font-medium bg-cyan-500/20 text-cyan-400\">Trial</span>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-		} else {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<span class=\"ml-2 px-2 py-0.5 rounded text-xs font-medium bg-gray-500/20 text-gray-400\">Free</span>")
+		case data.IsPaid:
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<span class=\"ml-2 px-2 py-0.5 rounded text-xs font-medium bg-green-500/20 text-green-400\">Active</span>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		case data.SubscriptionStatus == models.SubscriptionStatusPastDue:
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "<span class=\"ml-2 px-2 py-0.5 rounded text-xs font-medium bg-yellow-500/20 text-yellow-400\">Past due</span>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		case data.BillingNotice == "":
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<span class=\"ml-2 px-2 py-0.5 rounded text-xs font-medium bg-gray-500/20 text-gray-400\">Free</span>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "</div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "</div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if isPro {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<p class=\"text-sm text-gray-400 mt-2\">Renews on ")
+		switch {
+		case data.TrialEnd != "":
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<p class=\"text-sm text-cyan-400 mt-2\">Trial ends on ")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			var templ_7745c5c3_Var5 string
-			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(periodEnd)
+			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(data.TrialEnd)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 41, Col: 64}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 76, Col: 73}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "</p>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, " (")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-		} else {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, "<p class=\"text-sm text-gray-400 mt-2\">Upgrade to unlock all features</p>")
+			var templ_7745c5c3_Var6 string
+			templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(trialDaysLabel(data.TrialDaysLeft))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 76, Col: 113}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, " left)</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		case data.IsPaid && data.CancelAtPeriodEnd:
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "<p class=\"text-sm text-yellow-400 mt-2\">Ends on ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var7 string
+			templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(data.PeriodEnd)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 78, Col: 70}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		case data.IsPaid:
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "<p class=\"text-sm text-gray-400 mt-2\">Renews on ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var8 string
+			templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(data.PeriodEnd)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 80, Col: 70}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		case data.SubscriptionStatus == models.SubscriptionStatusPastDue:
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "<p class=\"text-sm text-yellow-400 mt-2\"><i class=\"fas fa-exclamation-triangle mr-1\"></i> Your last payment failed. Update your payment method to restore access.</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		case data.SubscriptionStatus == models.SubscriptionStatusCanceled && data.PeriodEnd != "":
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "<p class=\"text-sm text-gray-400 mt-2\">Your subscription ended on ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var9 string
+			templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(data.PeriodEnd)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 84, Col: 87}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		case data.BillingNotice != "":
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "<p class=\"text-sm text-yellow-400 mt-2\"><i class=\"fas fa-exclamation-triangle mr-1\"></i> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var10 string
+			templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(data.BillingNotice)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 86, Col: 115}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		default:
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "<p class=\"text-sm text-gray-400 mt-2\">Upgrade to unlock all features</p>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "<div class=\"mt-4\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		switch {
+		case data.IsPaid:
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "<a href=\"#subscription\" class=\"text-cyan-400 hover:text-cyan-300 text-sm font-medium\">Manage Subscription &rarr;</a>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		case data.SubscriptionStatus == models.SubscriptionStatusPastDue:
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "<form action=\"/settings/billing\" method=\"POST\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = layouts.CSRFField().Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "<button type=\"submit\" class=\"text-yellow-400 hover:text-yellow-300 text-sm font-medium\">Update Payment Method &rarr;</button></form>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		case data.SubscriptionStatus == models.SubscriptionStatusCanceled:
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "<a href=\"/pricing\" class=\"text-cyan-400 hover:text-cyan-300 text-sm font-medium\">Resubscribe &rarr;</a>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		default:
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 27, "<a href=\"/pricing\" class=\"text-cyan-400 hover:text-cyan-300 text-sm font-medium\">Upgrade Now &rarr;</a>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 28, "</div></div><!-- Usage Stats (Placeholder) --><div class=\"glass-card p-6 rounded-xl relative overflow-hidden group\"><div class=\"absolute top-0 right-0 p-4 opacity-10 group-hover:opacity-20 transition-opacity\"><i class=\"fas fa-chart-bar text-6xl text-purple-400\"></i></div><h3 class=\"text-gray-400 text-sm font-medium uppercase tracking-wider mb-2\">API Usage</h3><div class=\"flex items-baseline\"><span class=\"text-2xl font-bold text-white\">1,234</span> <span class=\"ml-2 text-sm text-gray-400\">/ 10,000 reqs</span></div><div class=\"w-full bg-gray-700 rounded-full h-1.5 mt-4\"><div class=\"bg-purple-500 h-1.5 rounded-full\" style=\"width: 12%\"></div></div></div><!-- Projects (Placeholder) --><div class=\"glass-card p-6 rounded-xl relative overflow-hidden group\"><div class=\"absolute top-0 right-0 p-4 opacity-10 group-hover:opacity-20 transition-opacity\"><i class=\"fas fa-folder text-6xl text-pink-400\"></i></div><h3 class=\"text-gray-400 text-sm font-medium uppercase tracking-wider mb-2\">Active Projects</h3><div class=\"flex items-baseline\"><span class=\"text-2xl font-bold text-white\">3</span> <span class=\"ml-2 text-sm text-gray-400\">projects</span></div><div class=\"mt-4\"><a href=\"/projects\" class=\"text-pink-400 hover:text-pink-300 text-sm font-medium\">View All Projects &rarr;</a></div></div></div><!-- Recent Activity / Quick Actions --><div class=\"grid grid-cols-1 lg:grid-cols-3 gap-8\"><!-- Main Content Area --><div class=\"lg:col-span-2\"><div class=\"glass-card rounded-xl p-6\"><h3 class=\"text-lg font-semibold text-white mb-4\">Getting Started</h3><div class=\"space-y-4\"><div class=\"flex items-start p-4 rounded-lg bg-white/5 hover:bg-white/10 transition-colors cursor-pointer\"><div class=\"flex-shrink-0 p-2 rounded-lg bg-cyan-500/20 text-cyan-400\"><i class=\"fas fa-rocket\"></i></div><div class=\"ml-4\"><h4 class=\"text-white font-medium\">Create your first project</h4><p class=\"text-gray-400 text-sm mt-1\">Start building your SaaS application with our templates.</p></div></div><div class=\"flex items-start p-4 rounded-lg bg-white/5 hover:bg-white/10 transition-colors cursor-pointer\"><div class=\"flex-shrink-0 p-2 rounded-lg bg-purple-500/20 text-purple-400\"><i class=\"fas fa-key\"></i></div><div class=\"ml-4\"><h4 class=\"text-white font-medium\">Generate API Keys</h4><p class=\"text-gray-400 text-sm mt-1\">Create secure access keys for external integrations.</p></div></div></div></div>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if data.IsPaid {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 29, "<!-- Subscription --> <div id=\"subscription\" class=\"glass-card rounded-xl p-6 mt-8\"><h3 class=\"text-lg font-semibold text-white mb-4\">Subscription</h3><div hx-get=\"/account/subscription\" hx-trigger=\"load\" hx-swap=\"outerHTML\"><p class=\"text-gray-400 text-sm\">Loading subscription...</p></div></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "<div class=\"mt-4\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 30, "<!-- Purchases --><div class=\"glass-card rounded-xl p-6 mt-8\"><div class=\"flex items-center justify-between mb-4\"><h3 class=\"text-lg font-semibold text-white\">Recent Purchases</h3><a href=\"/shop\" class=\"text-cyan-400 hover:text-cyan-300 text-sm font-medium\">Visit Shop &rarr;</a></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if isPro {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "<a href=\"/billing\" class=\"text-cyan-400 hover:text-cyan-300 text-sm font-medium\">Manage Subscription &rarr;</a>")
+		if len(data.Purchases) == 0 {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 31, "<p class=\"text-gray-400 text-sm\">You haven't bought anything yet.</p>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		} else {
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "<a href=\"/pricing\" class=\"text-cyan-400 hover:text-cyan-300 text-sm font-medium\">Upgrade Now &rarr;</a>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 32, "<table class=\"w-full text-left text-sm\"><thead><tr class=\"text-gray-400 border-b border-white/10\"><th class=\"py-2\">Item</th><th class=\"py-2\">Qty</th><th class=\"py-2\">Date</th><th class=\"py-2 text-right\">Total</th></tr></thead> <tbody>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			for _, purchase := range data.Purchases {
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 33, "<tr class=\"border-b border-white/5 text-gray-300\"><td class=\"py-2 text-white\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var11 string
+				templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(purchase.ItemName)
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 197, Col: 57}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 34, "</td><td class=\"py-2\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var12 string
+				templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(strconv.Itoa(purchase.Quantity))
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 198, Col: 60}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 35, "</td><td class=\"py-2\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				if purchase.CompletedAt != nil {
+					var templ_7745c5c3_Var13 string
+					templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs(purchase.CompletedAt.Format("Jan 02, 2006"))
+					if templ_7745c5c3_Err != nil {
+						return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 201, Col: 57}
+					}
+					_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 36, "</td><td class=\"py-2 text-right\">")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				var templ_7745c5c3_Var14 string
+				templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs(catalog.FormatAmount(purchase.Total(), purchase.Currency))
+				if templ_7745c5c3_Err != nil {
+					return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/dashboard.templ`, Line: 204, Col: 97}
+				}
+				_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+				templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 37, "</td></tr>")
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 38, "</tbody></table>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "</div></div><!-- Usage Stats (Placeholder) --><div class=\"glass-card p-6 rounded-xl relative overflow-hidden group\"><div class=\"absolute top-0 right-0 p-4 opacity-10 group-hover:opacity-20 transition-opacity\"><i class=\"fas fa-chart-bar text-6xl text-purple-400\"></i></div><h3 class=\"text-gray-400 text-sm font-medium uppercase tracking-wider mb-2\">API Usage</h3><div class=\"flex items-baseline\"><span class=\"text-2xl font-bold text-white\">1,234</span> <span class=\"ml-2 text-sm text-gray-400\">/ 10,000 reqs</span></div><div class=\"w-full bg-gray-700 rounded-full h-1.5 mt-4\"><div class=\"bg-purple-500 h-1.5 rounded-full\" style=\"width: 12%\"></div></div></div><!-- Projects (Placeholder) --><div class=\"glass-card p-6 rounded-xl relative overflow-hidden group\"><div class=\"absolute top-0 right-0 p-4 opacity-10 group-hover:opacity-20 transition-opacity\"><i class=\"fas fa-folder text-6xl text-pink-400\"></i></div><h3 class=\"text-gray-400 text-sm font-medium uppercase tracking-wider mb-2\">Active Projects</h3><div class=\"flex items-baseline\"><span class=\"text-2xl font-bold text-white\">3</span> <span class=\"ml-2 text-sm text-gray-400\">projects</span></div><div class=\"mt-4\"><a href=\"/projects\" class=\"text-pink-400 hover:text-pink-300 text-sm font-medium\">View All Projects &rarr;</a></div></div></div><!-- Recent Activity / Quick Actions --><div class=\"grid grid-cols-1 lg:grid-cols-3 gap-8\"><!-- Main Content Area --><div class=\"lg:col-span-2\"><div class=\"glass-card rounded-xl p-6\"><h3 class=\"text-lg font-semibold text-white mb-4\">Getting Started</h3><div class=\"space-y-4\"><div class=\"flex items-start p-4 rounded-lg bg-white/5 hover:bg-white/10 transition-colors cursor-pointer\"><div class=\"flex-shrink-0 p-2 rounded-lg bg-cyan-500/20 text-cyan-400\"><i class=\"fas fa-rocket\"></i></div><div class=\"ml-4\"><h4 class=\"text-white font-medium\">Create your first project</h4><p class=\"text-gray-400 text-sm mt-1\">Start building your SaaS application with our templates.</p></div></div><div class=\"flex items-start p-4 rounded-lg bg-white/5 hover:bg-white/10 transition-colors cursor-pointer\"><div class=\"flex-shrink-0 p-2 rounded-lg bg-purple-500/20 text-purple-400\"><i class=\"fas fa-key\"></i></div><div class=\"ml-4\"><h4 class=\"text-white font-medium\">Generate API Keys</h4><p class=\"text-gray-400 text-sm mt-1\">Create secure access keys for external integrations.</p></div></div></div></div></div><!-- Sidebar --><div class=\"lg:col-span-1\"><div class=\"glass-card rounded-xl p-6\"><h3 class=\"text-lg font-semibold text-white mb-4\">Quick Links</h3><nav class=\"space-y-2\"><a href=\"/docs\" class=\"block px-4 py-2 rounded-lg text-gray-300 hover:bg-white/5 hover:text-white transition-colors\"><i class=\"fas fa-book w-6 text-center mr-2\"></i> Documentation</a> <a href=\"/support\" class=\"block px-4 py-2 rounded-lg text-gray-300 hover:bg-white/5 hover:text-white transition-colors\"><i class=\"fas fa-life-ring w-6 text-center mr-2\"></i> Support</a> <a href=\"https://github.com/DraconDev\" target=\"_blank\" class=\"block px-4 py-2 rounded-lg text-gray-300 hover:bg-white/5 hover:text-white transition-colors\"><i class=\"fab fa-github w-6 text-center mr-2\"></i> GitHub Repo</a></nav></div></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 39, "</div></div><!-- Sidebar --><div class=\"lg:col-span-1\"><div class=\"glass-card rounded-xl p-6\"><h3 class=\"text-lg font-semibold text-white mb-4\">Quick Links</h3><nav class=\"space-y-2\"><a href=\"/shop\" class=\"block px-4 py-2 rounded-lg text-gray-300 hover:bg-white/5 hover:text-white transition-colors\"><i class=\"fas fa-store w-6 text-center mr-2\"></i> Shop</a> <a href=\"/docs\" class=\"block px-4 py-2 rounded-lg text-gray-300 hover:bg-white/5 hover:text-white transition-colors\"><i class=\"fas fa-book w-6 text-center mr-2\"></i> Documentation</a> <a href=\"/support\" class=\"block px-4 py-2 rounded-lg text-gray-300 hover:bg-white/5 hover:text-white transition-colors\"><i class=\"fas fa-life-ring w-6 text-center mr-2\"></i> Support</a> <a href=\"https://github.com/DraconDev\" target=\"_blank\" class=\"block px-4 py-2 rounded-lg text-gray-300 hover:bg-white/5 hover:text-white transition-colors\"><i class=\"fab fa-github w-6 text-center mr-2\"></i> GitHub Repo</a></nav></div><div class=\"glass-card rounded-xl p-6 mt-8\"><h3 class=\"text-lg font-semibold text-white mb-4\">Subscriptions</h3><div hx-get=\"/account/subscriptions\" hx-trigger=\"load\" hx-swap=\"outerHTML\"><p class=\"text-gray-400 text-sm\">Loading subscriptions...</p></div></div></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -152,4 +391,12 @@ func DashboardContent(name, email, picture, planStatus string, isPro bool, perio
 	})
 }
 
+// trialDaysLabel renders a day count, e.g. "1 day" or "5 days"
+func trialDaysLabel(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return strconv.Itoa(days) + " days"
+}
+
 var _ = templruntime.GeneratedTemplate
@@ -9,11 +9,12 @@ import "github.com/a-h/templ"
 import templruntime "github.com/a-h/templ/runtime"
 
 import (
+	"github.com/DraconDev/go-templ-htmx-ex/internal/utils/catalog"
 	"github.com/DraconDev/go-templ-htmx-ex/templates/layouts"
 )
 
 // PaymentContent renders the payment page content
-func PaymentContent(userInfo layouts.UserInfo) templ.Component {
+func PaymentContent(userInfo layouts.UserInfo, plans []catalog.Plan) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -34,7 +35,234 @@ func PaymentContent(userInfo layouts.UserInfo) templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"max-w-4xl mx-auto\"><div class=\"text-center mb-12\"><h1 class=\"text-4xl font-bold text-white mb-4\">Subscribe to Premium</h1><p class=\"text-gray-300 text-lg\">Get access to exclusive features and content</p></div><div class=\"grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-8\"><!-- Premium Plan --><div class=\"glass-card rounded-2xl p-8 border-2 border-cyan-500/30 relative\"><div class=\"absolute -top-4 left-1/2 transform -translate-x-1/2\"><span class=\"bg-gradient-to-r from-cyan-500 to-blue-600 text-white px-4 py-2 rounded-full text-sm font-semibold\">Most Popular</span></div><div class=\"text-center mb-6\"><h3 class=\"text-2xl font-bold text-white mb-2\">Premium Plan</h3><div class=\"text-4xl font-bold text-white mb-2\">$29<span class=\"text-lg text-gray-400\">/month</span></div><p class=\"text-gray-400\">Everything you need</p></div><ul class=\"space-y-3 mb-8\"><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> Unlimited access to all content</li><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> Priority support</li><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> Advanced analytics</li><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> API access</li><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> Custom integrations</li></ul><button onclick=\"initiatePayment('premium')\" class=\"w-full bg-gradient-to-r from-cyan-500 to-blue-600 hover:from-cyan-600 hover:to-blue-700 text-white font-semibold py-3 px-6 rounded-lg transition-all duration-200 transform hover:scale-105\">Subscribe Now</button></div><!-- Basic Plan --><div class=\"glass-card rounded-2xl p-8\"><div class=\"text-center mb-6\"><h3 class=\"text-2xl font-bold text-white mb-2\">Basic Plan</h3><div class=\"text-4xl font-bold text-white mb-2\">$9<span class=\"text-lg text-gray-400\">/month</span></div><p class=\"text-gray-400\">Perfect for getting started</p></div><ul class=\"space-y-3 mb-8\"><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> Access to core features</li><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> Email support</li><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> Basic analytics</li><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> 5GB storage</li></ul><button onclick=\"initiatePayment('basic')\" class=\"w-full bg-gray-700 hover:bg-gray-600 text-white font-semibold py-3 px-6 rounded-lg transition-all duration-200\">Subscribe Now</button></div><!-- Enterprise Plan --><div class=\"glass-card rounded-2xl p-8\"><div class=\"text-center mb-6\"><h3 class=\"text-2xl font-bold text-white mb-2\">Enterprise Plan</h3><div class=\"text-4xl font-bold text-white mb-2\">$99<span class=\"text-lg text-gray-400\">/month</span></div><p class=\"text-gray-400\">For teams and businesses</p></div><ul class=\"space-y-3 mb-8\"><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> Everything in Premium</li><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> Team management</li><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> Priority support</li><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> White-label options</li><li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> Unlimited storage</li></ul><button onclick=\"initiatePayment('enterprise')\" class=\"w-full bg-purple-600 hover:bg-purple-700 text-white font-semibold py-3 px-6 rounded-lg transition-all duration-200\">Contact Sales</button></div></div><!-- Current Status --><div class=\"mt-12 glass-card rounded-2xl p-6\"><h3 class=\"text-xl font-bold text-white mb-4\">Your Current Status</h3><div class=\"flex items-center justify-between\"><div><p class=\"text-gray-300\">You are currently on the <span class=\"text-cyan-400 font-semibold\">Free Plan</span></p><p class=\"text-gray-400 text-sm mt-1\">Upgrade to unlock premium features</p></div><div class=\"text-right\"><span class=\"bg-yellow-500/20 text-yellow-400 px-3 py-1 rounded-full text-sm font-semibold\">Free</span></div></div></div></div><script>\n\t\t// Product configurations - In production, these would come from your backend\n\t\tconst products = {\n\t\t\tpremium: {\n\t\t\t\tproductId: 'prod_premium_123',\n\t\t\t\tpriceId: 'price_premium_monthly_123',\n\t\t\t\tname: 'Premium Plan',\n\t\t\t\tprice: '$29/month'\n\t\t\t},\n\t\t\tbasic: {\n\t\t\t\tproductId: 'prod_basic_123',\n\t\t\t\tpriceId: 'price_basic_monthly_123',\n\t\t\t\tname: 'Basic Plan',\n\t\t\t\tprice: '$9/month'\n\t\t\t},\n\t\t\tenterprise: {\n\t\t\t\tproductId: 'prod_enterprise_123',\n\t\t\t\tpriceId: 'price_enterprise_monthly_123',\n\t\t\t\tname: 'Enterprise Plan',\n\t\t\t\tprice: '$99/month'\n\t\t\t}\n\t\t};\n\n\t\tfunction initiatePayment(planType) {\n\t\t\tconst product = products[planType];\n\t\t\tif (!product) {\n\t\t\t\talert('Invalid plan selected');\n\t\t\t\treturn;\n\t\t\t}\n\n\t\t\t// Show loading state\n\t\t\tevent.target.disabled = true;\n\t\t\tevent.target.innerHTML = 'Processing...';\n\n\t\t\t// Call our API to create checkout session\n\t\t\tfetch('/api/payment/checkout', {\n\t\t\t\tmethod: 'POST',\n\t\t\t\theaders: {\n\t\t\t\t\t'Content-Type': 'application/json'\n\t\t\t\t},\n\t\t\t\tbody: JSON.stringify({\n\t\t\t\t\tprice_id: product.priceId,\n\t\t\t\t\tproduct_id: product.productId,\n\t\t\t\t\tsuccess_url: window.location.origin + '/payment/success',\n\t\t\t\t\tcancel_url: window.location.origin + '/payment/cancel'\n\t\t\t\t})\n\t\t\t})\n\t\t\t.then(response => response.json())\n\t\t\t.then(data => {\n\t\t\t\tif (data.checkout_url) {\n\t\t\t\t\t// Redirect to Stripe checkout\n\t\t\t\t\twindow.location.href = data.checkout_url;\n\t\t\t\t} else {\n\t\t\t\t\tthrow new Error(data.error || 'Failed to create checkout session');\n\t\t\t\t}\n\t\t\t})\n\t\t\t.catch(error => {\n\t\t\t\tconsole.error('Payment error:', error);\n\t\t\t\talert('Payment failed: ' + error.message);\n\t\t\t\t\n\t\t\t\t// Reset button state\n\t\t\t\tevent.target.disabled = false;\n\t\t\t\tevent.target.innerHTML = 'Subscribe Now';\n\t\t\t});\n\t\t}\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"max-w-4xl mx-auto\"><div class=\"text-center mb-12\"><h1 class=\"text-4xl font-bold text-white mb-4\">Subscribe to Premium</h1><p class=\"text-gray-300 text-lg\">Get access to exclusive features and content</p></div><input type=\"hidden\" id=\"pricing-interval\" name=\"interval\" value=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var2 string
+		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(catalog.IntervalMonth)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/payment.templ`, Line: 16, Col: 90}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = PromotionCodeField().Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<div class=\"grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-8\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		for _, plan := range plans {
+			if !plan.Free() {
+				templ_7745c5c3_Err = PaymentPlanCard(plan).Render(ctx, templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err != nil {
+					return templ_7745c5c3_Err
+				}
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</div><!-- Current Status --><div class=\"mt-12 glass-card rounded-2xl p-6\"><h3 class=\"text-xl font-bold text-white mb-4\">Your Current Status</h3><div class=\"flex items-center justify-between\"><div><p class=\"text-gray-300\">You are currently on the <span class=\"text-cyan-400 font-semibold\">Free Plan</span></p><p class=\"text-gray-400 text-sm mt-1\">Upgrade to unlock premium features</p></div><div class=\"text-right\"><span class=\"bg-yellow-500/20 text-yellow-400 px-3 py-1 rounded-full text-sm font-semibold\">Free</span></div></div></div></div><script>\n\t\t// The server picks the price ID for the button's plan and interval\n\t\tfunction initiatePayment(button) {\n\t\t\tconst plan = button.dataset.plan;\n\t\t\tif (!plan) {\n\t\t\t\talert('Invalid plan selected');\n\t\t\t\treturn;\n\t\t\t}\n\n\t\t\t// Show loading state\n\t\t\tbutton.disabled = true;\n\t\t\tbutton.innerHTML = 'Processing...';\n\n\t\t\t// Call our API to create checkout session\n\t\t\tfetch('/api/payment/checkout', {\n\t\t\t\tmethod: 'POST',\n\t\t\t\theaders: {\n\t\t\t\t\t'Content-Type': 'application/json',\n\t\t\t\t\t'X-CSRF-Token': csrfToken()\n\t\t\t\t},\n\t\t\t\tbody: JSON.stringify({\n\t\t\t\t\tplan: plan,\n\t\t\t\t\tinterval: button.dataset.interval,\n\t\t\t\t\tpromotion_code: document.getElementById('promotion-code').value,\n\t\t\t\t\tsuccess_url: window.location.origin + '/payment/success',\n\t\t\t\t\tcancel_url: window.location.origin + '/payment/cancel'\n\t\t\t\t})\n\t\t\t})\n\t\t\t.then(response => response.json())\n\t\t\t.then(data => {\n\t\t\t\tif (data.checkout_url) {\n\t\t\t\t\t// Redirect to Stripe checkout\n\t\t\t\t\twindow.location.href = data.checkout_url;\n\t\t\t\t} else {\n\t\t\t\t\tthrow new Error(data.error || 'Failed to create checkout session');\n\t\t\t\t}\n\t\t\t})\n\t\t\t.catch(error => {\n\t\t\t\tconsole.error('Payment error:', error);\n\t\t\t\talert('Payment failed: ' + error.message);\n\t\t\t\t\n\t\t\t\t// Reset button state\n\t\t\t\tbutton.disabled = false;\n\t\t\t\tbutton.innerHTML = 'Subscribe Now';\n\t\t\t});\n\t\t}\n\t</script>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		return nil
+	})
+}
+
+// PaymentPlanCard renders one paid catalog plan
+func PaymentPlanCard(plan catalog.Plan) templ.Component {
+	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
+			return templ_7745c5c3_CtxErr
+		}
+		templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+		if !templ_7745c5c3_IsBuffer {
+			defer func() {
+				templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+				if templ_7745c5c3_Err == nil {
+					templ_7745c5c3_Err = templ_7745c5c3_BufErr
+				}
+			}()
+		}
+		ctx = templ.InitializeContext(ctx)
+		templ_7745c5c3_Var3 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var3 == nil {
+			templ_7745c5c3_Var3 = templ.NopComponent
+		}
+		ctx = templ.ClearChildren(ctx)
+		var templ_7745c5c3_Var4 = []any{paymentCardClass(plan)}
+		templ_7745c5c3_Err = templ.RenderCSSItems(ctx, templ_7745c5c3_Buffer, templ_7745c5c3_Var4...)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "<div class=\"")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var5 string
+		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(templ.CSSClasses(templ_7745c5c3_Var4).String())
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/payment.templ`, Line: 1, Col: 0}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if plan.Highlight {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, "<div class=\"absolute -top-4 left-1/2 transform -translate-x-1/2\"><span class=\"bg-gradient-to-r from-cyan-500 to-blue-600 text-white px-4 py-2 rounded-full text-sm font-semibold\">Most Popular</span></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 8, "<div class=\"text-center mb-6\"><h3 class=\"text-2xl font-bold text-white mb-2\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var6 string
+		templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(plan.Label)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/payment.templ`, Line: 101, Col: 62}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 9, " Plan</h3>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if plan.Monthly != nil {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 10, "<div class=\"text-4xl font-bold text-white mb-2\">")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var7 string
+			templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(plan.Monthly.Display())
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/payment.templ`, Line: 103, Col: 76}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 11, "<span class=\"text-lg text-gray-400\">/month</span></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		} else {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 12, "<div class=\"text-4xl font-bold text-white mb-2\">Custom</div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 13, "<p class=\"text-gray-400\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		var templ_7745c5c3_Var8 string
+		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(plan.Description)
+		if templ_7745c5c3_Err != nil {
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/payment.templ`, Line: 107, Col: 46}
+		}
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 14, "</p></div><ul class=\"space-y-3 mb-8\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		for _, feature := range plan.Features {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 15, "<li class=\"flex items-center text-gray-300\"><svg class=\"w-5 h-5 text-green-500 mr-3\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M5 13l4 4L19 7\"></path></svg> ")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var9 string
+			templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(feature)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/payment.templ`, Line: 116, Col: 14}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "</li>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 17, "</ul>")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		if plan.ContactURL != "" {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 18, "<a href=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var10 templ.SafeURL
+			templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinURLErrs(templ.SafeURL(plan.ContactURL))
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/payment.templ`, Line: 122, Col: 43}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 19, "\" class=\"block w-full text-center bg-purple-600 hover:bg-purple-700 text-white font-semibold py-3 px-6 rounded-lg transition-all duration-200\">Contact Sales</a>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		} else if plan.Purchasable(catalog.IntervalMonth) {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 20, "<button onclick=\"initiatePayment(this)\" data-plan=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var11 string
+			templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(plan.Name)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/payment.templ`, Line: 128, Col: 25}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 21, "\" data-interval=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var12 string
+			templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs(catalog.IntervalMonth)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/payment.templ`, Line: 129, Col: 41}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 22, "\" class=\"w-full bg-gradient-to-r from-cyan-500 to-blue-600 hover:from-cyan-600 hover:to-blue-700 text-white font-semibold py-3 px-6 rounded-lg transition-all duration-200 transform hover:scale-105\">Subscribe Now</button>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		} else {
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "<button disabled class=\"w-full bg-gray-700 text-white font-semibold py-3 px-6 rounded-lg opacity-50 cursor-not-allowed\">Coming Soon</button>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "</div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -42,6 +270,13 @@ func PaymentContent(userInfo layouts.UserInfo) templ.Component {
 	})
 }
 
+func paymentCardClass(plan catalog.Plan) string {
+	if plan.Highlight {
+		return "glass-card rounded-2xl p-8 border-2 border-cyan-500/30 relative"
+	}
+	return "glass-card rounded-2xl p-8 relative"
+}
+
 // PaymentSuccessContent renders the payment success page content
 func PaymentSuccessContent() templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
@@ -59,12 +294,12 @@ func PaymentSuccessContent() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var2 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var2 == nil {
-			templ_7745c5c3_Var2 = templ.NopComponent
+		templ_7745c5c3_Var13 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var13 == nil {
+			templ_7745c5c3_Var13 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div class=\"max-w-2xl mx-auto text-center\"><div class=\"glass-card rounded-2xl p-12\"><div class=\"mb-8\"><div class=\"w-20 h-20 bg-green-500 rounded-full flex items-center justify-center mx-auto mb-6\"><svg class=\"w-10 h-10 text-white\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"3\" d=\"M5 13l4 4L19 7\"></path></svg></div><h1 class=\"text-3xl font-bold text-white mb-4\">Payment Successful!</h1><p class=\"text-gray-300 text-lg\">Thank you for your subscription. Your premium features are now active.</p></div><div class=\"space-y-4\"><a href=\"/profile\" class=\"inline-block bg-gradient-to-r from-cyan-500 to-blue-600 hover:from-cyan-600 hover:to-blue-700 text-white font-semibold py-3 px-8 rounded-lg transition-all duration-200\">View Profile</a> <a href=\"/\" class=\"inline-block bg-gray-700 hover:bg-gray-600 text-white font-semibold py-3 px-8 rounded-lg transition-all duration-200 ml-4\">Go Home</a></div><div class=\"mt-8 p-4 bg-green-500/10 border border-green-500/20 rounded-lg\"><p class=\"text-green-400 text-sm\">Your subscription will be active immediately. You can manage your billing in your profile.</p></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 25, "<div class=\"max-w-2xl mx-auto text-center\"><div class=\"glass-card rounded-2xl p-12\"><div class=\"mb-8\"><div class=\"w-20 h-20 bg-green-500 rounded-full flex items-center justify-center mx-auto mb-6\"><svg class=\"w-10 h-10 text-white\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"3\" d=\"M5 13l4 4L19 7\"></path></svg></div><h1 class=\"text-3xl font-bold text-white mb-4\">Payment Successful!</h1><p class=\"text-gray-300 text-lg\">Thank you for your subscription. Your premium features are now active.</p></div><div class=\"space-y-4\"><a href=\"/profile\" class=\"inline-block bg-gradient-to-r from-cyan-500 to-blue-600 hover:from-cyan-600 hover:to-blue-700 text-white font-semibold py-3 px-8 rounded-lg transition-all duration-200\">View Profile</a> <a href=\"/\" class=\"inline-block bg-gray-700 hover:bg-gray-600 text-white font-semibold py-3 px-8 rounded-lg transition-all duration-200 ml-4\">Go Home</a></div><div class=\"mt-8 p-4 bg-green-500/10 border border-green-500/20 rounded-lg\"><p class=\"text-green-400 text-sm\">Your subscription will be active immediately. You can manage your billing in your profile.</p></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -89,12 +324,12 @@ func PaymentCancelContent() templ.Component {
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var3 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var3 == nil {
-			templ_7745c5c3_Var3 = templ.NopComponent
+		templ_7745c5c3_Var14 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var14 == nil {
+			templ_7745c5c3_Var14 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 3, "<div class=\"max-w-2xl mx-auto text-center\"><div class=\"glass-card rounded-2xl p-12\"><div class=\"mb-8\"><div class=\"w-20 h-20 bg-yellow-500 rounded-full flex items-center justify-center mx-auto mb-6\"><svg class=\"w-10 h-10 text-white\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"3\" d=\"M6 18L18 6M6 6l12 12\"></path></svg></div><h1 class=\"text-3xl font-bold text-white mb-4\">Payment Cancelled</h1><p class=\"text-gray-300 text-lg\">No worries! You can try again anytime to unlock premium features.</p></div><div class=\"space-y-4\"><a href=\"/payment\" class=\"inline-block bg-gradient-to-r from-cyan-500 to-blue-600 hover:from-cyan-600 hover:to-blue-700 text-white font-semibold py-3 px-8 rounded-lg transition-all duration-200\">Try Again</a> <a href=\"/\" class=\"inline-block bg-gray-700 hover:bg-gray-600 text-white font-semibold py-3 px-8 rounded-lg transition-all duration-200 ml-4\">Go Home</a></div><div class=\"mt-8 p-4 bg-blue-500/10 border border-blue-500/20 rounded-lg\"><p class=\"text-blue-400 text-sm\">Need help? Contact our support team and we'll assist you with your purchase.</p></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 26, "<div class=\"max-w-2xl mx-auto text-center\"><div class=\"glass-card rounded-2xl p-12\"><div class=\"mb-8\"><div class=\"w-20 h-20 bg-yellow-500 rounded-full flex items-center justify-center mx-auto mb-6\"><svg class=\"w-10 h-10 text-white\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"3\" d=\"M6 18L18 6M6 6l12 12\"></path></svg></div><h1 class=\"text-3xl font-bold text-white mb-4\">Payment Cancelled</h1><p class=\"text-gray-300 text-lg\">No worries! You can try again anytime to unlock premium features.</p></div><div class=\"space-y-4\"><a href=\"/payment\" class=\"inline-block bg-gradient-to-r from-cyan-500 to-blue-600 hover:from-cyan-600 hover:to-blue-700 text-white font-semibold py-3 px-8 rounded-lg transition-all duration-200\">Try Again</a> <a href=\"/\" class=\"inline-block bg-gray-700 hover:bg-gray-600 text-white font-semibold py-3 px-8 rounded-lg transition-all duration-200 ml-4\">Go Home</a></div><div class=\"mt-8 p-4 bg-blue-500/10 border border-blue-500/20 rounded-lg\"><p class=\"text-blue-400 text-sm\">Need help? Contact our support team and we'll assist you with your purchase.</p></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -157,7 +157,7 @@ func ProfileContent(name, email, picture string) templ.Component {
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "</div></div><div class=\"glass-card rounded-xl p-4 shadow-lg border border-gray-500/30\"><div class=\"flex items-center justify-between\"><span class=\"text-gray-300 font-medium\">Authentication</span> <span class=\"text-cyan-400 font-semibold flex items-center\"><div class=\"w-2 h-2 bg-cyan-500 rounded-full mr-2\"></div>OAuth 2.0</span></div></div></div></div></section><!-- Action Buttons --><section class=\"mt-8 flex flex-col sm:flex-row gap-4\" aria-label=\"Profile actions\"><button hx-post=\"/api/auth/logout\" hx-swap=\"none\" class=\"flex-1 bg-gradient-to-r from-red-500 to-red-600 hover:from-red-400 hover:to-red-500 text-white font-semibold py-3 px-6 rounded-xl text-center transition-all duration-300 transform hover:scale-105 shadow-lg glow-effect\">Logout</button> <a href=\"/\" class=\"flex-1 bg-gradient-to-r from-cyan-500 to-purple-600 hover:from-cyan-400 hover:to-purple-500 text-white font-semibold py-3 px-6 rounded-xl text-center transition-all duration-300 transform hover:scale-105 shadow-lg glow-effect inline-block\">Back to Home</a></section></div></section></main>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 16, "</div></div><div class=\"glass-card rounded-xl p-4 shadow-lg border border-gray-500/30\"><div class=\"flex items-center justify-between\"><span class=\"text-gray-300 font-medium\">Authentication</span> <span class=\"text-cyan-400 font-semibold flex items-center\"><div class=\"w-2 h-2 bg-cyan-500 rounded-full mr-2\"></div>OAuth 2.0</span></div></div></div></div></section><!-- Subscriptions across projects --><section class=\"glass-card rounded-2xl p-6 border border-gray-400/30 mt-8\" aria-label=\"Subscriptions\"><h3 class=\"text-xl font-bold text-white mb-6 flex items-center\"><div class=\"w-8 h-8 bg-purple-500 rounded-lg flex items-center justify-center mr-3 glow-effect\"><span class=\"text-white text-sm\">💳</span></div>Subscriptions</h3><div hx-get=\"/account/subscriptions\" hx-trigger=\"load\" hx-swap=\"outerHTML\"><p class=\"text-gray-400 text-sm\">Loading subscriptions...</p></div></section><!-- Action Buttons --><section class=\"mt-8 flex flex-col sm:flex-row gap-4\" aria-label=\"Profile actions\"><button hx-post=\"/api/auth/logout\" hx-swap=\"none\" class=\"flex-1 bg-gradient-to-r from-red-500 to-red-600 hover:from-red-400 hover:to-red-500 text-white font-semibold py-3 px-6 rounded-xl text-center transition-all duration-300 transform hover:scale-105 shadow-lg glow-effect\">Logout</button> <a href=\"/\" class=\"flex-1 bg-gradient-to-r from-cyan-500 to-purple-600 hover:from-cyan-400 hover:to-purple-500 text-white font-semibold py-3 px-6 rounded-xl text-center transition-all duration-300 transform hover:scale-105 shadow-lg glow-effect inline-block\">Back to Home</a></section></div></section></main>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
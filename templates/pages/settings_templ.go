@@ -63,14 +63,14 @@ func SettingsContent(userInfo layouts.UserInfo, prefs *models.UserPreferences) t
 			templ_7745c5c3_Var2 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"max-w-4xl mx-auto px-4 sm:px-6 lg:px-8 py-12\"><h1 class=\"text-3xl font-bold text-white mb-8\">Settings</h1><div class=\"glass-card rounded-2xl overflow-hidden\" x-data=\"{ tab: 'account' }\"><!-- Tabs Header --><div class=\"flex border-b border-gray-700\"><button @click=\"tab = 'account'\" :class=\"{ 'text-cyan-400 border-cyan-400': tab === 'account', 'text-gray-400 border-transparent hover:text-gray-200': tab !== 'account' }\" class=\"px-6 py-4 text-sm font-medium border-b-2 transition-colors duration-200\">Account</button> <button @click=\"tab = 'notifications'\" :class=\"{ 'text-cyan-400 border-cyan-400': tab === 'notifications', 'text-gray-400 border-transparent hover:text-gray-200': tab !== 'notifications' }\" class=\"px-6 py-4 text-sm font-medium border-b-2 transition-colors duration-200\">Notifications</button> <button @click=\"tab = 'billing'\" :class=\"{ 'text-cyan-400 border-cyan-400': tab === 'billing', 'text-gray-400 border-transparent hover:text-gray-200': tab !== 'billing' }\" class=\"px-6 py-4 text-sm font-medium border-b-2 transition-colors duration-200\">Billing</button></div><!-- Account Tab --><div x-show=\"tab === 'account'\" class=\"p-6 space-y-6\"><div class=\"flex items-center space-x-4 mb-8\"><img src=\"")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"max-w-4xl mx-auto px-4 sm:px-6 lg:px-8 py-12\"><h1 class=\"text-3xl font-bold text-white mb-8\">Settings</h1><div class=\"glass-card rounded-2xl overflow-hidden\" x-data=\"{ tab: 'account' }\"><!-- Tabs Header --><div class=\"flex border-b border-gray-700\"><button @click=\"tab = 'account'\" :class=\"{ 'text-cyan-400 border-cyan-400': tab === 'account', 'text-gray-400 border-transparent hover:text-gray-200': tab !== 'account' }\" class=\"px-6 py-4 text-sm font-medium border-b-2 transition-colors duration-200\">Account</button> <button @click=\"tab = 'notifications'\" :class=\"{ 'text-cyan-400 border-cyan-400': tab === 'notifications', 'text-gray-400 border-transparent hover:text-gray-200': tab !== 'notifications' }\" class=\"px-6 py-4 text-sm font-medium border-b-2 transition-colors duration-200\">Notifications</button> <button @click=\"tab = 'billing'\" :class=\"{ 'text-cyan-400 border-cyan-400': tab === 'billing', 'text-gray-400 border-transparent hover:text-gray-200': tab !== 'billing' }\" class=\"px-6 py-4 text-sm font-medium border-b-2 transition-colors duration-200\">Billing</button> <button @click=\"tab = 'security'\" :class=\"{ 'text-cyan-400 border-cyan-400': tab === 'security', 'text-gray-400 border-transparent hover:text-gray-200': tab !== 'security' }\" class=\"px-6 py-4 text-sm font-medium border-b-2 transition-colors duration-200\">Security</button></div><!-- Account Tab --><div x-show=\"tab === 'account'\" class=\"p-6 space-y-6\"><div class=\"flex items-center space-x-4 mb-8\"><img src=\"")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
 		var templ_7745c5c3_Var3 string
 		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(userInfo.Picture)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/settings.templ`, Line: 45, Col: 32}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/settings.templ`, Line: 52, Col: 32}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 		if templ_7745c5c3_Err != nil {
@@ -83,7 +83,7 @@ func SettingsContent(userInfo layouts.UserInfo, prefs *models.UserPreferences) t
 		var templ_7745c5c3_Var4 string
 		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(userInfo.Name)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/settings.templ`, Line: 45, Col: 54}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/settings.templ`, Line: 52, Col: 54}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 		if templ_7745c5c3_Err != nil {
@@ -96,7 +96,7 @@ func SettingsContent(userInfo layouts.UserInfo, prefs *models.UserPreferences) t
 		var templ_7745c5c3_Var5 string
 		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(userInfo.Name)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/settings.templ`, Line: 47, Col: 66}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/settings.templ`, Line: 54, Col: 66}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 		if templ_7745c5c3_Err != nil {
@@ -109,7 +109,7 @@ func SettingsContent(userInfo layouts.UserInfo, prefs *models.UserPreferences) t
 		var templ_7745c5c3_Var6 string
 		templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(userInfo.Email)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/settings.templ`, Line: 48, Col: 47}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/settings.templ`, Line: 55, Col: 47}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
 		if templ_7745c5c3_Err != nil {
@@ -122,7 +122,7 @@ func SettingsContent(userInfo layouts.UserInfo, prefs *models.UserPreferences) t
 		var templ_7745c5c3_Var7 string
 		templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs(userInfo.Name)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/settings.templ`, Line: 55, Col: 46}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/settings.templ`, Line: 62, Col: 46}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
 		if templ_7745c5c3_Err != nil {
@@ -135,7 +135,7 @@ func SettingsContent(userInfo layouts.UserInfo, prefs *models.UserPreferences) t
 		var templ_7745c5c3_Var8 string
 		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(userInfo.Email)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/settings.templ`, Line: 60, Col: 48}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `templates/pages/settings.templ`, Line: 67, Col: 48}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
 		if templ_7745c5c3_Err != nil {
@@ -221,7 +221,15 @@ func SettingsContent(userInfo layouts.UserInfo, prefs *models.UserPreferences) t
 				return templ_7745c5c3_Err
 			}
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "><div class=\"w-11 h-6 bg-gray-700 peer-focus:outline-none peer-focus:ring-4 peer-focus:ring-cyan-800 rounded-full peer peer-checked:after:translate-x-full peer-checked:after:border-white after:content-[''] after:absolute after:top-[2px] after:left-[2px] after:bg-white after:border-gray-300 after:border after:rounded-full after:h-5 after:w-5 after:transition-all peer-checked:bg-cyan-600\"></div></label></div></div><div class=\"pt-4\"><button type=\"submit\" class=\"px-6 py-2 bg-cyan-600 hover:bg-cyan-500 text-white font-medium rounded-lg transition-colors\">Save Changes</button></div></div></form></div><!-- Billing Tab --><div x-show=\"tab === 'billing'\" class=\"p-6 text-center\"><div class=\"max-w-md mx-auto\"><div class=\"w-16 h-16 bg-gray-700 rounded-full flex items-center justify-center mx-auto mb-4\"><svg class=\"w-8 h-8 text-gray-300\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 10h18M7 15h1m4 0h1m-7 4h12a3 3 0 003-3V8a3 3 0 00-3-3H6a3 3 0 00-3 3v8a3 3 0 003 3z\"></path></svg></div><h3 class=\"text-xl font-semibold text-white mb-2\">Manage Subscription</h3><p class=\"text-gray-400 mb-8\">View your invoices, update payment method, or change your plan via the secure Stripe Customer Portal.</p><form action=\"/settings/billing\" method=\"POST\"><button type=\"submit\" class=\"w-full py-3 px-4 rounded-lg bg-white text-black font-bold hover:bg-gray-200 transition-colors flex items-center justify-center\"><span>Open Customer Portal</span> <svg class=\"w-4 h-4 ml-2\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M10 6H6a2 2 0 00-2 2v10a2 2 0 002 2h10a2 2 0 002-2v-4M14 4h6m0 0v6m0-6L10 14\"></path></svg></button></form></div></div></div></div>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 23, "><div class=\"w-11 h-6 bg-gray-700 peer-focus:outline-none peer-focus:ring-4 peer-focus:ring-cyan-800 rounded-full peer peer-checked:after:translate-x-full peer-checked:after:border-white after:content-[''] after:absolute after:top-[2px] after:left-[2px] after:bg-white after:border-gray-300 after:border after:rounded-full after:h-5 after:w-5 after:transition-all peer-checked:bg-cyan-600\"></div></label></div></div><div class=\"pt-4\"><button type=\"submit\" class=\"px-6 py-2 bg-cyan-600 hover:bg-cyan-500 text-white font-medium rounded-lg transition-colors\">Save Changes</button></div></div></form></div><!-- Billing Tab --><div x-show=\"tab === 'billing'\" class=\"p-6 text-center\"><div class=\"max-w-md mx-auto\"><div class=\"w-16 h-16 bg-gray-700 rounded-full flex items-center justify-center mx-auto mb-4\"><svg class=\"w-8 h-8 text-gray-300\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M3 10h18M7 15h1m4 0h1m-7 4h12a3 3 0 003-3V8a3 3 0 00-3-3H6a3 3 0 00-3 3v8a3 3 0 003 3z\"></path></svg></div><h3 class=\"text-xl font-semibold text-white mb-2\">Manage Subscription</h3><p class=\"text-gray-400 mb-8\">Update your payment method or download invoices via the secure Stripe Customer Portal.</p><form action=\"/settings/billing\" method=\"POST\">")
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = layouts.CSRFField().Render(ctx, templ_7745c5c3_Buffer)
+		if templ_7745c5c3_Err != nil {
+			return templ_7745c5c3_Err
+		}
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 24, "<button type=\"submit\" class=\"w-full py-3 px-4 rounded-lg bg-white text-black font-bold hover:bg-gray-200 transition-colors flex items-center justify-center\"><span>Open Customer Portal</span> <svg class=\"w-4 h-4 ml-2\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M10 6H6a2 2 0 00-2 2v10a2 2 0 002 2h10a2 2 0 002-2v-4M14 4h6m0 0v6m0-6L10 14\"></path></svg></button></form></div><div class=\"mt-10 pt-8 border-t border-gray-700\"><div hx-get=\"/settings/billing/history\" hx-trigger=\"intersect once\" hx-swap=\"outerHTML\"><p class=\"text-gray-500 text-sm\">Loading billing history...</p></div></div></div><!-- Security Tab --><div x-show=\"tab === 'security'\" class=\"p-6\"><h3 class=\"text-lg font-medium text-white mb-1\">Active Sessions</h3><p class=\"text-sm text-gray-400 mb-6\">Devices currently signed in to your account. Revoke any you don't recognise.</p><div hx-get=\"/settings/sessions\" hx-trigger=\"load\" hx-swap=\"outerHTML\"><p class=\"text-gray-500 text-sm\">Loading sessions...</p></div><div class=\"pt-6\"><button onclick=\"logoutEverywhere()\" class=\"px-6 py-2 text-sm text-red-400 border border-red-500/40 hover:bg-red-500/20 rounded-lg transition-colors\">Sign out of all devices</button></div></div></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}